@@ -1,30 +1,56 @@
 package api
 
 import (
-	"github.com/imfact-labs/token-model/digest"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 
 	apic "github.com/imfact-labs/currency-model/api"
 	"github.com/imfact-labs/currency-model/common"
+	cdigest "github.com/imfact-labs/currency-model/digest"
 	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/token-model/digest"
 	"github.com/imfact-labs/token-model/types"
 )
 
 var (
-	HandlerPathToken        = `/token/{contract:(?i)` + ctypes.REStringAddressString + `}`
-	HandlerPathTokenBalance = `/token/{contract:(?i)` + ctypes.REStringAddressString + `}/account/{address:(?i)` + ctypes.REStringAddressString + `}` // revive:disable-line:line-length-limit
+	HandlerPathToken              = `/token/{contract:(?i)` + ctypes.REStringAddressString + `}`
+	HandlerPathTokenBalance       = `/token/{contract:(?i)` + ctypes.REStringAddressString + `}/account/{address:(?i)` + ctypes.REStringAddressString + `}` // revive:disable-line:line-length-limit
+	HandlerPathTokenSupplyHistory = `/token/{contract:(?i)` + ctypes.REStringAddressString + `}/supply/history`
+	HandlerPathTokenStats         = `/token/{contract:(?i)` + ctypes.REStringAddressString + `}/stats`
+)
+
+var (
+	defaultTokenStatsTop  = 10
+	defaultTokenStatsDays = 7
+	maxTokenStatsDays     = 90
 )
 
 func SetHandlers(hd *apic.Handlers) {
 	get := 1000
 	_ = hd.SetHandler(HandlerPathTokenBalance, HandleTokenBalance, true, get, get).
 		Methods(http.MethodOptions, "GET")
+	_ = hd.SetHandler(HandlerPathTokenSupplyHistory, HandleTokenSupplyHistory, true, get, get).
+		Methods(http.MethodOptions, "GET")
+	_ = hd.SetHandler(HandlerPathTokenStats, HandleTokenStats, true, get, get).
+		Methods(http.MethodOptions, "GET")
+	_ = hd.SetHandler(HandlerPathTokenSymbol, HandleTokenSymbol, true, get, get).
+		Methods(http.MethodOptions, "GET")
 	_ = hd.SetHandler(HandlerPathToken, HandleToken, true, get, get).
 		Methods(http.MethodOptions, "GET")
 }
 
 func HandleToken(hd *apic.Handlers, w http.ResponseWriter, r *http.Request) {
-	cachekey := apic.CacheKeyPath(r)
+	height, err := parseHeightQuery(r.URL.Query().Get("height"))
+	if err != nil {
+		apic.HTTP2ProblemWithError(w, err, http.StatusBadRequest)
+
+		return
+	}
+
+	cachekey := apic.CacheKey(apic.CacheKeyPath(r), stringHeightQuery(height))
 	if err := apic.LoadFromCache(hd.Cache(), cachekey, w); err == nil {
 		return
 	}
@@ -37,7 +63,7 @@ func HandleToken(hd *apic.Handlers, w http.ResponseWriter, r *http.Request) {
 	}
 
 	if v, err, shared := hd.RG().Do(cachekey, func() (interface{}, error) {
-		return handleTokenInGroup(hd, contract)
+		return handleTokenInGroup(hd, contract, height)
 	}); err != nil {
 		apic.HTTP2HandleError(w, err)
 	} else {
@@ -48,12 +74,12 @@ func HandleToken(hd *apic.Handlers, w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-func handleTokenInGroup(hd *apic.Handlers, contract string) (interface{}, error) {
-	switch design, err := digest.Token(hd.Database(), contract); {
+func handleTokenInGroup(hd *apic.Handlers, contract string, height base.Height) (interface{}, error) {
+	switch design, err := digest.Token(hd.Database(), contract, height); {
 	case err != nil:
 		return nil, err
 	default:
-		hal, err := buildTokenHal(hd, contract, *design)
+		hal, err := buildTokenHal(hd, contract, *design, height)
 		if err != nil {
 			return nil, err
 		}
@@ -61,11 +87,12 @@ func handleTokenInGroup(hd *apic.Handlers, contract string) (interface{}, error)
 	}
 }
 
-func buildTokenHal(hd *apic.Handlers, contract string, design types.Design) (apic.Hal, error) {
+func buildTokenHal(hd *apic.Handlers, contract string, design types.Design, height base.Height) (apic.Hal, error) {
 	h, err := hd.CombineURL(HandlerPathToken, "contract", contract)
 	if err != nil {
 		return nil, err
 	}
+	h = apic.AddQueryValue(h, stringHeightQuery(height))
 
 	hal := apic.NewBaseHal(design, apic.NewHalLink(h, nil))
 
@@ -73,7 +100,14 @@ func buildTokenHal(hd *apic.Handlers, contract string, design types.Design) (api
 }
 
 func HandleTokenBalance(hd *apic.Handlers, w http.ResponseWriter, r *http.Request) {
-	cachekey := apic.CacheKeyPath(r)
+	height, err := parseHeightQuery(r.URL.Query().Get("height"))
+	if err != nil {
+		apic.HTTP2ProblemWithError(w, err, http.StatusBadRequest)
+
+		return
+	}
+
+	cachekey := apic.CacheKey(apic.CacheKeyPath(r), stringHeightQuery(height))
 	if err := apic.LoadFromCache(hd.Cache(), cachekey, w); err == nil {
 		return
 	}
@@ -93,7 +127,7 @@ func HandleTokenBalance(hd *apic.Handlers, w http.ResponseWriter, r *http.Reques
 	}
 
 	if v, err, shared := hd.RG().Do(cachekey, func() (interface{}, error) {
-		return handleTokenBalanceInGroup(hd, contract, account)
+		return handleTokenBalanceInGroup(hd, contract, account, height)
 	}); err != nil {
 		apic.HTTP2HandleError(w, err)
 	} else {
@@ -104,12 +138,12 @@ func HandleTokenBalance(hd *apic.Handlers, w http.ResponseWriter, r *http.Reques
 	}
 }
 
-func handleTokenBalanceInGroup(hd *apic.Handlers, contract, account string) (interface{}, error) {
-	switch amount, err := digest.TokenBalance(hd.Database(), contract, account); {
+func handleTokenBalanceInGroup(hd *apic.Handlers, contract, account string, height base.Height) (interface{}, error) {
+	switch amount, err := digest.TokenBalance(hd.Database(), contract, account, height); {
 	case err != nil:
 		return nil, err
 	default:
-		hal, err := buildTokenBalanceHal(hd, contract, account, amount)
+		hal, err := buildTokenBalanceHal(hd, contract, account, amount, height)
 		if err != nil {
 			return nil, err
 		}
@@ -117,7 +151,9 @@ func handleTokenBalanceInGroup(hd *apic.Handlers, contract, account string) (int
 	}
 }
 
-func buildTokenBalanceHal(hd *apic.Handlers, contract, account string, amount *common.Big) (apic.Hal, error) {
+func buildTokenBalanceHal(
+	hd *apic.Handlers, contract, account string, amount *common.Big, height base.Height,
+) (apic.Hal, error) {
 	var hal apic.Hal
 
 	if amount == nil {
@@ -127,6 +163,7 @@ func buildTokenBalanceHal(hd *apic.Handlers, contract, account string, amount *c
 		if err != nil {
 			return nil, err
 		}
+		h = apic.AddQueryValue(h, stringHeightQuery(height))
 
 		hal = apic.NewBaseHal(struct {
 			Amount common.Big `json:"amount"`
@@ -135,3 +172,237 @@ func buildTokenBalanceHal(hd *apic.Handlers, contract, account string, amount *c
 
 	return hal, nil
 }
+
+func HandleTokenSupplyHistory(hd *apic.Handlers, w http.ResponseWriter, r *http.Request) {
+	limit := apic.ParseLimitQuery(r.URL.Query().Get("limit"))
+	reverse := apic.ParseBoolQuery(r.URL.Query().Get("reverse"))
+	offset, err := parseHeightQuery(r.URL.Query().Get("offset"))
+	if err != nil {
+		apic.HTTP2ProblemWithError(w, err, http.StatusBadRequest)
+
+		return
+	}
+
+	if limit < 1 {
+		limit = hd.ItemsLimiter("token-supply-history")
+	}
+	limit = digest.NormalizeLimit(limit)
+
+	cachekey := apic.CacheKey(
+		apic.CacheKeyPath(r), stringOffsetQuery(offset), apic.StringBoolQuery("reverse", reverse),
+		stringLimitQuery(limit),
+	)
+	if err := apic.LoadFromCache(hd.Cache(), cachekey, w); err == nil {
+		return
+	}
+
+	contract, err, status := apic.ParseRequest(w, r, "contract")
+	if err != nil {
+		apic.HTTP2ProblemWithError(w, err, status)
+
+		return
+	}
+
+	if v, err, shared := hd.RG().Do(cachekey, func() (interface{}, error) {
+		return handleTokenSupplyHistoryInGroup(hd, contract, offset, reverse, limit)
+	}); err != nil {
+		apic.HTTP2HandleError(w, err)
+	} else {
+		apic.HTTP2WriteHalBytes(hd.Encoder(), w, v.([]byte), http.StatusOK)
+		if !shared {
+			apic.HTTP2WriteCache(w, cachekey, hd.ExpireShortLived())
+		}
+	}
+}
+
+func handleTokenSupplyHistoryInGroup(
+	hd *apic.Handlers, contract string, offset base.Height, reverse bool, limit int64,
+) (interface{}, error) {
+	var vas []digest.TokenSupply
+	if err := digest.TokenSupplyHistory(
+		hd.Database(), contract, offset, reverse, limit,
+		func(va digest.TokenSupply) (bool, error) {
+			vas = append(vas, va)
+
+			return true, nil
+		},
+	); err != nil {
+		return nil, err
+	}
+
+	hal, err := buildTokenSupplyHistoryHal(hd, contract, vas, offset, reverse, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return hd.Encoder().Marshal(hal)
+}
+
+func buildTokenSupplyHistoryHal(
+	hd *apic.Handlers, contract string, vas []digest.TokenSupply, offset base.Height, reverse bool, limit int64,
+) (apic.Hal, error) {
+	if len(vas) < 1 {
+		return apic.NewEmptyHal(), nil
+	}
+
+	baseSelf, err := hd.CombineURL(HandlerPathTokenSupplyHistory, "contract", contract)
+	if err != nil {
+		return nil, err
+	}
+
+	self := apic.AddQueryValue(baseSelf, stringOffsetQuery(offset))
+	self = apic.AddQueryValue(self, apic.StringBoolQuery("reverse", reverse))
+	self = apic.AddQueryValue(self, stringLimitQuery(limit))
+
+	var hal apic.Hal
+	hal = apic.NewBaseHal(vas, apic.NewHalLink(self, nil))
+
+	h, err := hd.CombineURL(HandlerPathToken, "contract", contract)
+	if err != nil {
+		return nil, err
+	}
+	hal = hal.AddLink("token", apic.NewHalLink(h, nil))
+
+	next := apic.AddQueryValue(baseSelf, stringOffsetQuery(vas[len(vas)-1].Height))
+	next = apic.AddQueryValue(next, apic.StringBoolQuery("reverse", reverse))
+	next = apic.AddQueryValue(next, stringLimitQuery(limit))
+	hal = hal.AddLink("next", apic.NewHalLink(next, nil))
+
+	hal = hal.AddLink(
+		"reverse", apic.NewHalLink(apic.AddQueryValue(baseSelf, apic.StringBoolQuery("reverse", !reverse)), nil),
+	)
+
+	return hal, nil
+}
+
+func HandleTokenStats(hd *apic.Handlers, w http.ResponseWriter, r *http.Request) {
+	top, err := parseCountQuery(r.URL.Query().Get("top"), defaultTokenStatsTop, digest.MaxTokenStatsTop)
+	if err != nil {
+		apic.HTTP2ProblemWithError(w, err, http.StatusBadRequest)
+
+		return
+	}
+
+	days, err := parseCountQuery(r.URL.Query().Get("days"), defaultTokenStatsDays, maxTokenStatsDays)
+	if err != nil {
+		apic.HTTP2ProblemWithError(w, err, http.StatusBadRequest)
+
+		return
+	}
+
+	cachekey := apic.CacheKey(apic.CacheKeyPath(r), fmt.Sprintf("top=%d", top), fmt.Sprintf("days=%d", days))
+	if err := apic.LoadFromCache(hd.Cache(), cachekey, w); err == nil {
+		return
+	}
+
+	contract, err, status := apic.ParseRequest(w, r, "contract")
+	if err != nil {
+		apic.HTTP2ProblemWithError(w, err, status)
+
+		return
+	}
+
+	if v, err, shared := hd.RG().Do(cachekey, func() (interface{}, error) {
+		return handleTokenStatsInGroup(hd, contract, top, days)
+	}); err != nil {
+		apic.HTTP2HandleError(w, err)
+	} else {
+		apic.HTTP2WriteHalBytes(hd.Encoder(), w, v.([]byte), http.StatusOK)
+		if !shared {
+			apic.HTTP2WriteCache(w, cachekey, hd.ExpireShortLived())
+		}
+	}
+}
+
+func handleTokenStatsInGroup(hd *apic.Handlers, contract string, top, days int) (interface{}, error) {
+	switch stats, err := digest.TokenStatistics(hd.Database(), contract, top, int64(days)); {
+	case err != nil:
+		return nil, err
+	default:
+		hal, err := buildTokenStatsHal(hd, contract, *stats, top, days)
+		if err != nil {
+			return nil, err
+		}
+		return hd.Encoder().Marshal(hal)
+	}
+}
+
+func buildTokenStatsHal(
+	hd *apic.Handlers, contract string, stats digest.TokenStats, top, days int,
+) (apic.Hal, error) {
+	h, err := hd.CombineURL(HandlerPathTokenStats, "contract", contract)
+	if err != nil {
+		return nil, err
+	}
+	h = apic.AddQueryValue(h, fmt.Sprintf("top=%d", top))
+	h = apic.AddQueryValue(h, fmt.Sprintf("days=%d", days))
+
+	var hal apic.Hal
+	hal = apic.NewBaseHal(stats, apic.NewHalLink(h, nil))
+
+	th, err := hd.CombineURL(HandlerPathToken, "contract", contract)
+	if err != nil {
+		return nil, err
+	}
+	hal = hal.AddLink("token", apic.NewHalLink(th, nil))
+
+	return hal, nil
+}
+
+// parseCountQuery parses a positive count; empty string gives d and a count
+// over max, if max is over 0, is cut to max.
+func parseCountQuery(s string, d, max int) (int, error) {
+	s = strings.TrimSpace(s)
+	if len(s) < 1 {
+		return d, nil
+	}
+
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 1 {
+		return 0, cdigest.ErrBadRequest.Errorf("invalid count, %q", s)
+	}
+
+	if max > 0 && n > max {
+		return max, nil
+	}
+
+	return n, nil
+}
+
+func parseHeightQuery(s string) (base.Height, error) {
+	s = strings.TrimSpace(s)
+	if len(s) < 1 {
+		return base.NilHeight, nil
+	}
+
+	height, err := base.ParseHeightString(s)
+	if err != nil {
+		return base.NilHeight, cdigest.ErrBadRequest.Errorf("invalid height, %q: %v", s, err)
+	}
+
+	return height, nil
+}
+
+func stringHeightQuery(height base.Height) string {
+	if height <= base.NilHeight {
+		return ""
+	}
+
+	return fmt.Sprintf("height=%d", height)
+}
+
+func stringOffsetQuery(offset base.Height) string {
+	if offset <= base.NilHeight {
+		return ""
+	}
+
+	return apic.StringOffsetQuery(offset.String())
+}
+
+func stringLimitQuery(limit int64) string {
+	if limit < 1 {
+		return ""
+	}
+
+	return fmt.Sprintf("limit=%d", limit)
+}
@@ -0,0 +1,440 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	apic "github.com/imfact-labs/currency-model/api"
+	cdigest "github.com/imfact-labs/currency-model/digest"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/token-model/digest"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+var HandlerPathTokenEvents = `/token/{contract:(?i)` + ctypes.REStringAddressString + `}/events`
+
+var (
+	tokenEventsPollInterval            = time.Second
+	tokenEventsKeepAliveInterval       = time.Second * 15
+	tokenEventsRowsLimit         int64 = 500
+	tokenEventsBufferSize              = 1 << 10
+)
+
+// SetTokenEventHandler sets the handler of the token event streams; the
+// streams are notified by the digester of the node through notifier. Without
+// notifier, the new events are polled from the digest database.
+func SetTokenEventHandler(hd *apic.Handlers, notifier *digest.TokenEventNotifier) {
+	get := 1000
+	p := newTokenEventPoller(hd, notifier)
+
+	_ = hd.SetHandler(HandlerPathTokenEvents, p.HandleTokenEvents, false, get, get).
+		Methods(http.MethodOptions, "GET")
+}
+
+// HandleTokenEvents streams the token events of contract as server-sent
+// events. The event id is "<height>:<index>" of the operation, so clients
+// resume with the Last-Event-ID header without losing the rest of a block, or
+// with the height query after a block; without them, the stream starts after
+// the last digested block. The account query keeps only the events touching
+// the account.
+func (p *tokenEventPoller) HandleTokenEvents(hd *apic.Handlers, w http.ResponseWriter, r *http.Request) {
+	cursor, err := parseTokenEventStart(r.Header.Get("Last-Event-ID"), r.URL.Query().Get("height"))
+	if err != nil {
+		apic.HTTP2ProblemWithError(w, err, http.StatusBadRequest)
+
+		return
+	}
+
+	contract, err, status := apic.ParseRequest(w, r, "contract")
+	if err != nil {
+		apic.HTTP2ProblemWithError(w, err, status)
+
+		return
+	}
+
+	account := strings.TrimSpace(r.URL.Query().Get("account"))
+	if len(account) > 0 {
+		if _, err := base.DecodeAddress(account, hd.Encoder()); err != nil {
+			apic.HTTP2ProblemWithError(w, err, http.StatusBadRequest)
+
+			return
+		}
+	}
+
+	if cursor.Height <= base.NilHeight {
+		top, err := digest.TokenEventTopHeight(hd.Database(), []string{contract})
+		if err != nil {
+			apic.HTTP2HandleError(w, err)
+
+			return
+		}
+		cursor = tokenEventCursor{Height: top, Index: math.MaxUint64}
+	}
+
+	rc := http.NewResponseController(w)
+	// NOTE the stream lives longer than the write timeout of api server
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	s := &tokenEventStream{
+		hd:       hd,
+		poller:   p,
+		w:        w,
+		rc:       rc,
+		contract: contract,
+		account:  account,
+		cursor:   cursor,
+	}
+
+	if err := s.run(r.Context()); err != nil {
+		hd.Debug().Err(err).Str("contract", contract).Msg("token event stream closed")
+	}
+}
+
+// tokenEventCursor points the last sent event of a stream.
+type tokenEventCursor struct {
+	Height base.Height
+	Index  uint64
+}
+
+func (c tokenEventCursor) sent(ev digest.TokenEvent) bool {
+	if ev.Height != c.Height {
+		return ev.Height < c.Height
+	}
+
+	return ev.Index <= c.Index
+}
+
+// parseTokenEventID parses the event id, "<height>:<index>"; a height without
+// index means the whole block is sent and empty id returns the cursor of
+// base.NilHeight.
+func parseTokenEventID(s string) (tokenEventCursor, error) {
+	s = strings.TrimSpace(s)
+	if len(s) < 1 {
+		return tokenEventCursor{Height: base.NilHeight}, nil
+	}
+
+	h, i, found := strings.Cut(s, ":")
+
+	height, err := base.ParseHeightString(h)
+	if err != nil {
+		return tokenEventCursor{}, cdigest.ErrBadRequest.Errorf("invalid event id, %q", s)
+	}
+
+	if !found {
+		return tokenEventCursor{Height: height, Index: math.MaxUint64}, nil
+	}
+
+	index, err := strconv.ParseUint(i, 10, 64)
+	if err != nil {
+		return tokenEventCursor{}, cdigest.ErrBadRequest.Errorf("invalid event id, %q", s)
+	}
+
+	return tokenEventCursor{Height: height, Index: index}, nil
+}
+
+// parseTokenEventStart returns the cursor to resume the stream; the event id
+// is used before the height query. Without them, the cursor is
+// base.NilHeight.
+func parseTokenEventStart(id, height string) (tokenEventCursor, error) {
+	switch cursor, err := parseTokenEventID(id); {
+	case err != nil:
+		return tokenEventCursor{}, err
+	case cursor.Height > base.NilHeight:
+		return cursor, nil
+	}
+
+	h, err := parseHeightQuery(height)
+	if err != nil {
+		return tokenEventCursor{}, err
+	}
+
+	return tokenEventCursor{Height: h, Index: math.MaxUint64}, nil
+}
+
+type tokenEventStream struct {
+	hd        *apic.Handlers
+	poller    *tokenEventPoller
+	w         http.ResponseWriter
+	rc        *http.ResponseController
+	contract  string
+	account   string
+	cursor    tokenEventCursor
+	lastWrite time.Time
+}
+
+// run sends the events after the cursor from the digest database until it
+// catches up, and then the events of the shared poller. The database is read
+// once more after subscribing, so the events polled in the meantime are not
+// lost; the events already sent are skipped by the cursor.
+func (s *tokenEventStream) run(ctx context.Context) error {
+	s.lastWrite = time.Now()
+
+	if err := s.catchUp(); err != nil {
+		return err
+	}
+
+	sub := s.poller.subscribe(s.contract)
+	defer s.poller.unsubscribe(sub)
+
+	if err := s.catchUp(); err != nil {
+		return err
+	}
+
+	keepalive := time.NewTicker(tokenEventsPollInterval)
+	defer keepalive.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case ev, ok := <-sub.ch:
+			if !ok {
+				return errors.Errorf("too slow to receive token events")
+			}
+
+			if err := s.send(ev); err != nil {
+				return err
+			}
+
+			if err := s.rc.Flush(); err != nil {
+				return err
+			}
+		case <-keepalive.C:
+			if time.Since(s.lastWrite) < tokenEventsKeepAliveInterval {
+				continue
+			}
+
+			if _, err := fmt.Fprint(s.w, ": keepalive\n\n"); err != nil {
+				return err
+			}
+
+			if err := s.rc.Flush(); err != nil {
+				return err
+			}
+
+			s.lastWrite = time.Now()
+		}
+	}
+}
+
+func (s *tokenEventStream) catchUp() error {
+	offset := s.cursor.Height
+	if s.cursor.Index != math.MaxUint64 {
+		offset--
+	}
+
+	for {
+		var werr error
+
+		last, err := digest.TokenEvents(
+			s.hd.Database(), []string{s.contract}, offset, tokenEventsRowsLimit,
+			func(ev digest.TokenEvent) (bool, error) {
+				if werr = s.send(ev); werr != nil {
+					return false, nil
+				}
+
+				return true, nil
+			},
+		)
+
+		switch {
+		case err != nil:
+			return err
+		case werr != nil:
+			return werr
+		case last <= offset:
+			return nil
+		}
+
+		offset = last
+
+		if err := s.rc.Flush(); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *tokenEventStream) send(ev digest.TokenEvent) error {
+	if s.cursor.sent(ev) {
+		return nil
+	}
+
+	s.cursor = tokenEventCursor{Height: ev.Height, Index: ev.Index}
+
+	if len(s.account) > 0 && !ev.Touches(s.account) {
+		return nil
+	}
+
+	b, err := s.hd.Encoder().Marshal(ev)
+	if err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintf(s.w, "id: %d:%d\nevent: %s\ndata: %s\n\n", ev.Height, ev.Index, ev.Type, b); err != nil {
+		return err
+	}
+
+	s.lastWrite = time.Now()
+
+	return nil
+}
+
+type tokenEventSubscriber struct {
+	contract string
+	ch       chan digest.TokenEvent
+}
+
+// tokenEventPoller reads the new token events of every contract once for all
+// the streams and passes them to the subscribers of their contract. The
+// subscriber which does not receive in time is closed, and the stream resumes
+// by the event id after reconnecting. The poller runs while there are
+// subscribers and reads the events whenever the digester notifies the new
+// block.
+type tokenEventPoller struct {
+	sync.Mutex
+	st          *cdigest.Database
+	log         *zerolog.Logger
+	notifier    *digest.TokenEventNotifier
+	subscribers map[*tokenEventSubscriber]struct{}
+	cancel      func()
+}
+
+func newTokenEventPoller(hd *apic.Handlers, notifier *digest.TokenEventNotifier) *tokenEventPoller {
+	return &tokenEventPoller{
+		st:          hd.Database(),
+		log:         hd.Logger,
+		notifier:    notifier,
+		subscribers: map[*tokenEventSubscriber]struct{}{},
+	}
+}
+
+func (p *tokenEventPoller) subscribe(contract string) *tokenEventSubscriber {
+	sub := &tokenEventSubscriber{
+		contract: contract,
+		ch:       make(chan digest.TokenEvent, tokenEventsBufferSize),
+	}
+
+	p.Lock()
+	defer p.Unlock()
+
+	p.subscribers[sub] = struct{}{}
+
+	if p.cancel == nil {
+		ctx, cancel := context.WithCancel(context.Background())
+		p.cancel = cancel
+
+		go p.poll(ctx)
+	}
+
+	return sub
+}
+
+func (p *tokenEventPoller) unsubscribe(sub *tokenEventSubscriber) {
+	p.Lock()
+	defer p.Unlock()
+
+	if _, found := p.subscribers[sub]; found {
+		p.remove(sub)
+	}
+}
+
+// remove closes sub and stops polling when no subscribers are left.
+func (p *tokenEventPoller) remove(sub *tokenEventSubscriber) {
+	delete(p.subscribers, sub)
+	close(sub.ch)
+
+	if len(p.subscribers) < 1 && p.cancel != nil {
+		p.cancel()
+		p.cancel = nil
+	}
+}
+
+func (p *tokenEventPoller) poll(ctx context.Context) {
+	var notified <-chan base.Height
+	var ticked <-chan time.Time
+
+	if p.notifier != nil {
+		ch, stop := p.notifier.Listen()
+		defer stop()
+
+		notified = ch
+	} else {
+		ticker := time.NewTicker(tokenEventsPollInterval)
+		defer ticker.Stop()
+
+		ticked = ticker.C
+	}
+
+	// NOTE the events until the last digested block are read by the streams
+	// after subscribing.
+	offset := p.st.LastBlock()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-notified:
+		case <-ticked:
+		}
+
+		offset = p.read(offset)
+	}
+}
+
+// read publishes the events after offset and returns the last height read.
+func (p *tokenEventPoller) read(offset base.Height) base.Height {
+	for {
+		last, err := digest.TokenEvents(p.st, nil, offset, tokenEventsRowsLimit,
+			func(ev digest.TokenEvent) (bool, error) {
+				p.publish(ev)
+
+				return true, nil
+			},
+		)
+		if err != nil {
+			p.log.Error().Err(err).Msg("failed to poll token events")
+
+			return offset
+		}
+
+		if last <= offset {
+			return offset
+		}
+
+		offset = last
+	}
+}
+
+func (p *tokenEventPoller) publish(ev digest.TokenEvent) {
+	p.Lock()
+	defer p.Unlock()
+
+	for sub := range p.subscribers {
+		if sub.contract != ev.Contract {
+			continue
+		}
+
+		select {
+		case sub.ch <- ev:
+		default:
+			p.remove(sub)
+		}
+	}
+}
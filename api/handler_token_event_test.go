@@ -0,0 +1,175 @@
+package api
+
+import (
+	"math"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	apic "github.com/imfact-labs/currency-model/api"
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/mitum2/base"
+	jsonenc "github.com/imfact-labs/mitum2/util/encoder/json"
+	"github.com/imfact-labs/token-model/digest"
+)
+
+func TestParseTokenEventStart(t *testing.T) {
+	cases := []struct {
+		name     string
+		id       string
+		height   string
+		expected tokenEventCursor
+		err      bool
+	}{
+		{name: "empty", expected: tokenEventCursor{Height: base.NilHeight, Index: math.MaxUint64}},
+		{name: "height", height: "3", expected: tokenEventCursor{Height: 3, Index: math.MaxUint64}},
+		{name: "event id", id: "3:1", expected: tokenEventCursor{Height: 3, Index: 1}},
+		{name: "event id without index", id: "3", expected: tokenEventCursor{Height: 3, Index: math.MaxUint64}},
+		{name: "event id before height", id: "3:1", height: "5", expected: tokenEventCursor{Height: 3, Index: 1}},
+		{name: "invalid event id", id: "3:a", err: true},
+		{name: "invalid height", height: "a", err: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cursor, err := parseTokenEventStart(c.id, c.height)
+
+			switch {
+			case c.err:
+				if err == nil {
+					t.Fatal("expected error, but nil")
+				}
+
+				return
+			case err != nil:
+				t.Fatalf("parse: %v", err)
+			}
+
+			if cursor != c.expected {
+				t.Fatalf("cursor not matched; expected=%+v cursor=%+v", c.expected, cursor)
+			}
+		})
+	}
+}
+
+func TestTokenEventStreamSend(t *testing.T) {
+	events := []digest.TokenEvent{
+		newTestTokenEvent(3, 0, "a"),
+		newTestTokenEvent(3, 1, "b"),
+		newTestTokenEvent(3, 2, "a"),
+		newTestTokenEvent(4, 0, "b"),
+		newTestTokenEvent(5, 0, "a", "b"),
+	}
+
+	cases := []struct {
+		name     string
+		id       string
+		height   string
+		account  string
+		expected []string
+	}{
+		{name: "all", expected: []string{"3:0", "3:1", "3:2", "4:0", "5:0"}},
+		{name: "resume from height", height: "3", expected: []string{"4:0", "5:0"}},
+		{name: "resume from event id", id: "3:1", expected: []string{"3:2", "4:0", "5:0"}},
+		{name: "account", account: "a", expected: []string{"3:0", "3:2", "5:0"}},
+		{name: "resume account from event id", id: "3:0", account: "b", expected: []string{"3:1", "4:0", "5:0"}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cursor, err := parseTokenEventStart(c.id, c.height)
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+
+			hd := &apic.Handlers{}
+			hd.SetEncoder(jsonenc.NewEncoder())
+
+			w := httptest.NewRecorder()
+			s := &tokenEventStream{hd: hd, w: w, account: c.account, cursor: cursor}
+
+			for i := range events {
+				if err := s.send(events[i]); err != nil {
+					t.Fatalf("send: %v", err)
+				}
+			}
+
+			var ids []string
+			for _, line := range strings.Split(w.Body.String(), "\n") {
+				if id, found := strings.CutPrefix(line, "id: "); found {
+					ids = append(ids, id)
+				}
+			}
+
+			if strings.Join(ids, ",") != strings.Join(c.expected, ",") {
+				t.Fatalf("events not matched; expected=%v events=%v", c.expected, ids)
+			}
+
+			last := events[len(events)-1]
+			if s.cursor != (tokenEventCursor{Height: last.Height, Index: last.Index}) {
+				t.Fatalf("cursor not moved to the last event; cursor=%+v", s.cursor)
+			}
+		})
+	}
+}
+
+func TestTokenEventPollerSubscribers(t *testing.T) {
+	p := &tokenEventPoller{subscribers: map[*tokenEventSubscriber]struct{}{}}
+
+	var stopped bool
+	// NOTE the poller is regarded as running, so subscribe does not start
+	// polling.
+	p.cancel = func() { stopped = true }
+
+	a := p.subscribe("contract-a")
+	b := p.subscribe("contract-b")
+
+	p.publish(newTestTokenEvent(3, 0, "x"))
+
+	ev := newTestTokenEvent(3, 1, "x")
+	ev.Contract = "contract-a"
+	p.publish(ev)
+
+	if len(b.ch) != 0 {
+		t.Fatalf("events of other contract published; %d", len(b.ch))
+	}
+
+	switch len(a.ch) {
+	case 1:
+		if got := <-a.ch; got.Index != 1 {
+			t.Fatalf("wrong event published; %+v", got)
+		}
+	default:
+		t.Fatalf("event of contract not published; %d", len(a.ch))
+	}
+
+	for i := 0; i < tokenEventsBufferSize+1; i++ {
+		p.publish(ev)
+	}
+
+	if _, found := p.subscribers[a]; found {
+		t.Fatal("slow subscriber not removed")
+	}
+
+	if stopped {
+		t.Fatal("stopped with subscriber")
+	}
+
+	p.unsubscribe(b)
+
+	if !stopped || p.cancel != nil {
+		t.Fatal("not stopped without subscribers")
+	}
+}
+
+func newTestTokenEvent(height base.Height, index uint64, addresses ...string) digest.TokenEvent {
+	ev := digest.TokenEvent{Height: height, Index: index, Type: "transfer"}
+
+	for i := range addresses {
+		ev.Changes = append(ev.Changes, digest.TokenBalanceChange{
+			Address: addresses[i], Amount: common.NewBig(1), Delta: common.NewBig(1),
+		})
+	}
+
+	return ev
+}
@@ -0,0 +1,68 @@
+package api
+
+import (
+	"net/http"
+
+	apic "github.com/imfact-labs/currency-model/api"
+	"github.com/imfact-labs/token-model/digest"
+)
+
+var HandlerPathTokenSymbol = `/token/symbol/{symbol:[A-Z0-9][A-Z0-9_\.\!\$\*\@]*[A-Z0-9]}`
+
+// HandleTokenSymbol returns the contract account holding the token symbol.
+// A released symbol is kept with its last holder until it is registered again.
+func HandleTokenSymbol(hd *apic.Handlers, w http.ResponseWriter, r *http.Request) {
+	cachekey := apic.CacheKeyPath(r)
+	if err := apic.LoadFromCache(hd.Cache(), cachekey, w); err == nil {
+		return
+	}
+
+	symbol, err, status := apic.ParseRequest(w, r, "symbol")
+	if err != nil {
+		apic.HTTP2ProblemWithError(w, err, status)
+
+		return
+	}
+
+	if v, err, shared := hd.RG().Do(cachekey, func() (interface{}, error) {
+		return handleTokenSymbolInGroup(hd, symbol)
+	}); err != nil {
+		apic.HTTP2HandleError(w, err)
+	} else {
+		apic.HTTP2WriteHalBytes(hd.Encoder(), w, v.([]byte), http.StatusOK)
+		if !shared {
+			apic.HTTP2WriteCache(w, cachekey, hd.ExpireShortLived())
+		}
+	}
+}
+
+func handleTokenSymbolInGroup(hd *apic.Handlers, symbol string) (interface{}, error) {
+	switch entry, err := digest.TokenSymbol(hd.Database(), symbol); {
+	case err != nil:
+		return nil, err
+	default:
+		hal, err := buildTokenSymbolHal(hd, *entry)
+		if err != nil {
+			return nil, err
+		}
+		return hd.Encoder().Marshal(hal)
+	}
+}
+
+func buildTokenSymbolHal(hd *apic.Handlers, entry digest.TokenSymbolEntry) (apic.Hal, error) {
+	h, err := hd.CombineURL(HandlerPathTokenSymbol, "symbol", entry.Symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	var hal apic.Hal
+	hal = apic.NewBaseHal(entry, apic.NewHalLink(h, nil))
+
+	th, err := hd.CombineURL(HandlerPathToken, "contract", entry.Contract)
+	if err != nil {
+		return nil, err
+	}
+	hal = hal.AddLink("token", apic.NewHalLink(th, nil))
+
+	return hal, nil
+}
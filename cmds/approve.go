@@ -13,9 +13,10 @@ import (
 
 type ApproveCommand struct {
 	OperationCommand
-	Approved1 ccmds.AddressFlag `arg:"" name:"approved" help:"approved account" required:"true"`
-	Approved2 ccmds.AddressFlag `arg:"" name:"approved" help:"approved account" required:"true"`
-	Amount    ccmds.BigFlag     `arg:"" name:"amount" help:"amount to approve" required:"true"`
+	ItemsFileFlag
+	Approved1 ccmds.AddressFlag `arg:"" name:"approved" help:"approved account" optional:""`
+	Approved2 ccmds.AddressFlag `arg:"" name:"approved" help:"approved account" optional:""`
+	Amount    string            `arg:"" name:"amount" help:"amount to approve; raw units, or token units with --human or symbol suffix like \"12.5MCT\"" optional:""`
 	approved1 base.Address
 	approved2 base.Address
 }
@@ -29,6 +30,17 @@ func (cmd *ApproveCommand) Run(pctx context.Context) error { // nolint:dupl
 		return err
 	}
 
+	if cmd.IsSet() {
+		ops, err := cmd.createOperations()
+		if err != nil {
+			return err
+		}
+
+		ccmds.PrettyPrint(cmd.Out, ops)
+
+		return nil
+	}
+
 	op, err := cmd.createOperation()
 	if err != nil {
 		return err
@@ -44,6 +56,15 @@ func (cmd *ApproveCommand) parseFlags() error {
 		return err
 	}
 
+	switch {
+	case cmd.IsSet() && (len(cmd.Approved1.String()) > 0 || len(cmd.Approved2.String()) > 0):
+		return errors.Errorf("approved and amount can not be used with --items-file")
+	case cmd.IsSet():
+		return nil
+	case len(cmd.Approved2.String()) < 1 || len(cmd.Amount) < 1:
+		return errors.Errorf("expected approved, approved and amount, or --items-file")
+	}
+
 	approved, err := cmd.Approved1.Encode(cmd.Encoders.JSON())
 	if err != nil {
 		return errors.Wrapf(err, "invalid approved format, %q", cmd.Approved1.String())
@@ -62,20 +83,72 @@ func (cmd *ApproveCommand) parseFlags() error {
 func (cmd *ApproveCommand) createOperation() (base.Operation, error) { // nolint:dupl}
 	e := util.StringError(utils.ErrStringCreate("approve operation"))
 
+	amount, err := cmd.amount(cmd.Amount)
+	if err != nil {
+		return nil, e.Wrap(err)
+	}
+
 	item1 := token.NewApproveItem(cmd.contract,
-		cmd.approved1, cmd.Amount.Big)
+		cmd.approved1, amount)
 
 	item2 := token.NewApproveItem(cmd.contract,
-		cmd.approved2, cmd.Amount.Big)
+		cmd.approved2, amount)
 
 	fact := token.NewApproveFact(
 		[]byte(cmd.Token), cmd.sender, []token.ApproveItem{item1, item2}, cmd.Currency.CID,
 	)
 
 	op := token.NewApprove(fact)
-	if err := op.Sign(cmd.Privatekey, cmd.NetworkID.NetworkID()); err != nil {
+	if err := cmd.sign(&op); err != nil {
 		return nil, e.Wrap(err)
 	}
 
 	return op, nil
 }
+
+// createOperations creates approve operations from the items file; the items
+// are split by MaxApproveItems.
+func (cmd *ApproveCommand) createOperations() ([]base.Operation, error) {
+	e := util.StringError(utils.ErrStringCreate("approve operation"))
+
+	rows, err := cmd.load("approved", "amount")
+	if err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	items := make([]token.ApproveItem, len(rows))
+	for i := range rows {
+		approved, err := rows[i].address("approved")
+		if err != nil {
+			return nil, e.Wrap(err)
+		}
+
+		amount, err := rows[i].amount("amount", cmd.amount)
+		if err != nil {
+			return nil, e.Wrap(err)
+		}
+
+		item := token.NewApproveItem(cmd.contract, approved, amount)
+		if err := item.IsValid(nil); err != nil {
+			return nil, e.Wrap(errors.WithMessagef(err, "row %d", rows[i].line))
+		}
+		items[i] = item
+	}
+
+	chunks := splitItems(items, token.MaxApproveItems)
+	ops := make([]base.Operation, len(chunks))
+
+	for i := range chunks {
+		fact := token.NewApproveFact(
+			[]byte(cmd.Token), cmd.sender, chunks[i], cmd.Currency.CID,
+		)
+
+		op := token.NewApprove(fact)
+		if err := cmd.sign(&op); err != nil {
+			return nil, e.Wrap(err)
+		}
+		ops[i] = op
+	}
+
+	return ops, nil
+}
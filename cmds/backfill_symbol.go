@@ -0,0 +1,52 @@
+package cmds
+
+import (
+	"context"
+
+	ccmds "github.com/imfact-labs/currency-model/app/cmds"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/token-model/operation/token"
+	"github.com/imfact-labs/token-model/utils"
+)
+
+type BackfillSymbolCommand struct {
+	OperationCommand
+	Symbol TokenSymbolFlag `arg:"" name:"symbol" help:"token symbol of design of contract account" required:"true"`
+}
+
+func (cmd *BackfillSymbolCommand) Run(pctx context.Context) error { // nolint:dupl
+	if _, err := cmd.prepare(pctx); err != nil {
+		return err
+	}
+
+	if err := cmd.parseFlags(); err != nil {
+		return err
+	}
+
+	op, err := cmd.createOperation()
+	if err != nil {
+		return err
+	}
+
+	ccmds.PrettyPrint(cmd.Out, op)
+
+	return nil
+}
+
+func (cmd *BackfillSymbolCommand) createOperation() (base.Operation, error) { // nolint:dupl}
+	e := util.StringError(utils.ErrStringCreate("backfill-symbol operation"))
+
+	fact := token.NewBackfillSymbolFact(
+		[]byte(cmd.Token),
+		cmd.sender, cmd.contract,
+		cmd.Currency.CID, cmd.Symbol.Symbol,
+	)
+
+	op := token.NewBackfillSymbol(fact)
+	if err := cmd.sign(&op); err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	return op, nil
+}
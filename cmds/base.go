@@ -12,6 +12,7 @@ import (
 	"github.com/imfact-labs/mitum2/launch"
 	"github.com/imfact-labs/mitum2/util"
 	"github.com/imfact-labs/mitum2/util/encoder"
+	"github.com/imfact-labs/mitum2/util/localtime"
 	"github.com/imfact-labs/mitum2/util/logging"
 	"github.com/imfact-labs/mitum2/util/ps"
 	"github.com/imfact-labs/token-model/runtime/steps"
@@ -62,9 +63,28 @@ func (cmd *BaseCommand) print(f string, a ...interface{}) {
 	_, _ = fmt.Fprintln(cmd.Out)
 }
 
+// OperationFlags is same with ccmds.OperationFlags, except the privatekey;
+// with "-" the operation is exported without signs and can be signed later by
+// "operation token sign".
+type OperationFlags struct {
+	Privatekey PrivatekeyFlag      `arg:"" name:"privatekey" help:"privatekey to sign operation; \"-\" for unsigned operation" required:"true"` // revive:disable-line:line-length-limit
+	Token      string              `help:"token for operation" optional:""`
+	NetworkID  ccmds.NetworkIDFlag `name:"network-id" help:"network-id" required:"true" default:"${network_id}"`
+	Pretty     bool                `name:"pretty" help:"pretty format"`
+}
+
+func (op *OperationFlags) IsValid([]byte) error {
+	if len(op.Token) < 1 {
+		op.Token = localtime.Now().UTC().String()
+	}
+
+	return op.NetworkID.NetworkID().IsValid(nil)
+}
+
 type OperationCommand struct {
 	BaseCommand
-	ccmds.OperationFlags
+	OperationFlags
+	TokenAmountFlags
 	Sender   ccmds.AddressFlag    `arg:"" name:"sender" help:"sender address" required:"true"`
 	Contract ccmds.AddressFlag    `arg:"" name:"contract" help:"contract address to register token" required:"true"`
 	Currency ccmds.CurrencyIDFlag `arg:"" name:"currency" help:"currency id" required:"true"`
@@ -91,3 +111,12 @@ func (cmd *OperationCommand) parseFlags() error {
 
 	return nil
 }
+
+// sign signs op with the privatekey; op is left unsigned with "-" privatekey.
+func (cmd *OperationCommand) sign(op base.Signer) error {
+	if cmd.Privatekey.Empty() {
+		return nil
+	}
+
+	return op.Sign(cmd.Privatekey, cmd.NetworkID.NetworkID())
+}
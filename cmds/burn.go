@@ -14,7 +14,7 @@ import (
 type BurnCommand struct {
 	OperationCommand
 	Target ccmds.AddressFlag `arg:"" name:"target" help:"token target" required:"true"`
-	Amount ccmds.BigFlag     `arg:"" name:"amount" help:"amount to burn" required:"true"`
+	Amount string            `arg:"" name:"amount" help:"amount to burn; raw units, or token units with --human or symbol suffix like \"12.5MCT\"" required:"true"`
 	target base.Address
 }
 
@@ -54,16 +54,21 @@ func (cmd *BurnCommand) parseFlags() error {
 func (cmd *BurnCommand) createOperation() (base.Operation, error) { // nolint:dupl}
 	e := util.StringError(utils.ErrStringCreate("burn operation"))
 
+	amount, err := cmd.amount(cmd.Amount)
+	if err != nil {
+		return nil, e.Wrap(err)
+	}
+
 	fact := token.NewBurnFact(
 		[]byte(cmd.Token),
 		cmd.sender, cmd.contract,
 		cmd.Currency.CID,
 		cmd.target,
-		cmd.Amount.Big,
+		amount,
 	)
 
 	op := token.NewBurn(fact)
-	if err := op.Sign(cmd.Privatekey, cmd.NetworkID.NetworkID()); err != nil {
+	if err := cmd.sign(&op); err != nil {
 		return nil, e.Wrap(err)
 	}
 
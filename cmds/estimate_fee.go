@@ -0,0 +1,179 @@
+package cmds
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	ccmds "github.com/imfact-labs/currency-model/app/cmds"
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/currency-model/operation/extras"
+	ccstate "github.com/imfact-labs/currency-model/state/currency"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/pkg/errors"
+)
+
+type EstimateFeeCommand struct {
+	BaseCommand
+	OperationInputFlags
+	//revive:disable:line-length-limit
+	CurrencyDesign       string `name:"currency-design" help:"currency design file; currency design json or its state" type:"existingfile" placeholder:"PATH"`
+	CurrencyDesignRemote string `name:"currency-design.remote" help:"remote node conn info to get currency design" placeholder:"ConnInfo"`
+	//revive:enable:line-length-limit
+	policies map[ctypes.CurrencyID]ctypes.CurrencyPolicy
+}
+
+type TokenFeeEstimate struct {
+	Fact     util.Hash         `json:"fact"`
+	Currency ctypes.CurrencyID `json:"currency"`
+	Items    int               `json:"items"`
+	DataSize int               `json:"data_size"`
+	Fee      common.Big        `json:"fee"`
+	Receipt  ctypes.FeeReceipt `json:"receipt,omitempty"`
+}
+
+type TokenFeeEstimates struct {
+	Operations []TokenFeeEstimate               `json:"operations"`
+	Total      map[ctypes.CurrencyID]common.Big `json:"total"`
+}
+
+// Run estimates the fee of operations by the FeeBase of fact and the feeer of
+// currency policy, same as the fee charged in processing.
+func (cmd *EstimateFeeCommand) Run(pctx context.Context) error {
+	if _, err := cmd.prepare(pctx); err != nil {
+		return err
+	}
+
+	if len(cmd.CurrencyDesign) < 1 && len(cmd.CurrencyDesignRemote) < 1 {
+		return errors.Errorf("expected --currency-design or --currency-design.remote")
+	}
+
+	cmd.policies = map[ctypes.CurrencyID]ctypes.CurrencyPolicy{}
+
+	if len(cmd.CurrencyDesign) > 0 {
+		if err := cmd.loadCurrencyDesignFile(); err != nil {
+			return err
+		}
+	}
+
+	ops, isArray, err := cmd.load(cmd.Encoder)
+	if err != nil {
+		return err
+	}
+
+	es := TokenFeeEstimates{
+		Operations: make([]TokenFeeEstimate, len(ops)),
+		Total:      map[ctypes.CurrencyID]common.Big{},
+	}
+
+	for i := range ops {
+		e, err := cmd.estimate(ops[i])
+		if err != nil {
+			return errors.WithMessagef(err, "operation %d", i)
+		}
+
+		es.Operations[i] = e
+
+		if t, found := es.Total[e.Currency]; found {
+			es.Total[e.Currency] = t.Add(e.Fee)
+		} else {
+			es.Total[e.Currency] = e.Fee
+		}
+	}
+
+	if isArray {
+		ccmds.PrettyPrint(cmd.Out, es)
+	} else {
+		ccmds.PrettyPrint(cmd.Out, es.Operations[0])
+	}
+
+	return nil
+}
+
+func (cmd *EstimateFeeCommand) estimate(op base.Operation) (TokenFeeEstimate, error) {
+	fact, ok := op.Fact().(extras.FeeAble)
+	if !ok {
+		return TokenFeeEstimate{}, errors.Errorf("expected FeeAble fact, not %T", op.Fact())
+	}
+
+	cid, items, dataSize, _ := fact.FeeBase()
+
+	policy, err := cmd.policy(cid)
+	if err != nil {
+		return TokenFeeEstimate{}, err
+	}
+
+	receipt, fee := ctypes.NewFeeReceiptFromFeeer(cid, policy.Feeer(), items, dataSize)
+
+	return TokenFeeEstimate{
+		Fact:     op.Fact().Hash(),
+		Currency: cid,
+		Items:    items,
+		DataSize: dataSize,
+		Fee:      fee,
+		Receipt:  receipt,
+	}, nil
+}
+
+func (cmd *EstimateFeeCommand) policy(cid ctypes.CurrencyID) (ctypes.CurrencyPolicy, error) {
+	if policy, found := cmd.policies[cid]; found {
+		return policy, nil
+	}
+
+	if len(cmd.CurrencyDesignRemote) < 1 {
+		return ctypes.CurrencyPolicy{}, errors.Errorf("currency design not found in --currency-design, %q", cid)
+	}
+
+	switch st, found, err := remoteState(cmd.Encoders, cmd.Encoder,
+		cmd.NetworkID.NetworkID(), cmd.CurrencyDesignRemote, ccstate.DesignStateKey(cid)); {
+	case err != nil:
+		return ctypes.CurrencyPolicy{}, errors.WithMessage(err, "get currency design")
+	case !found:
+		return ctypes.CurrencyPolicy{}, errors.Errorf("currency design not found, %q", cid)
+	default:
+		design, err := currencyDesignFromValue(st)
+		if err != nil {
+			return ctypes.CurrencyPolicy{}, err
+		}
+
+		cmd.policies[cid] = design.Policy()
+
+		return design.Policy(), nil
+	}
+}
+
+func (cmd *EstimateFeeCommand) loadCurrencyDesignFile() error {
+	b, err := os.ReadFile(filepath.Clean(cmd.CurrencyDesign))
+	if err != nil {
+		return errors.WithMessage(err, "read currency design")
+	}
+
+	i, err := cmd.Encoder.Decode(b)
+	if err != nil {
+		return errors.WithMessage(err, "load currency design")
+	}
+
+	design, err := currencyDesignFromValue(i)
+	if err != nil {
+		return err
+	}
+
+	cmd.policies[design.Currency()] = design.Policy()
+
+	return nil
+}
+
+func currencyDesignFromValue(i interface{}) (ctypes.CurrencyDesign, error) {
+	switch t := i.(type) {
+	case ctypes.CurrencyDesign:
+		return t, nil
+	case ccstate.DesignStateValue:
+		return t.Design, nil
+	case base.State:
+		return currencyDesignFromValue(t.Value())
+	default:
+		return ctypes.CurrencyDesign{}, errors.Errorf("expected currency design, not %T", i)
+	}
+}
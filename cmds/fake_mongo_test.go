@@ -0,0 +1,1757 @@
+//go:build integration
+
+package cmds
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// fakeMongo is the in-memory MongoDB server for the digest of integration
+// network. It speaks the wire protocol of the single member replica set and
+// supports only the commands and the query operators used by the digest and
+// the API handlers; the writes in transaction are applied at once and
+// abortTransaction does not roll back.
+type fakeMongo struct {
+	t        *testing.T
+	ln       net.Listener
+	dbs      map[string]map[string]*fakeCollection
+	conns    map[net.Conn]struct{}
+	wg       sync.WaitGroup
+	requests int32
+	sync.Mutex
+}
+
+type fakeCollection struct {
+	docs    []bson.D
+	indexes []fakeIndex
+}
+
+type fakeIndex struct {
+	name   string
+	keys   bson.D
+	unique bool
+}
+
+type fakeCommandError struct {
+	msg  string
+	code int32
+}
+
+func (e fakeCommandError) Error() string {
+	return e.msg
+}
+
+const (
+	fakeOpReply = 1
+	fakeOpQuery = 2004
+	fakeOpMsg   = 2013
+
+	fakeCodeDuplicateKey    = 11000
+	fakeCodeCommandNotFound = 59
+	fakeCodeBadValue        = 2
+)
+
+func newFakeMongo(t *testing.T) *fakeMongo {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("fake mongodb: %v", err)
+	}
+
+	fm := &fakeMongo{
+		t:     t,
+		ln:    ln,
+		dbs:   map[string]map[string]*fakeCollection{},
+		conns: map[net.Conn]struct{}{},
+	}
+
+	fm.wg.Add(1)
+
+	go fm.accept()
+
+	return fm
+}
+
+// URI returns the connection string of database; the client connects to
+// the server directly without discovering the replica set.
+func (fm *fakeMongo) URI(db string) string {
+	return fmt.Sprintf("mongodb://%s/%s?directConnection=true", fm.ln.Addr(), db)
+}
+
+func (fm *fakeMongo) Close() {
+	_ = fm.ln.Close()
+
+	fm.Lock()
+	for c := range fm.conns {
+		_ = c.Close()
+	}
+	fm.Unlock()
+
+	fm.wg.Wait()
+}
+
+func (fm *fakeMongo) accept() {
+	defer fm.wg.Done()
+
+	for {
+		conn, err := fm.ln.Accept()
+		if err != nil {
+			return
+		}
+
+		fm.Lock()
+		fm.conns[conn] = struct{}{}
+		fm.Unlock()
+
+		fm.wg.Add(1)
+
+		go func() {
+			defer fm.wg.Done()
+
+			fm.serve(conn)
+
+			fm.Lock()
+			delete(fm.conns, conn)
+			fm.Unlock()
+
+			_ = conn.Close()
+		}()
+	}
+}
+
+func (fm *fakeMongo) serve(conn net.Conn) {
+	header := make([]byte, 16)
+
+	for {
+		if _, err := io.ReadFull(conn, header); err != nil {
+			return
+		}
+
+		length := int(binary.LittleEndian.Uint32(header[0:4]))
+		requestID := int32(binary.LittleEndian.Uint32(header[4:8]))
+		opCode := int32(binary.LittleEndian.Uint32(header[12:16]))
+
+		if length < 16 {
+			return
+		}
+
+		body := make([]byte, length-16)
+		if _, err := io.ReadFull(conn, body); err != nil {
+			return
+		}
+
+		var reply []byte
+
+		switch opCode {
+		case fakeOpQuery:
+			reply = fm.handleQuery(requestID, body)
+		case fakeOpMsg:
+			reply = fm.handleMsg(requestID, body)
+		default:
+			fm.t.Logf("fake mongodb: unknown op code, %d", opCode)
+
+			return
+		}
+
+		if reply == nil {
+			continue
+		}
+
+		if _, err := conn.Write(reply); err != nil {
+			return
+		}
+	}
+}
+
+func (fm *fakeMongo) handleQuery(requestID int32, body []byte) []byte {
+	// NOTE flags, full collection name, skip and return
+	i := 4 + bytes.IndexByte(body[4:], 0) + 1 + 8
+
+	var query bson.D
+	if err := bson.Unmarshal(body[i:], &query); err != nil {
+		return fakeReply(requestID, fakeErrorDoc(err))
+	}
+
+	if q, ok := fakeField(query, "$query"); ok {
+		if d, ok := q.(bson.D); ok {
+			query = d
+		}
+	}
+
+	return fakeReply(requestID, fm.command(query))
+}
+
+func (fm *fakeMongo) handleMsg(requestID int32, body []byte) []byte {
+	flags := binary.LittleEndian.Uint32(body[0:4])
+	end := len(body)
+
+	if flags&1 == 1 { // NOTE checksum present
+		end -= 4
+	}
+
+	var cmd bson.D
+
+	for i := 4; i < end; {
+		kind := body[i]
+		i++
+
+		switch kind {
+		case 0:
+			size := int(binary.LittleEndian.Uint32(body[i : i+4]))
+			if err := bson.Unmarshal(body[i:i+size], &cmd); err != nil {
+				return fakeMsg(requestID, fakeErrorDoc(err))
+			}
+
+			i += size
+		case 1:
+			size := int(binary.LittleEndian.Uint32(body[i : i+4]))
+			section := body[i+4 : i+size]
+			i += size
+
+			n := bytes.IndexByte(section, 0)
+			identifier := string(section[:n])
+
+			var docs bson.A
+
+			for j := n + 1; j < len(section); {
+				dsize := int(binary.LittleEndian.Uint32(section[j : j+4]))
+
+				var d bson.D
+				if err := bson.Unmarshal(section[j:j+dsize], &d); err != nil {
+					return fakeMsg(requestID, fakeErrorDoc(err))
+				}
+
+				docs = append(docs, d)
+				j += dsize
+			}
+
+			cmd = append(cmd, bson.E{Key: identifier, Value: docs})
+		default:
+			return fakeMsg(requestID, fakeErrorDoc(fmt.Errorf("unknown section kind, %d", kind)))
+		}
+	}
+
+	reply := fakeMsg(requestID, fm.command(cmd))
+
+	if flags&2 == 2 { // NOTE moreToCome; no reply
+		return nil
+	}
+
+	return reply
+}
+
+func fakeReply(responseTo int32, doc bson.D) []byte {
+	b, _ := bson.Marshal(doc)
+
+	buf := make([]byte, 16+20)
+	binary.LittleEndian.PutUint32(buf[4:8], uint32(atomic.AddInt32(&fakeRequestID, 1)))
+	binary.LittleEndian.PutUint32(buf[8:12], uint32(responseTo))
+	binary.LittleEndian.PutUint32(buf[12:16], fakeOpReply)
+	binary.LittleEndian.PutUint32(buf[32:36], 1) // NOTE number returned
+
+	buf = append(buf, b...)
+	binary.LittleEndian.PutUint32(buf[0:4], uint32(len(buf)))
+
+	return buf
+}
+
+func fakeMsg(responseTo int32, doc bson.D) []byte {
+	b, _ := bson.Marshal(doc)
+
+	buf := make([]byte, 16+5)
+	binary.LittleEndian.PutUint32(buf[4:8], uint32(atomic.AddInt32(&fakeRequestID, 1)))
+	binary.LittleEndian.PutUint32(buf[8:12], uint32(responseTo))
+	binary.LittleEndian.PutUint32(buf[12:16], fakeOpMsg)
+
+	buf = append(buf, b...)
+	binary.LittleEndian.PutUint32(buf[0:4], uint32(len(buf)))
+
+	return buf
+}
+
+var fakeRequestID int32
+
+func fakeErrorDoc(err error) bson.D {
+	code := int32(fakeCodeBadValue)
+
+	var ce fakeCommandError
+	if e, ok := err.(fakeCommandError); ok { //nolint:errorlint //...
+		ce = e
+		code = ce.code
+	}
+
+	return bson.D{
+		{Key: "ok", Value: 0.0},
+		{Key: "errmsg", Value: err.Error()},
+		{Key: "code", Value: code},
+	}
+}
+
+func fakeOK(d ...bson.E) bson.D {
+	return append(d, bson.E{Key: "ok", Value: 1.0})
+}
+
+func (fm *fakeMongo) command(cmd bson.D) bson.D {
+	if len(cmd) < 1 {
+		return fakeErrorDoc(fmt.Errorf("empty command"))
+	}
+
+	atomic.AddInt32(&fm.requests, 1)
+
+	name := cmd[0].Key
+	db, _ := fakeField(cmd, "$db")
+	dbname, _ := db.(string)
+
+	switch strings.ToLower(name) {
+	case "hello", "ismaster":
+		return fm.hello()
+	case "ping", "endsessions", "committransaction", "aborttransaction", "killcursors":
+		return fakeOK()
+	case "buildinfo":
+		return fakeOK(
+			bson.E{Key: "version", Value: "7.0.0"},
+			bson.E{Key: "versionArray", Value: bson.A{int32(7), int32(0), int32(0), int32(0)}},
+		)
+	case "getmore":
+		return fakeOK(bson.E{Key: "cursor", Value: bson.D{
+			{Key: "nextBatch", Value: bson.A{}},
+			{Key: "id", Value: int64(0)},
+			{Key: "ns", Value: dbname},
+		}})
+	}
+
+	fm.Lock()
+	defer fm.Unlock()
+
+	var reply bson.D
+	var err error
+
+	switch name {
+	case "listCollections":
+		reply, err = fm.listCollections(dbname, cmd)
+	case "listDatabases":
+		reply, err = fm.listDatabases()
+	case "create":
+		_ = fm.collection(dbname, cmd[0].Value.(string), true)
+
+		reply = fakeOK()
+	case "createIndexes":
+		reply, err = fm.createIndexes(dbname, cmd)
+	case "listIndexes":
+		reply, err = fm.listIndexes(dbname, cmd)
+	case "drop":
+		delete(fm.dbs[dbname], cmd[0].Value.(string))
+
+		reply = fakeOK()
+	case "dropDatabase":
+		delete(fm.dbs, dbname)
+
+		reply = fakeOK()
+	case "insert":
+		reply, err = fm.insert(dbname, cmd)
+	case "update":
+		reply, err = fm.update(dbname, cmd)
+	case "delete":
+		reply, err = fm.delete(dbname, cmd)
+	case "find":
+		reply, err = fm.find(dbname, cmd)
+	case "count":
+		reply, err = fm.count(dbname, cmd)
+	case "aggregate":
+		reply, err = fm.aggregate(dbname, cmd)
+	case "distinct":
+		reply, err = fm.distinct(dbname, cmd)
+	default:
+		err = fakeCommandError{msg: fmt.Sprintf("no such command: '%s'", name), code: fakeCodeCommandNotFound}
+	}
+
+	if err != nil {
+		fm.t.Logf("fake mongodb: command %q failed: %v", name, err)
+
+		return fakeErrorDoc(err)
+	}
+
+	return reply
+}
+
+func (fm *fakeMongo) hello() bson.D {
+	addr := fm.ln.Addr().String()
+
+	return fakeOK(
+		bson.E{Key: "helloOk", Value: true},
+		bson.E{Key: "ismaster", Value: true},
+		bson.E{Key: "isWritablePrimary", Value: true},
+		bson.E{Key: "setName", Value: "fake"},
+		bson.E{Key: "setVersion", Value: int32(1)},
+		bson.E{Key: "hosts", Value: bson.A{addr}},
+		bson.E{Key: "primary", Value: addr},
+		bson.E{Key: "me", Value: addr},
+		bson.E{Key: "electionId", Value: bson.NewObjectID()},
+		bson.E{Key: "maxBsonObjectSize", Value: int32(16 * 1024 * 1024)},
+		bson.E{Key: "maxMessageSizeBytes", Value: int32(48000000)},
+		bson.E{Key: "maxWriteBatchSize", Value: int32(100000)},
+		bson.E{Key: "localTime", Value: bson.NewDateTimeFromTime(time.Now())},
+		bson.E{Key: "logicalSessionTimeoutMinutes", Value: int32(30)},
+		bson.E{Key: "connectionId", Value: atomic.AddInt32(&fakeRequestID, 1)},
+		bson.E{Key: "minWireVersion", Value: int32(0)},
+		bson.E{Key: "maxWireVersion", Value: int32(21)},
+		bson.E{Key: "readOnly", Value: false},
+	)
+}
+
+func (fm *fakeMongo) collection(db, name string, create bool) *fakeCollection {
+	cols, found := fm.dbs[db]
+	if !found {
+		if !create {
+			return nil
+		}
+
+		cols = map[string]*fakeCollection{}
+		fm.dbs[db] = cols
+	}
+
+	col, found := cols[name]
+	if !found && create {
+		col = &fakeCollection{
+			indexes: []fakeIndex{{name: "_id_", keys: bson.D{{Key: "_id", Value: int32(1)}}, unique: true}},
+		}
+		cols[name] = col
+	}
+
+	return col
+}
+
+func fakeCursor(db, col string, docs []bson.D) bson.D {
+	batch := make(bson.A, len(docs))
+	for i := range docs {
+		batch[i] = docs[i]
+	}
+
+	return fakeOK(bson.E{Key: "cursor", Value: bson.D{
+		{Key: "firstBatch", Value: batch},
+		{Key: "id", Value: int64(0)},
+		{Key: "ns", Value: db + "." + col},
+	}})
+}
+
+func (fm *fakeMongo) listCollections(db string, cmd bson.D) (bson.D, error) {
+	var filter bson.D
+	if i, ok := fakeField(cmd, "filter"); ok {
+		filter, _ = i.(bson.D)
+	}
+
+	var names []string
+	for name := range fm.dbs[db] {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var docs []bson.D
+
+	for _, name := range names {
+		doc := bson.D{
+			{Key: "name", Value: name},
+			{Key: "type", Value: "collection"},
+			{Key: "options", Value: bson.D{}},
+			{Key: "info", Value: bson.D{{Key: "readOnly", Value: false}}},
+		}
+
+		switch ok, err := fakeMatch(doc, filter); {
+		case err != nil:
+			return nil, err
+		case ok:
+			docs = append(docs, doc)
+		}
+	}
+
+	return fakeCursor(db, "$cmd.listCollections", docs), nil
+}
+
+func (fm *fakeMongo) listDatabases() (bson.D, error) {
+	var dbs bson.A
+	for name := range fm.dbs {
+		dbs = append(dbs, bson.D{{Key: "name", Value: name}, {Key: "sizeOnDisk", Value: int64(0)}, {Key: "empty", Value: false}})
+	}
+
+	return fakeOK(bson.E{Key: "databases", Value: dbs}, bson.E{Key: "totalSize", Value: int64(0)}), nil
+}
+
+func (fm *fakeMongo) createIndexes(db string, cmd bson.D) (bson.D, error) {
+	col := fm.collection(db, cmd[0].Value.(string), true)
+	before := len(col.indexes)
+
+	indexes, _ := fakeField(cmd, "indexes")
+
+	for _, i := range indexes.(bson.A) {
+		d := i.(bson.D)
+
+		name, _ := fakeField(d, "name")
+		keys, _ := fakeField(d, "key")
+		unique, _ := fakeField(d, "unique")
+
+		index := fakeIndex{keys: keys.(bson.D)}
+		index.name, _ = name.(string)
+		index.unique, _ = unique.(bool)
+
+		var found bool
+
+		for j := range col.indexes {
+			if col.indexes[j].name == index.name {
+				found = true
+
+				break
+			}
+		}
+
+		if !found {
+			col.indexes = append(col.indexes, index)
+		}
+	}
+
+	return fakeOK(
+		bson.E{Key: "numIndexesBefore", Value: int32(before)},
+		bson.E{Key: "numIndexesAfter", Value: int32(len(col.indexes))},
+	), nil
+}
+
+func (fm *fakeMongo) listIndexes(db string, cmd bson.D) (bson.D, error) {
+	name := cmd[0].Value.(string)
+
+	col := fm.collection(db, name, false)
+	if col == nil {
+		return nil, fakeCommandError{msg: fmt.Sprintf("ns does not exist: %s.%s", db, name), code: 26}
+	}
+
+	docs := make([]bson.D, len(col.indexes))
+	for i := range col.indexes {
+		docs[i] = bson.D{
+			{Key: "v", Value: int32(2)},
+			{Key: "key", Value: col.indexes[i].keys},
+			{Key: "name", Value: col.indexes[i].name},
+		}
+
+		if col.indexes[i].unique {
+			docs[i] = append(docs[i], bson.E{Key: "unique", Value: true})
+		}
+	}
+
+	return fakeCursor(db, name, docs), nil
+}
+
+// duplicated returns the unique index, which doc violates against the other
+// documents except the document at skip.
+func (col *fakeCollection) duplicated(doc bson.D, skip int) (string, bool) {
+	for _, index := range col.indexes {
+		if !index.unique {
+			continue
+		}
+
+		key := fakeIndexKey(doc, index.keys)
+
+		for i := range col.docs {
+			if i == skip {
+				continue
+			}
+
+			if fakeEqual(key, fakeIndexKey(col.docs[i], index.keys)) {
+				return index.name, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+func fakeIndexKey(doc, keys bson.D) bson.A {
+	key := make(bson.A, len(keys))
+
+	for i := range keys {
+		if vs := fakeLookup(doc, keys[i].Key, false); len(vs) > 0 {
+			key[i] = vs[0]
+		}
+	}
+
+	return key
+}
+
+func fakeWriteError(index int, name string) bson.D {
+	return bson.D{
+		{Key: "index", Value: int32(index)},
+		{Key: "code", Value: int32(fakeCodeDuplicateKey)},
+		{Key: "errmsg", Value: "E11000 duplicate key error index: " + name},
+	}
+}
+
+func fakeWriteReply(n int, writeErrors bson.A, extra ...bson.E) bson.D {
+	d := append(bson.D{{Key: "n", Value: int32(n)}}, extra...)
+
+	if len(writeErrors) > 0 {
+		d = append(d, bson.E{Key: "writeErrors", Value: writeErrors})
+	}
+
+	return fakeOK(d...)
+}
+
+func fakeOrdered(cmd bson.D) bool {
+	ordered, found := fakeField(cmd, "ordered")
+	if !found {
+		return true
+	}
+
+	b, _ := ordered.(bool)
+
+	return b
+}
+
+func (fm *fakeMongo) insert(db string, cmd bson.D) (bson.D, error) {
+	col := fm.collection(db, cmd[0].Value.(string), true)
+	ordered := fakeOrdered(cmd)
+
+	documents, _ := fakeField(cmd, "documents")
+
+	var n int
+	var writeErrors bson.A
+
+	for i, d := range documents.(bson.A) {
+		doc := d.(bson.D)
+		if _, found := fakeField(doc, "_id"); !found {
+			doc = append(bson.D{{Key: "_id", Value: bson.NewObjectID()}}, doc...)
+		}
+
+		if name, found := col.duplicated(doc, -1); found {
+			writeErrors = append(writeErrors, fakeWriteError(i, name))
+
+			if ordered {
+				break
+			}
+
+			continue
+		}
+
+		col.docs = append(col.docs, doc)
+		n++
+	}
+
+	return fakeWriteReply(n, writeErrors), nil
+}
+
+func (fm *fakeMongo) update(db string, cmd bson.D) (bson.D, error) {
+	col := fm.collection(db, cmd[0].Value.(string), true)
+	ordered := fakeOrdered(cmd)
+
+	updates, _ := fakeField(cmd, "updates")
+
+	var n, modified int
+	var upserted, writeErrors bson.A
+
+	for i, u := range updates.(bson.A) {
+		d := u.(bson.D)
+
+		q, _ := fakeField(d, "q")
+		filter, _ := q.(bson.D)
+		update, _ := fakeField(d, "u")
+		upsert, _ := fakeField(d, "upsert")
+		multi, _ := fakeField(d, "multi")
+
+		var matched []int
+
+		for j := range col.docs {
+			switch ok, err := fakeMatch(col.docs[j], filter); {
+			case err != nil:
+				return nil, err
+			case ok:
+				matched = append(matched, j)
+			}
+
+			if len(matched) > 0 && multi != true {
+				break
+			}
+		}
+
+		if len(matched) < 1 {
+			if upsert != true {
+				continue
+			}
+
+			doc, err := fakeUpsertDoc(filter, update)
+			if err != nil {
+				return nil, err
+			}
+
+			if name, found := col.duplicated(doc, -1); found {
+				writeErrors = append(writeErrors, fakeWriteError(i, name))
+
+				if ordered {
+					break
+				}
+
+				continue
+			}
+
+			id, _ := fakeField(doc, "_id")
+			col.docs = append(col.docs, doc)
+			upserted = append(upserted, bson.D{{Key: "index", Value: int32(i)}, {Key: "_id", Value: id}})
+			n++
+
+			continue
+		}
+
+		var failed bool
+
+		for _, j := range matched {
+			doc, err := fakeApplyUpdate(col.docs[j], update)
+			if err != nil {
+				return nil, err
+			}
+
+			if name, found := col.duplicated(doc, j); found {
+				writeErrors = append(writeErrors, fakeWriteError(i, name))
+				failed = true
+
+				break
+			}
+
+			if !fakeEqual(doc, col.docs[j]) {
+				modified++
+			}
+
+			col.docs[j] = doc
+			n++
+		}
+
+		if failed && ordered {
+			break
+		}
+	}
+
+	extra := []bson.E{{Key: "nModified", Value: int32(modified)}}
+	if len(upserted) > 0 {
+		extra = append(extra, bson.E{Key: "upserted", Value: upserted})
+	}
+
+	return fakeWriteReply(n, writeErrors, extra...), nil
+}
+
+func fakeIsOperatorDoc(d bson.D) bool {
+	return len(d) > 0 && strings.HasPrefix(d[0].Key, "$")
+}
+
+func fakeUpsertDoc(filter bson.D, update interface{}) (bson.D, error) {
+	u, ok := update.(bson.D)
+	if !ok {
+		return nil, fmt.Errorf("unsupported update, %T", update)
+	}
+
+	var doc bson.D
+
+	if !fakeIsOperatorDoc(u) {
+		doc = append(bson.D{}, u...)
+
+		if _, found := fakeField(doc, "_id"); !found {
+			if id, found := fakeField(filter, "_id"); found {
+				if d, ok := id.(bson.D); !ok || !fakeIsOperatorDoc(d) {
+					doc = append(bson.D{{Key: "_id", Value: id}}, doc...)
+				}
+			}
+		}
+	} else {
+		for _, e := range filter {
+			if strings.HasPrefix(e.Key, "$") {
+				continue
+			}
+
+			if d, ok := e.Value.(bson.D); ok && fakeIsOperatorDoc(d) {
+				if v, found := fakeField(d, "$eq"); found {
+					doc = fakeSetPath(doc, e.Key, v)
+				}
+
+				continue
+			}
+
+			doc = fakeSetPath(doc, e.Key, e.Value)
+		}
+
+		i, err := fakeApplyOperators(doc, u, true)
+		if err != nil {
+			return nil, err
+		}
+
+		doc = i
+	}
+
+	if _, found := fakeField(doc, "_id"); !found {
+		doc = append(bson.D{{Key: "_id", Value: bson.NewObjectID()}}, doc...)
+	}
+
+	return doc, nil
+}
+
+func fakeApplyUpdate(doc bson.D, update interface{}) (bson.D, error) {
+	u, ok := update.(bson.D)
+	if !ok {
+		return nil, fmt.Errorf("unsupported update, %T", update)
+	}
+
+	if fakeIsOperatorDoc(u) {
+		return fakeApplyOperators(doc, u, false)
+	}
+
+	id, _ := fakeField(doc, "_id")
+
+	nd := bson.D{{Key: "_id", Value: id}}
+
+	for _, e := range u {
+		if e.Key != "_id" {
+			nd = append(nd, e)
+		}
+	}
+
+	return nd, nil
+}
+
+func fakeApplyOperators(doc, u bson.D, insert bool) (bson.D, error) {
+	nd := append(bson.D{}, doc...)
+
+	for _, op := range u {
+		fields, ok := op.Value.(bson.D)
+		if !ok {
+			return nil, fmt.Errorf("invalid %s, %T", op.Key, op.Value)
+		}
+
+		for _, f := range fields {
+			switch op.Key {
+			case "$set":
+				nd = fakeSetPath(nd, f.Key, f.Value)
+			case "$setOnInsert":
+				if insert {
+					nd = fakeSetPath(nd, f.Key, f.Value)
+				}
+			case "$unset":
+				nd = fakeUnsetPath(nd, f.Key)
+			case "$inc":
+				var v interface{} = int32(0)
+				if vs := fakeLookup(nd, f.Key, false); len(vs) > 0 {
+					v = vs[0]
+				}
+
+				nd = fakeSetPath(nd, f.Key, fakeAdd(v, f.Value))
+			case "$max", "$min":
+				vs := fakeLookup(nd, f.Key, false)
+				if len(vs) < 1 {
+					nd = fakeSetPath(nd, f.Key, f.Value)
+
+					continue
+				}
+
+				c := fakeCompare(f.Value, vs[0])
+				if (op.Key == "$max" && c > 0) || (op.Key == "$min" && c < 0) {
+					nd = fakeSetPath(nd, f.Key, f.Value)
+				}
+			case "$push", "$addToSet":
+				var a bson.A
+				if vs := fakeLookup(nd, f.Key, false); len(vs) > 0 {
+					a, _ = vs[0].(bson.A)
+				}
+
+				values := bson.A{f.Value}
+				if d, ok := f.Value.(bson.D); ok {
+					if each, found := fakeField(d, "$each"); found {
+						values, _ = each.(bson.A)
+					}
+				}
+
+				for _, v := range values {
+					if op.Key == "$addToSet" && fakeContains(a, v) {
+						continue
+					}
+
+					a = append(a, v)
+				}
+
+				nd = fakeSetPath(nd, f.Key, a)
+			default:
+				return nil, fmt.Errorf("unsupported update operator, %s", op.Key)
+			}
+		}
+	}
+
+	return nd, nil
+}
+
+func (fm *fakeMongo) delete(db string, cmd bson.D) (bson.D, error) {
+	col := fm.collection(db, cmd[0].Value.(string), false)
+
+	deletes, _ := fakeField(cmd, "deletes")
+
+	var n int
+
+	for _, d := range deletes.(bson.A) {
+		if col == nil {
+			break
+		}
+
+		q, _ := fakeField(d.(bson.D), "q")
+		filter, _ := q.(bson.D)
+		limit, _ := fakeField(d.(bson.D), "limit")
+
+		var kept []bson.D
+
+		for i := range col.docs {
+			if limit != nil && fakeNumber(limit) == 1 && n > 0 {
+				kept = append(kept, col.docs[i])
+
+				continue
+			}
+
+			switch ok, err := fakeMatch(col.docs[i], filter); {
+			case err != nil:
+				return nil, err
+			case ok:
+				n++
+			default:
+				kept = append(kept, col.docs[i])
+			}
+		}
+
+		col.docs = kept
+	}
+
+	return fakeWriteReply(n, nil), nil
+}
+
+func (fm *fakeMongo) query(db, name string, filter bson.D) ([]bson.D, error) {
+	col := fm.collection(db, name, false)
+	if col == nil {
+		return nil, nil
+	}
+
+	var docs []bson.D
+
+	for i := range col.docs {
+		switch ok, err := fakeMatch(col.docs[i], filter); {
+		case err != nil:
+			return nil, err
+		case ok:
+			docs = append(docs, col.docs[i])
+		}
+	}
+
+	return docs, nil
+}
+
+func (fm *fakeMongo) find(db string, cmd bson.D) (bson.D, error) {
+	name := cmd[0].Value.(string)
+
+	var filter bson.D
+	if i, found := fakeField(cmd, "filter"); found {
+		filter, _ = i.(bson.D)
+	}
+
+	docs, err := fm.query(db, name, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if i, found := fakeField(cmd, "sort"); found {
+		fakeSort(docs, i.(bson.D))
+	}
+
+	if i, found := fakeField(cmd, "skip"); found {
+		docs = fakeSkip(docs, fakeNumber(i))
+	}
+
+	if i, found := fakeField(cmd, "limit"); found {
+		docs = fakeLimit(docs, fakeNumber(i))
+	}
+
+	if i, found := fakeField(cmd, "projection"); found {
+		docs = fakeProject(docs, i.(bson.D))
+	}
+
+	return fakeCursor(db, name, docs), nil
+}
+
+func (fm *fakeMongo) count(db string, cmd bson.D) (bson.D, error) {
+	var filter bson.D
+	if i, found := fakeField(cmd, "query"); found {
+		filter, _ = i.(bson.D)
+	}
+
+	docs, err := fm.query(db, cmd[0].Value.(string), filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if i, found := fakeField(cmd, "skip"); found {
+		docs = fakeSkip(docs, fakeNumber(i))
+	}
+
+	if i, found := fakeField(cmd, "limit"); found {
+		docs = fakeLimit(docs, fakeNumber(i))
+	}
+
+	return fakeOK(bson.E{Key: "n", Value: int32(len(docs))}), nil
+}
+
+func (fm *fakeMongo) distinct(db string, cmd bson.D) (bson.D, error) {
+	var filter bson.D
+	if i, found := fakeField(cmd, "query"); found {
+		filter, _ = i.(bson.D)
+	}
+
+	docs, err := fm.query(db, cmd[0].Value.(string), filter)
+	if err != nil {
+		return nil, err
+	}
+
+	key, _ := fakeField(cmd, "key")
+
+	var values bson.A
+
+	for i := range docs {
+		for _, v := range fakeLookup(docs[i], key.(string), true) {
+			if !fakeContains(values, v) {
+				values = append(values, v)
+			}
+		}
+	}
+
+	return fakeOK(bson.E{Key: "values", Value: values}), nil
+}
+
+func (fm *fakeMongo) aggregate(db string, cmd bson.D) (bson.D, error) {
+	name, ok := cmd[0].Value.(string)
+	if !ok {
+		return nil, fmt.Errorf("unsupported aggregate, %v", cmd[0].Value)
+	}
+
+	docs, err := fm.query(db, name, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	pipeline, _ := fakeField(cmd, "pipeline")
+
+	for _, s := range pipeline.(bson.A) {
+		stage := s.(bson.D)
+
+		if len(stage) != 1 {
+			return nil, fmt.Errorf("invalid stage, %v", stage)
+		}
+
+		switch v := stage[0].Value; stage[0].Key {
+		case "$match":
+			var matched []bson.D
+
+			for i := range docs {
+				switch ok, err := fakeMatch(docs[i], v.(bson.D)); {
+				case err != nil:
+					return nil, err
+				case ok:
+					matched = append(matched, docs[i])
+				}
+			}
+
+			docs = matched
+		case "$sort":
+			docs = append([]bson.D{}, docs...)
+			fakeSort(docs, v.(bson.D))
+		case "$skip":
+			docs = fakeSkip(docs, fakeNumber(v))
+		case "$limit":
+			docs = fakeLimit(docs, fakeNumber(v))
+		case "$project":
+			docs = fakeProject(docs, v.(bson.D))
+		case "$count":
+			docs = []bson.D{{{Key: v.(string), Value: int32(len(docs))}}}
+		case "$group":
+			if docs, err = fakeGroup(docs, v.(bson.D)); err != nil {
+				return nil, err
+			}
+		case "$replaceRoot":
+			newRoot, _ := fakeField(v.(bson.D), "newRoot")
+
+			for i := range docs {
+				d, ok := fakeExpr(docs[i], newRoot).(bson.D)
+				if !ok {
+					return nil, fmt.Errorf("invalid newRoot, %v", newRoot)
+				}
+
+				docs[i] = d
+			}
+		case "$out":
+			col := fm.collection(db, v.(string), true)
+			col.docs = append([]bson.D{}, docs...)
+			docs = nil
+		default:
+			return nil, fmt.Errorf("unsupported stage, %s", stage[0].Key)
+		}
+	}
+
+	return fakeCursor(db, name, docs), nil
+}
+
+func fakeGroup(docs []bson.D, spec bson.D) ([]bson.D, error) {
+	id, _ := fakeField(spec, "_id")
+
+	var keys bson.A
+	var groups [][]bson.D
+
+	for i := range docs {
+		key := fakeExpr(docs[i], id)
+
+		j := -1
+
+		for k := range keys {
+			if fakeEqual(keys[k], key) {
+				j = k
+
+				break
+			}
+		}
+
+		if j < 0 {
+			keys = append(keys, key)
+			groups = append(groups, nil)
+			j = len(keys) - 1
+		}
+
+		groups[j] = append(groups[j], docs[i])
+	}
+
+	result := make([]bson.D, len(keys))
+
+	for i := range keys {
+		doc := bson.D{{Key: "_id", Value: keys[i]}}
+
+		for _, f := range spec {
+			if f.Key == "_id" {
+				continue
+			}
+
+			acc, ok := f.Value.(bson.D)
+			if !ok || len(acc) != 1 {
+				return nil, fmt.Errorf("invalid accumulator, %v", f.Value)
+			}
+
+			var v interface{}
+
+			switch acc[0].Key {
+			case "$first":
+				v = fakeExpr(groups[i][0], acc[0].Value)
+			case "$last":
+				v = fakeExpr(groups[i][len(groups[i])-1], acc[0].Value)
+			case "$sum":
+				v = int32(0)
+				for _, d := range groups[i] {
+					v = fakeAdd(v, fakeExpr(d, acc[0].Value))
+				}
+			case "$max":
+				for _, d := range groups[i] {
+					if e := fakeExpr(d, acc[0].Value); v == nil || fakeCompare(e, v) > 0 {
+						v = e
+					}
+				}
+			case "$push", "$addToSet":
+				a := bson.A{}
+
+				for _, d := range groups[i] {
+					e := fakeExpr(d, acc[0].Value)
+					if acc[0].Key == "$addToSet" && fakeContains(a, e) {
+						continue
+					}
+
+					a = append(a, e)
+				}
+
+				v = a
+			default:
+				return nil, fmt.Errorf("unsupported accumulator, %s", acc[0].Key)
+			}
+
+			doc = append(doc, bson.E{Key: f.Key, Value: v})
+		}
+
+		result[i] = doc
+	}
+
+	return result, nil
+}
+
+// fakeExpr evaluates the field paths, like "$field" and "$$ROOT", in the
+// expression.
+func fakeExpr(doc bson.D, expr interface{}) interface{} {
+	switch t := expr.(type) {
+	case string:
+		switch {
+		case t == "$$ROOT":
+			return doc
+		case strings.HasPrefix(t, "$"):
+			if vs := fakeLookup(doc, t[1:], false); len(vs) > 0 {
+				return vs[0]
+			}
+
+			return nil
+		default:
+			return t
+		}
+	case bson.D:
+		d := make(bson.D, len(t))
+		for i := range t {
+			d[i] = bson.E{Key: t[i].Key, Value: fakeExpr(doc, t[i].Value)}
+		}
+
+		return d
+	default:
+		return expr
+	}
+}
+
+func fakeSkip(docs []bson.D, n int64) []bson.D {
+	if n >= int64(len(docs)) {
+		return nil
+	}
+
+	return docs[n:]
+}
+
+func fakeLimit(docs []bson.D, n int64) []bson.D {
+	if n < 0 {
+		n = -n
+	}
+
+	if n == 0 || n >= int64(len(docs)) {
+		return docs
+	}
+
+	return docs[:n]
+}
+
+func fakeSort(docs []bson.D, spec bson.D) {
+	sort.SliceStable(docs, func(i, j int) bool {
+		for _, s := range spec {
+			var a, b interface{}
+
+			if vs := fakeLookup(docs[i], s.Key, false); len(vs) > 0 {
+				a = vs[0]
+			}
+
+			if vs := fakeLookup(docs[j], s.Key, false); len(vs) > 0 {
+				b = vs[0]
+			}
+
+			c := fakeCompare(a, b)
+			if fakeNumber(s.Value) < 0 {
+				c = -c
+			}
+
+			if c != 0 {
+				return c < 0
+			}
+		}
+
+		return false
+	})
+}
+
+func fakeProject(docs []bson.D, spec bson.D) []bson.D {
+	if len(spec) < 1 {
+		return docs
+	}
+
+	include := true
+	excludeID := false
+
+	for _, s := range spec {
+		if s.Key == "_id" {
+			excludeID = fakeNumber(s.Value) == 0 && s.Value != true
+
+			continue
+		}
+
+		include = s.Value == true || fakeNumber(s.Value) != 0
+	}
+
+	projected := make([]bson.D, len(docs))
+
+	for i := range docs {
+		var d bson.D
+
+		for _, e := range docs[i] {
+			if e.Key == "_id" {
+				if !excludeID {
+					d = append(d, e)
+				}
+
+				continue
+			}
+
+			_, found := fakeField(spec, e.Key)
+			if found == include {
+				d = append(d, e)
+			}
+		}
+
+		projected[i] = d
+	}
+
+	return projected
+}
+
+func fakeField(d bson.D, key string) (interface{}, bool) {
+	for i := range d {
+		if d[i].Key == key {
+			return d[i].Value, true
+		}
+	}
+
+	return nil, false
+}
+
+// fakeLookup returns the values of dotted path; when expand is true, the
+// elements of array are also returned, like the query of MongoDB.
+func fakeLookup(v interface{}, path string, expand bool) []interface{} {
+	parts := strings.SplitN(path, ".", 2)
+
+	switch t := v.(type) {
+	case bson.D:
+		i, found := fakeField(t, parts[0])
+		if !found {
+			return nil
+		}
+
+		if len(parts) == 1 {
+			if a, ok := i.(bson.A); ok && expand {
+				return append([]interface{}{i}, a...)
+			}
+
+			return []interface{}{i}
+		}
+
+		return fakeLookup(i, parts[1], expand)
+	case bson.A:
+		var vs []interface{}
+
+		for _, e := range t {
+			vs = append(vs, fakeLookup(e, path, expand)...)
+		}
+
+		return vs
+	default:
+		return nil
+	}
+}
+
+func fakeSetPath(doc bson.D, path string, value interface{}) bson.D {
+	parts := strings.SplitN(path, ".", 2)
+
+	nd := append(bson.D{}, doc...)
+
+	for i := range nd {
+		if nd[i].Key != parts[0] {
+			continue
+		}
+
+		if len(parts) == 1 {
+			nd[i].Value = value
+		} else {
+			sub, _ := nd[i].Value.(bson.D)
+			nd[i].Value = fakeSetPath(sub, parts[1], value)
+		}
+
+		return nd
+	}
+
+	if len(parts) == 1 {
+		return append(nd, bson.E{Key: parts[0], Value: value})
+	}
+
+	return append(nd, bson.E{Key: parts[0], Value: fakeSetPath(nil, parts[1], value)})
+}
+
+func fakeUnsetPath(doc bson.D, path string) bson.D {
+	parts := strings.SplitN(path, ".", 2)
+
+	var nd bson.D
+
+	for _, e := range doc {
+		switch {
+		case e.Key != parts[0]:
+			nd = append(nd, e)
+		case len(parts) > 1:
+			if sub, ok := e.Value.(bson.D); ok {
+				e.Value = fakeUnsetPath(sub, parts[1])
+			}
+
+			nd = append(nd, e)
+		}
+	}
+
+	return nd
+}
+
+func fakeMatch(doc, filter bson.D) (bool, error) {
+	for _, e := range filter {
+		switch e.Key {
+		case "$and", "$or", "$nor":
+			conds, ok := e.Value.(bson.A)
+			if !ok {
+				return false, fmt.Errorf("invalid %s, %T", e.Key, e.Value)
+			}
+
+			var n int
+
+			for _, c := range conds {
+				ok, err := fakeMatch(doc, c.(bson.D))
+				if err != nil {
+					return false, err
+				}
+
+				if ok {
+					n++
+				}
+			}
+
+			switch {
+			case e.Key == "$and" && n != len(conds),
+				e.Key == "$or" && n < 1,
+				e.Key == "$nor" && n > 0:
+				return false, nil
+			}
+
+			continue
+		}
+
+		values := fakeLookup(doc, e.Key, true)
+
+		if d, ok := e.Value.(bson.D); ok && fakeIsOperatorDoc(d) {
+			for _, op := range d {
+				ok, err := fakeMatchOperator(values, op.Key, op.Value)
+				if err != nil || !ok {
+					return false, err
+				}
+			}
+
+			continue
+		}
+
+		if ok, _ := fakeMatchOperator(values, "$eq", e.Value); !ok {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func fakeMatchOperator(values []interface{}, op string, arg interface{}) (bool, error) {
+	any := func(f func(interface{}) bool) bool {
+		for _, v := range values {
+			if f(v) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	switch op {
+	case "$eq":
+		if arg == nil && len(values) < 1 {
+			return true, nil
+		}
+
+		return any(func(v interface{}) bool { return fakeEqual(v, arg) }), nil
+	case "$ne":
+		ok, _ := fakeMatchOperator(values, "$eq", arg)
+
+		return !ok, nil
+	case "$gt", "$gte", "$lt", "$lte":
+		return any(func(v interface{}) bool {
+			if !fakeSameKind(v, arg) {
+				return false
+			}
+
+			c := fakeCompare(v, arg)
+
+			switch op {
+			case "$gt":
+				return c > 0
+			case "$gte":
+				return c >= 0
+			case "$lt":
+				return c < 0
+			default:
+				return c <= 0
+			}
+		}), nil
+	case "$in", "$nin":
+		a, ok := arg.(bson.A)
+		if !ok {
+			return false, fmt.Errorf("invalid %s, %T", op, arg)
+		}
+
+		var found bool
+
+		for _, i := range a {
+			if ok, _ := fakeMatchOperator(values, "$eq", i); ok {
+				found = true
+
+				break
+			}
+		}
+
+		return found == (op == "$in"), nil
+	case "$exists":
+		return (len(values) > 0) == (arg == true || fakeNumber(arg) != 0), nil
+	case "$size":
+		return any(func(v interface{}) bool {
+			a, ok := v.(bson.A)
+
+			return ok && int64(len(a)) == fakeNumber(arg)
+		}), nil
+	case "$regex":
+		var pattern string
+
+		switch t := arg.(type) {
+		case string:
+			pattern = t
+		case bson.Regex:
+			pattern = t.Pattern
+		}
+
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return false, err
+		}
+
+		return any(func(v interface{}) bool {
+			s, ok := v.(string)
+
+			return ok && re.MatchString(s)
+		}), nil
+	case "$options":
+		return true, nil
+	case "$elemMatch":
+		d, ok := arg.(bson.D)
+		if !ok {
+			return false, fmt.Errorf("invalid $elemMatch, %T", arg)
+		}
+
+		for _, v := range values {
+			a, ok := v.(bson.A)
+			if !ok {
+				continue
+			}
+
+			for _, e := range a {
+				var matched bool
+				var err error
+
+				if ed, ok := e.(bson.D); ok && !fakeIsOperatorDoc(d) {
+					matched, err = fakeMatch(ed, d)
+				} else {
+					matched, err = fakeMatch(bson.D{{Key: "v", Value: e}}, bson.D{{Key: "v", Value: d}})
+				}
+
+				if err != nil {
+					return false, err
+				}
+
+				if matched {
+					return true, nil
+				}
+			}
+		}
+
+		return false, nil
+	default:
+		return false, fmt.Errorf("unsupported query operator, %s", op)
+	}
+}
+
+func fakeIsNumber(v interface{}) bool {
+	switch v.(type) {
+	case int32, int64, float64, int:
+		return true
+	default:
+		return false
+	}
+}
+
+func fakeNumber(v interface{}) int64 {
+	switch t := v.(type) {
+	case int32:
+		return int64(t)
+	case int64:
+		return t
+	case float64:
+		return int64(t)
+	case int:
+		return int64(t)
+	default:
+		return 0
+	}
+}
+
+func fakeFloat(v interface{}) float64 {
+	if f, ok := v.(float64); ok {
+		return f
+	}
+
+	return float64(fakeNumber(v))
+}
+
+func fakeAdd(a, b interface{}) interface{} {
+	switch {
+	case !fakeIsNumber(a):
+		return b
+	case !fakeIsNumber(b):
+		return a
+	}
+
+	_, af := a.(float64)
+	_, bf := b.(float64)
+
+	if af || bf {
+		return fakeFloat(a) + fakeFloat(b)
+	}
+
+	n := fakeNumber(a) + fakeNumber(b)
+	if n > math.MaxInt32 || n < math.MinInt32 {
+		return n
+	}
+
+	_, a64 := a.(int64)
+	_, b64 := b.(int64)
+
+	if a64 || b64 {
+		return n
+	}
+
+	return int32(n)
+}
+
+// fakeRank returns the order of bson types in the comparison of MongoDB.
+func fakeRank(v interface{}) int {
+	switch v.(type) {
+	case nil:
+		return 1
+	case int32, int64, float64, int:
+		return 2
+	case string:
+		return 3
+	case bson.D:
+		return 4
+	case bson.A:
+		return 5
+	case bson.Binary:
+		return 6
+	case bson.ObjectID:
+		return 7
+	case bool:
+		return 8
+	case bson.DateTime:
+		return 9
+	default:
+		return 10
+	}
+}
+
+func fakeSameKind(a, b interface{}) bool {
+	return fakeRank(a) == fakeRank(b)
+}
+
+func fakeCompare(a, b interface{}) int {
+	if ra, rb := fakeRank(a), fakeRank(b); ra != rb {
+		if ra < rb {
+			return -1
+		}
+
+		return 1
+	}
+
+	switch t := a.(type) {
+	case nil:
+		return 0
+	case int32, int64, float64, int:
+		x, y := fakeFloat(t), fakeFloat(b)
+
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		default:
+			return 0
+		}
+	case string:
+		return strings.Compare(t, b.(string))
+	case bool:
+		switch x, y := t, b.(bool); {
+		case x == y:
+			return 0
+		case !x:
+			return -1
+		default:
+			return 1
+		}
+	case bson.DateTime:
+		y := b.(bson.DateTime)
+
+		switch {
+		case t < y:
+			return -1
+		case t > y:
+			return 1
+		default:
+			return 0
+		}
+	case bson.ObjectID:
+		return bytes.Compare(t[:], func() []byte { y := b.(bson.ObjectID); return y[:] }())
+	case bson.A:
+		y := b.(bson.A)
+
+		for i := 0; i < len(t) && i < len(y); i++ {
+			if c := fakeCompare(t[i], y[i]); c != 0 {
+				return c
+			}
+		}
+
+		return len(t) - len(y)
+	default:
+		ab, _ := bson.Marshal(bson.D{{Key: "v", Value: a}})
+		bb, _ := bson.Marshal(bson.D{{Key: "v", Value: b}})
+
+		return bytes.Compare(ab, bb)
+	}
+}
+
+func fakeEqual(a, b interface{}) bool {
+	return fakeCompare(a, b) == 0
+}
+
+func fakeContains(a bson.A, v interface{}) bool {
+	for i := range a {
+		if fakeEqual(a[i], v) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/imfact-labs/currency-model/common"
 	"github.com/imfact-labs/mitum2/base"
 	"github.com/imfact-labs/token-model/types"
 	"github.com/pkg/errors"
@@ -30,7 +29,7 @@ func (v *TokenSymbolFlag) String() string {
 
 type AddressTokenAmountFlag struct {
 	address []base.Address
-	amount  []common.Big
+	amount  []string
 }
 
 func (v *AddressTokenAmountFlag) UnmarshalText(b []byte) error {
@@ -47,14 +46,11 @@ func (v *AddressTokenAmountFlag) UnmarshalText(b []byte) error {
 		}
 		v.address = append(v.address, add)
 
-		b, err := common.NewBigFromString(l[1])
-		if err != nil {
-			return errors.Wrapf(err, "invalid big string, %q", string(l[1]))
-		} else if err := b.IsValid(nil); err != nil {
-			return err
+		if len(strings.TrimSpace(l[1])) < 1 {
+			return errors.Errorf("empty amount, %q", arr[i])
 		}
 
-		v.amount = append(v.amount, b)
+		v.amount = append(v.amount, l[1])
 	}
 
 	if len(v.amount) != len(v.address) {
@@ -68,6 +64,39 @@ func (v *AddressTokenAmountFlag) Address() []base.Address {
 	return v.address
 }
 
-func (v *AddressTokenAmountFlag) Amount() []common.Big {
+// Amount returns the amount strings; they are parsed by
+// OperationCommand.amount.
+func (v *AddressTokenAmountFlag) Amount() []string {
 	return v.amount
 }
+
+// PrivatekeyFlag is the privatekey to sign operation; "-" means no privatekey
+// and the operation is created without signs.
+type PrivatekeyFlag struct {
+	base.Privatekey
+}
+
+func (v *PrivatekeyFlag) UnmarshalText(b []byte) error {
+	if strings.TrimSpace(string(b)) == "-" {
+		v.Privatekey = nil
+
+		return nil
+	}
+
+	k, err := base.DecodePrivatekeyFromString(string(b), enc)
+	if err != nil {
+		return errors.Wrapf(err, "invalid private key, %v", string(b))
+	}
+
+	if err := k.IsValid(nil); err != nil {
+		return err
+	}
+
+	v.Privatekey = k
+
+	return nil
+}
+
+func (v PrivatekeyFlag) Empty() bool {
+	return v.Privatekey == nil
+}
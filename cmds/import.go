@@ -526,7 +526,7 @@ func checkLastHeight(pctx context.Context, root string, fromHeight, toHeight bas
 	switch {
 	case nfromHeight < base.GenesisHeight:
 		nfromHeight = base.GenesisHeight
-	case nfromHeight > base.NilHeight:
+	case nfromHeight > base.GenesisHeight:
 		switch _, found, err := db.BlockMap(nfromHeight - 1); {
 		case err != nil:
 			return nfromHeight, toHeight, last, err
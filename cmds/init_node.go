@@ -0,0 +1,47 @@
+package cmds
+
+import (
+	"context"
+
+	ccmds "github.com/imfact-labs/currency-model/app/cmds"
+	"github.com/imfact-labs/mitum2/launch"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/logging"
+	"github.com/imfact-labs/token-model/runtime/pipeline"
+)
+
+// INITCommand is same with ccmds.INITCommand, except the genesis design can
+// have the token facts, like RegisterGenesisTokenFact.
+type INITCommand struct {
+	ccmds.INITCommand
+}
+
+func (cmd *INITCommand) Run(pctx context.Context) error {
+	var log *logging.Logging
+	if err := util.LoadFromContextOK(pctx, launch.LoggingContextKey, &log); err != nil {
+		return err
+	}
+
+	nctx := util.ContextWithValues(pctx, map[util.ContextKey]interface{}{
+		launch.DesignFlagContextKey:        cmd.DesignFlag,
+		launch.DevFlagsContextKey:          cmd.DevFlags,
+		launch.GenesisDesignFileContextKey: cmd.GenesisDesign,
+		launch.PrivatekeyContextKey:        string(cmd.PrivatekeyFlags.Flag.Body()),
+	})
+
+	pps := pipeline.DefaultINITPS()
+	_ = pps.SetLogging(log)
+
+	log.Log().Debug().Interface("process", pps.Verbose()).Msg("process ready")
+
+	nctx, err := pps.Run(nctx)
+	defer func() {
+		log.Log().Debug().Interface("process", pps.Verbose()).Msg("process will be closed")
+
+		if _, err = pps.Close(nctx); err != nil {
+			log.Log().Error().Err(err).Msg("failed to close")
+		}
+	}()
+
+	return err
+}
@@ -0,0 +1,970 @@
+//go:build integration
+
+package cmds
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/kong"
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/currency-model/operation/currency"
+	"github.com/imfact-labs/currency-model/operation/extension"
+	ccstate "github.com/imfact-labs/currency-model/state/currency"
+	cestate "github.com/imfact-labs/currency-model/state/extension"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	isaacnetwork "github.com/imfact-labs/mitum2/isaac/network"
+	isaacstates "github.com/imfact-labs/mitum2/isaac/states"
+	"github.com/imfact-labs/mitum2/launch"
+	"github.com/imfact-labs/mitum2/network/quicstream"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/logging"
+	"github.com/imfact-labs/token-model/digest"
+	"github.com/imfact-labs/token-model/operation/token"
+	"github.com/imfact-labs/token-model/state"
+	"github.com/imfact-labs/token-model/types"
+)
+
+// The integration network runs the nodes in this process by the same process
+// of RunCommand; the first node creates the genesis block and the others sync
+// from it. The first node also digests the blocks to fakeMongo and serves the
+// digest API. Run with,
+//
+//	go test -tags integration -run TestIntegrationNetwork -v ./cmds/
+const (
+	integrationNodes     = 3
+	integrationNetworkID = "integration"
+	integrationCurrency  = "MCC"
+	integrationTimeout   = time.Minute * 2
+)
+
+var integrationFlagDefaults = kong.Vars{
+	"log_out":                           "stderr",
+	"log_format":                        "json",
+	"log_level":                         "info",
+	"log_force_color":                   "false",
+	"design_uri":                        launch.DefaultDesignURI,
+	"create_account_threshold":          "100",
+	"create_contract_account_threshold": "100",
+	"suffrage_candidate_limiter_limit":  "77",
+	"max_operation_in_proposal":         "99",
+	"suffrage candidate lifespan":       "33",
+	"max suffrage size":                 "33",
+	"suffrage expel lifespan":           "44",
+	"safe_threshold":                    base.SafeThreshold.String(),
+	"network_id":                        integrationNetworkID,
+}
+
+type integrationAccount struct {
+	priv    base.Privatekey
+	keys    ctypes.AccountKeys
+	address base.Address
+}
+
+func newIntegrationAccount(t *testing.T) integrationAccount {
+	t.Helper()
+
+	priv := base.NewMPrivatekey()
+
+	key, err := ctypes.NewBaseAccountKey(priv.Publickey(), 100)
+	if err != nil {
+		t.Fatalf("account key: %v", err)
+	}
+
+	keys, err := ctypes.NewBaseAccountKeys([]ctypes.AccountKey{key}, 100)
+	if err != nil {
+		t.Fatalf("account keys: %v", err)
+	}
+
+	address, err := ctypes.NewAddressFromKeys(keys)
+	if err != nil {
+		t.Fatalf("account address: %v", err)
+	}
+
+	return integrationAccount{priv: priv, keys: keys, address: address}
+}
+
+type integrationNode struct {
+	address base.Address
+	priv    base.Privatekey
+	publish string
+	dir     string
+	design  string
+	stop    func()
+}
+
+func (n *integrationNode) connInfo(t *testing.T) quicstream.ConnInfo {
+	t.Helper()
+
+	var ci launch.ConnInfoFlag
+	if err := ci.UnmarshalText([]byte(n.publish + "#tls_insecure")); err != nil {
+		t.Fatalf("conn info: %v", err)
+	}
+
+	return ci.ConnInfo()
+}
+
+type integrationNetwork struct {
+	t        *testing.T
+	nodes    []*integrationNode
+	genesis  integrationAccount
+	contract integrationAccount
+	client   *isaacnetwork.BaseClient
+	mongo    *fakeMongo
+	api      string
+}
+
+func newIntegrationNetwork(t *testing.T) *integrationNetwork {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	nw := &integrationNetwork{
+		t:        t,
+		nodes:    make([]*integrationNode, integrationNodes),
+		genesis:  newIntegrationAccount(t),
+		contract: newIntegrationAccount(t),
+		mongo:    newFakeMongo(t),
+		api:      "http://" + freeTCPAddr(t),
+	}
+
+	t.Cleanup(nw.mongo.Close)
+
+	for i := range nw.nodes {
+		nw.nodes[i] = &integrationNode{
+			address: base.NewStringAddress(fmt.Sprintf("node%d", i)),
+			priv:    base.NewMPrivatekey(),
+			publish: freeUDPAddr(t),
+			dir:     filepath.Join(dir, fmt.Sprintf("node%d", i)),
+		}
+
+		if err := os.MkdirAll(nw.nodes[i].dir, 0o700); err != nil {
+			t.Fatalf("node directory: %v", err)
+		}
+	}
+
+	for i := range nw.nodes {
+		nw.writeDesign(nw.nodes[i])
+	}
+
+	client, err := newNetworkClient(encs, enc, base.NetworkID(integrationNetworkID))
+	if err != nil {
+		t.Fatalf("network client: %v", err)
+	}
+
+	nw.client = client
+
+	t.Cleanup(nw.stop)
+
+	return nw
+}
+
+func freeUDPAddr(t *testing.T) string {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("free udp port: %v", err)
+	}
+
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	return conn.LocalAddr().String()
+}
+
+func freeTCPAddr(t *testing.T) string {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("free tcp port: %v", err)
+	}
+
+	defer func() {
+		_ = ln.Close()
+	}()
+
+	return ln.Addr().String()
+}
+
+// writeDesign writes the node design; the first node digests the blocks to
+// the fake mongodb and serves the digest API.
+func (nw *integrationNetwork) writeDesign(n *integrationNode) {
+	var sources []string
+
+	for _, o := range nw.nodes {
+		if o == n {
+			continue
+		}
+
+		sources = append(sources, fmt.Sprintf(`  - type: sync-source-node
+    address: %s
+    publickey: %s
+    publish: %s
+    tls_insecure: true`, o.address, o.priv.Publickey(), o.publish))
+	}
+
+	n.design = filepath.Join(n.dir, "design.yml")
+
+	b := fmt.Sprintf(`address: %s
+privatekey: %s
+network_id: %s
+network:
+  bind: %s
+  publish: %s
+  tls_insecure: true
+storage:
+  base: %s
+sync_sources:
+%s
+parameters:
+  isaac:
+    threshold: "100"
+    interval_broadcast_ballot: 600ms
+    wait_preparing_init_ballot: 1s
+    min_wait_next_block_init_ballot: 1s
+  token:
+    symbol_registry_height: 0
+    canonical_approve_list_height: 0
+`, n.address, n.priv, integrationNetworkID, n.publish, n.publish, filepath.Join(n.dir, "data"),
+		strings.Join(sources, "\n"))
+
+	if n == nw.nodes[0] {
+		b += fmt.Sprintf(`api:
+  digest: true
+  network:
+    bind: %s
+    url: %s
+  database:
+    uri: %s
+`, nw.api, nw.api, nw.mongo.URI("token"))
+	}
+
+	if err := os.WriteFile(n.design, []byte(b), 0o600); err != nil {
+		nw.t.Fatalf("write design: %v", err)
+	}
+}
+
+func (nw *integrationNetwork) writeGenesisDesign() string {
+	var nodes []string
+
+	for _, n := range nw.nodes {
+		nodes = append(nodes, fmt.Sprintf(`      - _hint: currency-node-v0.0.1
+        address: %s
+        publickey: %s`, n.address, n.priv.Publickey()))
+	}
+
+	gk := nw.nodes[0].priv.Publickey()
+	g := nw.genesis.address
+
+	b := fmt.Sprintf(`facts:
+  - _hint: currency-suffrage-genesis-join-fact-v0.0.1
+    nodes:
+%s
+  - _hint: currency-genesis-network-policy-fact-v0.0.1
+    policy:
+      _hint: currency-network-policy-v0.0.1
+      max_operations_in_proposal: 99
+      suffrage_candidate_lifespan: 333333333
+      suffrage_candidate_limiter:
+        _hint: currency-fixed-suffrage-candidate-limiter-rule-v0.0.1
+        limit: 1
+      max_suffrage_size: 99
+      suffrage_expel_lifespan: 99
+  - _hint: mitum-currency-register-genesis-currency-operation-fact-v0.0.1
+    genesis_node_key: %s
+    keys:
+      _hint: mitum-currency-keys-v0.0.1
+      keys:
+        - _hint: mitum-currency-key-v0.0.1
+          key: %s
+          weight: 100
+      threshold: 100
+    currencies:
+      - _hint: mitum-currency-currency-design-v0.0.1
+        initial_supply: "100000000000000000000"
+        currency_id: %s
+        decimal: "9"
+        genesis_account:
+        policy:
+          _hint: mitum-currency-currency-policy-v0.0.1
+          min_balance: "1"
+          feeer:
+            _hint: mitum-currency-fixed-feeer-v0.0.1
+            receiver: %s
+            amount: "1"
+        total_supply: "100000000000000000000"
+  - _hint: mitum-token-register-genesis-token-operation-fact-v0.0.1
+    genesis_node_key: %s
+    owner: %s
+    contract: %s
+    symbol: GEN
+    name: genesis token
+    decimal: "9"
+    balances:
+      - address: %s
+        amount: "1000000"
+`, strings.Join(nodes, "\n"), gk, nw.genesis.priv.Publickey(), integrationCurrency, g, gk, g,
+		nw.contract.address, g)
+
+	f := filepath.Join(nw.nodes[0].dir, "genesis.yml")
+	if err := os.WriteFile(f, []byte(b), 0o600); err != nil {
+		nw.t.Fatalf("write genesis design: %v", err)
+	}
+
+	return f
+}
+
+// integrationContext returns the context of cli like main; the logs of node
+// are written in the directory of node.
+func integrationContext(t *testing.T, n *integrationNode, args []string, cli interface{}) context.Context {
+	t.Helper()
+
+	p, err := kong.New(cli, integrationFlagDefaults, kong.Exit(func(code int) {
+		t.Fatalf("kong exit, %d", code)
+	}))
+	if err != nil {
+		t.Fatalf("kong: %v", err)
+	}
+
+	if _, err := p.Parse(append(args,
+		"--log.out="+filepath.Join(n.dir, "node.log"),
+	)); err != nil {
+		t.Fatalf("parse flags: %v", err)
+	}
+
+	var flags launch.BaseFlags
+
+	switch i := cli.(type) {
+	case *integrationInitCLI:
+		flags = i.BaseFlags
+	case *integrationRunCLI:
+		flags = i.BaseFlags
+	}
+
+	pctx := util.ContextWithValues(context.Background(), map[util.ContextKey]interface{}{
+		launch.VersionContextKey: util.EnsureParseVersion("v0.0.1"),
+		launch.FlagsContextKey:   flags,
+	})
+
+	pctx, err = launch.PLogging(pctx)
+	if err != nil {
+		t.Fatalf("logging: %v", err)
+	}
+
+	return pctx
+}
+
+type integrationInitCLI struct { //nolint:govet //...
+	launch.BaseFlags
+	Init INITCommand `cmd:""`
+}
+
+type integrationRunCLI struct { //nolint:govet //...
+	launch.BaseFlags
+	Run RunCommand `cmd:""`
+}
+
+// init creates the genesis block in the first node; init runs in the child
+// process, because the event database opened by init is not released until
+// the process exits.
+func (nw *integrationNetwork) init() {
+	genesis := nw.writeGenesisDesign()
+	n := nw.nodes[0]
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestIntegrationInit$") //nolint:gosec //...
+	cmd.Env = append(os.Environ(), integrationInitEnv+"="+strings.Join([]string{n.dir, n.design, genesis}, "\n"))
+
+	if b, err := cmd.CombinedOutput(); err != nil {
+		nw.t.Fatalf("init: %v\n%s", err, b)
+	}
+}
+
+const integrationInitEnv = "TOKEN_INTEGRATION_INIT"
+
+// TestIntegrationInit runs init of node in the child process of
+// TestIntegrationNetwork.
+func TestIntegrationInit(t *testing.T) {
+	v := os.Getenv(integrationInitEnv)
+	if len(v) < 1 {
+		t.Skip("run by TestIntegrationNetwork")
+	}
+
+	args := strings.Split(v, "\n")
+	if len(args) != 3 {
+		t.Fatalf("invalid %s, %q", integrationInitEnv, v)
+	}
+
+	var cli integrationInitCLI
+
+	pctx := integrationContext(t, &integrationNode{dir: args[0]}, []string{"init", "--design=" + args[1], args[2]}, &cli)
+
+	if err := cli.Init.Run(pctx); err != nil {
+		t.Fatalf("init: %+v", err)
+	}
+}
+
+func (nw *integrationNetwork) start() {
+	for _, n := range nw.nodes {
+		nw.startNode(n)
+	}
+}
+
+func (nw *integrationNetwork) startNode(n *integrationNode) {
+	var cli integrationRunCLI
+
+	pctx := integrationContext(nw.t, n, []string{
+		"run", "--design=" + n.design, "--dev.allow-consensus",
+		"--discovery=" + nw.nodes[0].publish + "#tls_insecure",
+	}, &cli)
+
+	var log *logging.Logging
+	if err := util.LoadFromContextOK(pctx, launch.LoggingContextKey, &log); err != nil {
+		nw.t.Fatalf("logging: %v", err)
+	}
+
+	cmd := &cli.Run
+	cmd.RunCommand.SetLog(log.Log())
+
+	nctx, pps, err := cmd.runPS(pctx, log)
+	if err != nil {
+		nw.t.Fatalf("run process of %v: %v", n.address, err)
+	}
+
+	nctx, err = pps.Run(nctx)
+	if err != nil {
+		_, _ = pps.Close(nctx)
+
+		nw.t.Fatalf("run %v: %+v", n.address, err)
+	}
+
+	var states *isaacstates.States
+	if err := util.LoadFromContextOK(nctx, launch.StatesContextKey, &states); err != nil {
+		nw.t.Fatalf("states of %v: %v", n.address, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errch := states.Wait(ctx)
+
+	n.stop = func() {
+		cancel()
+		<-errch
+
+		_, _ = pps.Close(nctx)
+	}
+}
+
+func (nw *integrationNetwork) stop() {
+	for i := len(nw.nodes) - 1; i >= 0; i-- {
+		if n := nw.nodes[i]; n.stop != nil {
+			n.stop()
+			n.stop = nil
+		}
+	}
+
+	if nw.client != nil {
+		_ = nw.client.Close()
+	}
+}
+
+func (nw *integrationNetwork) waitFor(name string, f func() (bool, error)) {
+	nw.t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), integrationTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(time.Millisecond * 300)
+	defer ticker.Stop()
+
+	var last error
+
+	for {
+		switch ok, err := f(); {
+		case ok:
+			return
+		default:
+			last = err
+		}
+
+		select {
+		case <-ctx.Done():
+			nw.t.Fatalf("wait %s: %v", name, last)
+		case <-ticker.C:
+		}
+	}
+}
+
+// request calls f again while the node limits the requests.
+func (nw *integrationNetwork) request(f func(context.Context) error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), integrationTimeout)
+	defer cancel()
+
+	for {
+		err := func() error {
+			rctx, rcancel := context.WithTimeout(ctx, time.Second*3)
+			defer rcancel()
+
+			return f(rctx)
+		}()
+
+		if err == nil || !strings.Contains(err.Error(), launch.ErrRateLimited.Error()) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(time.Millisecond * 300):
+		}
+	}
+}
+
+func (nw *integrationNetwork) state(n *integrationNode, key string) (st base.State, found bool, _ error) {
+	err := nw.request(func(ctx context.Context) (err error) {
+		st, found, err = nw.client.State(ctx, n.connInfo(nw.t), key, nil)
+
+		return err
+	})
+
+	return st, found, err
+}
+
+func (nw *integrationNetwork) lastHeight(n *integrationNode) (base.Height, error) {
+	var bm base.BlockMap
+	var found bool
+
+	switch err := nw.request(func(ctx context.Context) (err error) {
+		bm, found, err = nw.client.LastBlockMap(ctx, n.connInfo(nw.t), nil)
+
+		return err
+	}); {
+	case err != nil:
+		return base.NilHeight, err
+	case !found:
+		return base.NilHeight, nil
+	default:
+		return bm.Manifest().Height(), nil
+	}
+}
+
+func (nw *integrationNetwork) blockMap(n *integrationNode, height base.Height) (base.BlockMap, error) {
+	var bm base.BlockMap
+	var found bool
+
+	switch err := nw.request(func(ctx context.Context) (err error) {
+		bm, found, err = nw.client.BlockMap(ctx, n.connInfo(nw.t), height)
+
+		return err
+	}); {
+	case err != nil:
+		return nil, err
+	case !found:
+		return nil, fmt.Errorf("blockmap of %d not found in %v", height, n.address)
+	default:
+		return bm, nil
+	}
+}
+
+type integrationSigner interface {
+	base.Operation
+	Sign(base.Privatekey, base.NetworkID) error
+}
+
+// send sends the operation to the node and waits until the condition is
+// satisfied in every node.
+func (nw *integrationNetwork) send(
+	name string, n *integrationNode, op integrationSigner, priv base.Privatekey, cond func(*integrationNode) (bool, error),
+) {
+	nw.t.Helper()
+
+	if err := op.Sign(priv, base.NetworkID(integrationNetworkID)); err != nil {
+		nw.t.Fatalf("sign %s: %v", name, err)
+	}
+
+	if err := op.IsValid([]byte(integrationNetworkID)); err != nil {
+		nw.t.Fatalf("invalid %s: %v", name, err)
+	}
+
+	nw.waitFor("send "+name, func() (bool, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+		defer cancel()
+
+		return nw.client.SendOperation(ctx, n.connInfo(nw.t), op)
+	})
+
+	for _, m := range nw.nodes {
+		m := m
+
+		nw.waitFor(fmt.Sprintf("%s in %v", name, m.address), func() (bool, error) {
+			return cond(m)
+		})
+	}
+}
+
+func (nw *integrationNetwork) exists(key string) func(*integrationNode) (bool, error) {
+	return func(n *integrationNode) (bool, error) {
+		_, found, err := nw.state(n, key)
+
+		return found, err
+	}
+}
+
+func (nw *integrationNetwork) tokenBalance(n *integrationNode, contract, holder base.Address) (common.Big, error) {
+	switch st, found, err := nw.state(n, state.StateKeyTokenBalance(contract.String(), holder.String())); {
+	case err != nil:
+		return common.ZeroBig, err
+	case !found:
+		return common.ZeroBig, nil
+	default:
+		return state.StateTokenBalanceValue(st)
+	}
+}
+
+func (nw *integrationNetwork) design(n *integrationNode, contract base.Address) (*types.Design, error) {
+	switch st, found, err := nw.state(n, state.StateKeyDesign(contract.String())); {
+	case err != nil:
+		return nil, err
+	case !found:
+		return nil, fmt.Errorf("design of %v not found", contract)
+	default:
+		return state.StateDesignValue(st)
+	}
+}
+
+func (nw *integrationNetwork) balanceIs(contract, holder base.Address, amount int64) func(*integrationNode) (bool, error) {
+	return func(n *integrationNode) (bool, error) {
+		b, err := nw.tokenBalance(n, contract, holder)
+		if err != nil {
+			return false, err
+		}
+
+		return b.Equal(common.NewBig(amount)), fmt.Errorf("token balance of %v, %v", holder, b)
+	}
+}
+
+func (nw *integrationNetwork) allowanceIs(
+	contract, owner, spender base.Address, amount int64,
+) func(*integrationNode) (bool, error) {
+	return func(n *integrationNode) (bool, error) {
+		design, err := nw.design(n, contract)
+		if err != nil {
+			return false, err
+		}
+
+		allowance := common.ZeroBig
+
+		for _, box := range design.Policy().ApproveList() {
+			if !box.Account().Equal(owner) {
+				continue
+			}
+
+			for _, info := range box.Approved() {
+				if info.Account().Equal(spender) {
+					allowance = info.Amount()
+				}
+			}
+		}
+
+		return allowance.Equal(common.NewBig(amount)), fmt.Errorf("allowance of %v by %v, %v", spender, owner, allowance)
+	}
+}
+
+func integrationAmounts(amount int64) []ctypes.Amount {
+	return []ctypes.Amount{ctypes.NewAmount(common.NewBig(amount), integrationCurrency)}
+}
+
+func integrationToken() []byte {
+	return []byte(util.UUID().String())
+}
+
+// TestIntegrationNetwork registers the token and sends the token operations to
+// the network, and then checks the blocks and the token states of every node
+// and the digest API of the first node.
+func TestIntegrationNetwork(t *testing.T) {
+	nw := newIntegrationNetwork(t)
+	nw.init()
+	nw.start()
+
+	for _, n := range nw.nodes {
+		n := n
+
+		nw.waitFor(fmt.Sprintf("%v joined", n.address), func() (bool, error) {
+			height, err := nw.lastHeight(n)
+
+			return height > base.GenesisHeight+1, err
+		})
+	}
+
+	cid := ctypes.CurrencyID(integrationCurrency)
+	g := nw.genesis
+	a, b := newIntegrationAccount(t), newIntegrationAccount(t)
+	contract := newIntegrationAccount(t)
+	genesisContract := nw.contract.address
+
+	nw.t.Log("genesis token", genesisContract)
+
+	// NOTE the operations are sent to the different nodes; each operation
+	// is waited until processed in every node.
+	accounts, err := currency.NewCreateAccount(currency.NewCreateAccountFact(integrationToken(), g.address,
+		[]currency.CreateAccountItem{
+			currency.NewCreateAccountItemMultiAmounts(a.keys, integrationAmounts(1000)),
+			currency.NewCreateAccountItemMultiAmounts(b.keys, integrationAmounts(1000)),
+		}, cid))
+	if err != nil {
+		t.Fatalf("create-accounts: %v", err)
+	}
+
+	nw.send("create-accounts", nw.nodes[0], &accounts, g.priv, func(n *integrationNode) (bool, error) {
+		if ok, err := nw.exists(ccstate.AccountStateKey(a.address))(n); !ok {
+			return ok, err
+		}
+
+		return nw.exists(ccstate.AccountStateKey(b.address))(n)
+	})
+
+	contractAccount, err := extension.NewCreateContractAccount(extension.NewCreateContractAccountFact(
+		integrationToken(), g.address, []extension.CreateContractAccountItem{
+			extension.NewCreateContractAccountItemMultiAmounts(contract.keys, integrationAmounts(1000)),
+		}, cid))
+	if err != nil {
+		t.Fatalf("create-contract-account: %v", err)
+	}
+
+	nw.send("create-contract-account", nw.nodes[1], &contractAccount, g.priv,
+		nw.exists(cestate.StateKeyContractAccount(contract.address)))
+
+	register := token.NewRegisterModel(token.NewRegisterModelFact(integrationToken(), g.address, contract.address,
+		cid, types.TokenSymbol("ITK"), "integration token", common.NewBig(9), common.NewBig(1000)))
+	nw.send("register-model", nw.nodes[2], &register, g.priv, nw.balanceIs(contract.address, g.address, 1000))
+
+	mint := token.NewMint(token.NewMintFact(integrationToken(), g.address, contract.address, cid, a.address,
+		common.NewBig(500)))
+	nw.send("mint", nw.nodes[0], &mint, g.priv, nw.balanceIs(contract.address, a.address, 500))
+
+	transfer := token.NewTransfer(token.NewTransferFact(integrationToken(), g.address, []token.TransferItem{
+		token.NewTransferItem(contract.address, b.address, common.NewBig(100)),
+		token.NewTransferItem(genesisContract, a.address, common.NewBig(3000)),
+	}, cid))
+	nw.send("transfer", nw.nodes[1], &transfer, g.priv, nw.balanceIs(genesisContract, a.address, 3000))
+
+	approve := token.NewApprove(token.NewApproveFact(integrationToken(), a.address, []token.ApproveItem{
+		token.NewApproveItem(contract.address, b.address, common.NewBig(200)),
+	}, cid))
+	nw.send("approve", nw.nodes[2], &approve, a.priv, nw.allowanceIs(contract.address, a.address, b.address, 200))
+
+	transferFrom := token.NewTransferFrom(token.NewTransferFromFact(integrationToken(), b.address,
+		[]token.TransferFromItem{
+			token.NewTransferFromItem(contract.address, g.address, a.address, common.NewBig(150)),
+		}, cid))
+	nw.send("transfer-from", nw.nodes[0], &transferFrom, b.priv,
+		nw.allowanceIs(contract.address, a.address, b.address, 50))
+
+	burn := token.NewBurn(token.NewBurnFact(integrationToken(), b.address, contract.address, cid, b.address,
+		common.NewBig(40)))
+	nw.send("burn", nw.nodes[1], &burn, b.priv, nw.balanceIs(contract.address, b.address, 60))
+
+	expected := []integrationTokenBalances{
+		{
+			contract: contract.address,
+			supply:   1000 + 500 - 40,
+			holders:  []base.Address{g.address, a.address, b.address},
+			amounts:  []int64{900 + 150, 500 - 150, 100 - 40},
+		},
+		{
+			contract: genesisContract,
+			supply:   1000000,
+			holders:  []base.Address{g.address, a.address},
+			amounts:  []int64{1000000 - 3000, 3000},
+		},
+	}
+
+	nw.checkConsensus()
+	nw.checkTokens(expected)
+	nw.checkDigest(expected, "ITK", contract.address)
+}
+
+// checkConsensus checks the every node has the same block at the highest
+// common height; the manifest hash includes the previous manifest, so the
+// lower blocks are also same.
+func (nw *integrationNetwork) checkConsensus() {
+	nw.t.Helper()
+
+	top := base.NilHeight
+
+	for _, n := range nw.nodes {
+		height, err := nw.lastHeight(n)
+		if err != nil {
+			nw.t.Fatalf("last height of %v: %v", n.address, err)
+		}
+
+		if top == base.NilHeight || height < top {
+			top = height
+		}
+	}
+
+	var manifest string
+
+	for _, n := range nw.nodes {
+		bm, err := nw.blockMap(n, top)
+		if err != nil {
+			nw.t.Fatalf("blockmap: %v", err)
+		}
+
+		switch h := bm.Manifest().Hash().String(); {
+		case len(manifest) < 1:
+			manifest = h
+		case h != manifest:
+			nw.t.Fatalf("height=%d: manifest of %v, %v not matched with %v", top, n.address, h, manifest)
+		}
+	}
+
+	nw.t.Logf("blocks matched in every node until %d", top)
+}
+
+type integrationTokenBalances struct {
+	contract base.Address
+	holders  []base.Address
+	amounts  []int64
+	supply   int64
+}
+
+// checkTokens checks the token balances and total supplies in every node.
+func (nw *integrationNetwork) checkTokens(expected []integrationTokenBalances) {
+	nw.t.Helper()
+
+	for _, n := range nw.nodes {
+		for _, e := range expected {
+			design, err := nw.design(n, e.contract)
+			if err != nil {
+				nw.t.Fatalf("design of %v in %v: %v", e.contract, n.address, err)
+			}
+
+			if ts := design.Policy().TotalSupply(); !ts.Equal(common.NewBig(e.supply)) {
+				nw.t.Fatalf("total supply of %v in %v, expected %d, got %v", e.contract, n.address, e.supply, ts)
+			}
+
+			var sum int64
+
+			for i := range e.holders {
+				if ok, err := nw.balanceIs(e.contract, e.holders[i], e.amounts[i])(n); !ok {
+					nw.t.Fatalf("token balance in %v of %v: %v", e.contract, n.address, err)
+				}
+
+				sum += e.amounts[i]
+			}
+
+			if sum != e.supply {
+				nw.t.Fatalf("supply of %v not conserved; balances=%d supply=%d", e.contract, sum, e.supply)
+			}
+		}
+	}
+}
+
+func (nw *integrationNetwork) apiGet(path string, v interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, nw.api+path, nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: status=%d body=%s", path, res.StatusCode, b)
+	}
+
+	return json.Unmarshal(b, v)
+}
+
+// checkDigest checks the digest API of the first node serves the token
+// states processed by the network.
+func (nw *integrationNetwork) checkDigest(
+	expected []integrationTokenBalances, symbol string, symbolContract base.Address,
+) {
+	nw.t.Helper()
+
+	for _, e := range expected {
+		e := e
+		supply := fmt.Sprintf("%d", e.supply)
+
+		nw.waitFor(fmt.Sprintf("digest token %v", e.contract), func() (bool, error) {
+			var u struct {
+				Embedded struct {
+					Policy struct {
+						TotalSupply string `json:"total_supply"`
+					} `json:"policy"`
+				} `json:"_embedded"`
+			}
+
+			if err := nw.apiGet("/token/"+e.contract.String(), &u); err != nil {
+				return false, err
+			}
+
+			return u.Embedded.Policy.TotalSupply == supply,
+				fmt.Errorf("total supply, expected %s, got %s", supply, u.Embedded.Policy.TotalSupply)
+		})
+
+		for i := range e.holders {
+			holder := e.holders[i]
+			amount := fmt.Sprintf("%d", e.amounts[i])
+
+			nw.waitFor(fmt.Sprintf("digest token balance %v of %v", holder, e.contract), func() (bool, error) {
+				var u struct {
+					Embedded struct {
+						Amount string `json:"amount"`
+					} `json:"_embedded"`
+				}
+
+				if err := nw.apiGet("/token/"+e.contract.String()+"/account/"+holder.String(), &u); err != nil {
+					return false, err
+				}
+
+				return u.Embedded.Amount == amount,
+					fmt.Errorf("token balance, expected %s, got %s", amount, u.Embedded.Amount)
+			})
+		}
+	}
+
+	nw.waitFor("digest token symbol "+symbol, func() (bool, error) {
+		var u struct {
+			Embedded digest.TokenSymbolEntry `json:"_embedded"`
+		}
+
+		if err := nw.apiGet("/token/symbol/"+symbol, &u); err != nil {
+			return false, err
+		}
+
+		return u.Embedded.Contract == symbolContract.String() && !u.Embedded.Released,
+			fmt.Errorf("token symbol, %+v", u.Embedded)
+	})
+
+	nw.t.Logf("digest API matched with the states; %d requests to mongodb", atomic.LoadInt32(&nw.mongo.requests))
+}
@@ -0,0 +1,170 @@
+package cmds
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/pkg/errors"
+)
+
+// ItemsFileFlag is the file of operation items. A file with ".json"
+// extension is a JSON array of objects keyed by the column names; otherwise
+// it is CSV with the columns in order. Empty lines, lines starting with "#"
+// and a header line of the column names are ignored in CSV.
+//
+// The items over the max items of an operation are split into several
+// operations. They have the same sender and contract, so a proposal accepts
+// only one of them; send them by "network client send-operation --wait",
+// which sends each after the previous one is in state.
+type ItemsFileFlag struct {
+	ItemsFile string `name:"items-file" help:"items file in csv or json; operations are split by max items, send them by send-operation --wait" type:"existingfile" placeholder:"PATH"` // revive:disable-line:line-length-limit
+}
+
+func (f ItemsFileFlag) IsSet() bool {
+	return len(f.ItemsFile) > 0
+}
+
+// itemsFileRow is a row of items file; line is the line number in CSV or the
+// index in JSON, for error messages.
+type itemsFileRow struct {
+	values map[string]string
+	line   int
+}
+
+func (r itemsFileRow) address(column string) (base.Address, error) {
+	a, err := base.DecodeAddress(strings.TrimSpace(r.values[column]), enc)
+	if err != nil {
+		return nil, errors.Wrapf(err, "row %d, invalid %s, %q", r.line, column, r.values[column])
+	}
+
+	return a, nil
+}
+
+func (r itemsFileRow) amount(column string, parse func(string) (common.Big, error)) (common.Big, error) {
+	b, err := parse(r.values[column])
+	if err != nil {
+		return common.Big{}, errors.WithMessagef(err, "row %d, invalid %s", r.line, column)
+	}
+
+	if err := b.IsValid(nil); err != nil {
+		return common.Big{}, errors.Wrapf(err, "row %d, invalid %s", r.line, column)
+	}
+
+	return b, nil
+}
+
+func (f ItemsFileFlag) load(columns ...string) ([]itemsFileRow, error) {
+	b, err := os.ReadFile(filepath.Clean(f.ItemsFile))
+	if err != nil {
+		return nil, errors.WithMessage(err, "read items file")
+	}
+
+	var rows []itemsFileRow
+
+	if strings.EqualFold(filepath.Ext(f.ItemsFile), ".json") {
+		rows, err = loadJSONItemsFile(b, columns)
+	} else {
+		rows, err = loadCSVItemsFile(b, columns)
+	}
+
+	switch {
+	case err != nil:
+		return nil, errors.WithMessagef(err, "load items file, %q", f.ItemsFile)
+	case len(rows) < 1:
+		return nil, errors.Errorf("empty items file, %q", f.ItemsFile)
+	default:
+		return rows, nil
+	}
+}
+
+func loadCSVItemsFile(b []byte, columns []string) ([]itemsFileRow, error) {
+	r := csv.NewReader(bytes.NewReader(b))
+	r.Comment = '#'
+	r.FieldsPerRecord = len(columns)
+	r.TrimLeadingSpace = true
+
+	var rows []itemsFileRow
+
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		line, _ := r.FieldPos(0)
+
+		if len(rows) < 1 && strings.EqualFold(strings.TrimSpace(record[0]), columns[0]) {
+			continue
+		}
+
+		values := map[string]string{}
+		for i := range columns {
+			values[columns[i]] = record[i]
+		}
+
+		rows = append(rows, itemsFileRow{values: values, line: line})
+	}
+
+	return rows, nil
+}
+
+func loadJSONItemsFile(b []byte, columns []string) ([]itemsFileRow, error) {
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+
+	var l []map[string]interface{}
+	if err := d.Decode(&l); err != nil {
+		return nil, err
+	}
+
+	rows := make([]itemsFileRow, len(l))
+
+	for i := range l {
+		values := map[string]string{}
+
+		for j := range columns {
+			v, found := l[i][columns[j]]
+			if !found {
+				return nil, errors.Errorf("row %d, missing %s", i, columns[j])
+			}
+
+			values[columns[j]] = fmt.Sprintf("%v", v)
+		}
+
+		rows[i] = itemsFileRow{values: values, line: i}
+	}
+
+	return rows, nil
+}
+
+// splitItems splits items into chunks of at most n items. The operations of
+// the chunks share the duplication key of sender, so they can not be in the
+// same block.
+func splitItems[T any](items []T, n int) [][]T {
+	if n < 1 {
+		n = len(items)
+	}
+
+	chunks := make([][]T, 0, (len(items)+n-1)/n)
+
+	for i := 0; i < len(items); i += n {
+		end := i + n
+		if end > len(items) {
+			end = len(items)
+		}
+
+		chunks = append(chunks, items[i:end])
+	}
+
+	return chunks
+}
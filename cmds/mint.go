@@ -14,7 +14,7 @@ import (
 type MintCommand struct {
 	OperationCommand
 	Receiver ccmds.AddressFlag `arg:"" name:"receiver" help:"token receiver" required:"true"`
-	Amount   ccmds.BigFlag     `arg:"" name:"amount" help:"amount to mint" required:"true"`
+	Amount   string            `arg:"" name:"amount" help:"amount to mint; raw units, or token units with --human or symbol suffix like \"12.5MCT\"" required:"true"`
 	receiver base.Address
 }
 
@@ -54,16 +54,21 @@ func (cmd *MintCommand) parseFlags() error {
 func (cmd *MintCommand) createOperation() (base.Operation, error) { // nolint:dupl}
 	e := util.StringError(utils.ErrStringCreate("mint operation"))
 
+	amount, err := cmd.amount(cmd.Amount)
+	if err != nil {
+		return nil, e.Wrap(err)
+	}
+
 	fact := token.NewMintFact(
 		[]byte(cmd.Token),
 		cmd.sender, cmd.contract,
 		cmd.Currency.CID,
 		cmd.receiver,
-		cmd.Amount.Big,
+		amount,
 	)
 
 	op := token.NewMint(fact)
-	if err := op.Sign(cmd.Privatekey, cmd.NetworkID.NetworkID()); err != nil {
+	if err := cmd.sign(&op); err != nil {
 		return nil, e.Wrap(err)
 	}
 
@@ -0,0 +1,232 @@
+package cmds
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/token-model/state"
+	"github.com/imfact-labs/token-model/types"
+	"github.com/pkg/errors"
+)
+
+type NetworkClientTokenCommand struct { //nolint:govet //...
+	//revive:disable:line-length-limit
+	Design    NetworkClientTokenDesignCommand    `cmd:"" name:"design" help:"get token design"`
+	Balance   NetworkClientTokenBalanceCommand   `cmd:"" name:"balance" help:"get token balance"`
+	Allowance NetworkClientTokenAllowanceCommand `cmd:"" name:"allowance" help:"get token allowance"`
+	Simulate  NetworkClientTokenSimulateCommand  `cmd:"" name:"simulate" help:"simulate token operation without sending"`
+	//revive:enable:line-length-limit
+}
+
+type TokenAmount struct {
+	Raw       common.Big `json:"raw"`
+	Formatted string     `json:"formatted"`
+}
+
+func NewTokenAmount(amount, decimal common.Big) TokenAmount {
+	return TokenAmount{Raw: amount, Formatted: formatTokenAmount(amount, decimal)}
+}
+
+type BaseNetworkClientTokenCommand struct { //nolint:govet //...
+	BaseNetworkClientCommand
+	Contract string `arg:"" name:"contract" help:"token contract account" required:"true"`
+	contract base.Address
+}
+
+func (cmd *BaseNetworkClientTokenCommand) Prepare(pctx context.Context) error {
+	if err := cmd.BaseNetworkClientCommand.Prepare(pctx); err != nil {
+		return err
+	}
+
+	a, err := base.DecodeAddress(strings.TrimSpace(cmd.Contract), cmd.Encoder)
+	if err != nil {
+		return errors.Wrapf(err, "invalid contract account, %q", cmd.Contract)
+	}
+	cmd.contract = a
+
+	return nil
+}
+
+func (cmd *BaseNetworkClientTokenCommand) state(ctx context.Context, key string) (base.State, bool, error) {
+	nctx, cancel := context.WithTimeout(ctx, cmd.Timeout)
+	defer cancel()
+
+	st, found, err := cmd.Client.State(nctx, cmd.Remote.ConnInfo(), key, nil)
+	if err != nil {
+		cmd.Log.Error().Err(err).Str("key", key).Msg("failed to get state")
+
+		return nil, false, err
+	}
+
+	return st, found, nil
+}
+
+func (cmd *BaseNetworkClientTokenCommand) design(ctx context.Context) (types.Design, base.Height, error) {
+	switch st, found, err := cmd.state(ctx, state.NewStateKeyGenerator(cmd.contract.String()).Design()); {
+	case err != nil:
+		return types.Design{}, base.NilHeight, err
+	case !found:
+		return types.Design{}, base.NilHeight, errors.Errorf("token design not found, contract %v", cmd.contract)
+	default:
+		design, err := state.StateDesignValue(st)
+		if err != nil {
+			return types.Design{}, base.NilHeight, err
+		}
+
+		return *design, st.Height(), nil
+	}
+}
+
+func (cmd *BaseNetworkClientTokenCommand) address(s string) (base.Address, error) {
+	a, err := base.DecodeAddress(strings.TrimSpace(s), cmd.Encoder)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid account, %q", s)
+	}
+
+	return a, nil
+}
+
+type NetworkClientTokenDesignCommand struct { //nolint:govet //...
+	BaseNetworkClientTokenCommand
+}
+
+func (cmd *NetworkClientTokenDesignCommand) Run(pctx context.Context) error {
+	if err := cmd.Prepare(pctx); err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = cmd.Client.Close()
+	}()
+
+	design, height, err := cmd.design(pctx)
+	if err != nil {
+		return err
+	}
+
+	return cmd.Print(struct {
+		Contract    base.Address `json:"contract"`
+		Height      base.Height  `json:"height"`
+		Design      types.Design `json:"design"`
+		TotalSupply TokenAmount  `json:"total_supply"`
+	}{
+		Contract:    cmd.contract,
+		Height:      height,
+		Design:      design,
+		TotalSupply: NewTokenAmount(design.Policy().TotalSupply(), design.Decimal()),
+	}, os.Stdout)
+}
+
+type NetworkClientTokenBalanceCommand struct { //nolint:govet //...
+	BaseNetworkClientTokenCommand
+	Address string `arg:"" name:"address" help:"account" required:"true"`
+}
+
+func (cmd *NetworkClientTokenBalanceCommand) Run(pctx context.Context) error {
+	if err := cmd.Prepare(pctx); err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = cmd.Client.Close()
+	}()
+
+	account, err := cmd.address(cmd.Address)
+	if err != nil {
+		return err
+	}
+
+	design, _, err := cmd.design(pctx)
+	if err != nil {
+		return err
+	}
+
+	amount := common.ZeroBig
+	height := base.NilHeight
+
+	key := state.NewStateKeyGenerator(cmd.contract.String()).TokenBalance(account.String())
+
+	switch st, found, err := cmd.state(pctx, key); {
+	case err != nil:
+		return err
+	case found:
+		i, err := state.StateTokenBalanceValue(st)
+		if err != nil {
+			return err
+		}
+
+		amount = i
+		height = st.Height()
+	}
+
+	return cmd.Print(struct {
+		Contract base.Address      `json:"contract"`
+		Address  base.Address      `json:"address"`
+		Symbol   types.TokenSymbol `json:"symbol"`
+		Height   base.Height       `json:"height"`
+		Amount   TokenAmount       `json:"amount"`
+	}{
+		Contract: cmd.contract,
+		Address:  account,
+		Symbol:   design.Symbol(),
+		Height:   height,
+		Amount:   NewTokenAmount(amount, design.Decimal()),
+	}, os.Stdout)
+}
+
+type NetworkClientTokenAllowanceCommand struct { //nolint:govet //...
+	BaseNetworkClientTokenCommand
+	Owner   string `arg:"" name:"owner" help:"owner account" required:"true"`
+	Spender string `arg:"" name:"spender" help:"approved account" required:"true"`
+}
+
+func (cmd *NetworkClientTokenAllowanceCommand) Run(pctx context.Context) error {
+	if err := cmd.Prepare(pctx); err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = cmd.Client.Close()
+	}()
+
+	owner, err := cmd.address(cmd.Owner)
+	if err != nil {
+		return err
+	}
+
+	spender, err := cmd.address(cmd.Spender)
+	if err != nil {
+		return err
+	}
+
+	design, height, err := cmd.design(pctx)
+	if err != nil {
+		return err
+	}
+
+	amount := common.ZeroBig
+	if apb := design.Policy().GetApproveBox(owner); apb != nil {
+		if info := apb.GetApproveInfo(spender); info != nil {
+			amount = info.Amount()
+		}
+	}
+
+	return cmd.Print(struct {
+		Contract base.Address      `json:"contract"`
+		Owner    base.Address      `json:"owner"`
+		Spender  base.Address      `json:"spender"`
+		Symbol   types.TokenSymbol `json:"symbol"`
+		Height   base.Height       `json:"height"`
+		Amount   TokenAmount       `json:"amount"`
+	}{
+		Contract: cmd.contract,
+		Owner:    owner,
+		Spender:  spender,
+		Symbol:   design.Symbol(),
+		Height:   height,
+		Amount:   NewTokenAmount(amount, design.Decimal()),
+	}, os.Stdout)
+}
@@ -0,0 +1,278 @@
+package cmds
+
+import (
+	"context"
+	"os"
+
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/launch"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/token-model/runtime/steps"
+	"github.com/pkg/errors"
+)
+
+type NetworkClientTokenSimulateCommand struct { //nolint:govet //...
+	BaseNetworkClientCommand
+	Input    string `arg:"" name:"input" help:"input of operation or json array of operations; default is stdin" default:"-"`
+	IsString bool   `name:"input.is-string" help:"input is string, not file"`
+	Design   string `name:"design" help:"node design file, of which token parameters are used" type:"existingfile" placeholder:"PATH"` // revive:disable-line:line-length-limit
+}
+
+type TokenStateDiff struct {
+	Key    string          `json:"key"`
+	Before base.StateValue `json:"before"`
+	After  base.StateValue `json:"after"`
+}
+
+// TokenSimulation is the result of simulated operation; Stage is where the
+// operation failed with Reason, one of "is-valid", "preprocess" and "process".
+type TokenSimulation struct {
+	Fact   util.Hash        `json:"fact"`
+	Stage  string           `json:"stage,omitempty"`
+	Reason string           `json:"reason,omitempty"`
+	Diffs  []TokenStateDiff `json:"diffs,omitempty"`
+}
+
+// Run processes the operations against the states of remote node without
+// sending them. The operations of array are processed like in one proposal;
+// every operation is preprocessed in order against the states of the last
+// block, and then processed against the same states. The states after are
+// merged from the values of the operation and the former ones, like the states
+// of the next block.
+func (cmd *NetworkClientTokenSimulateCommand) Run(pctx context.Context) error {
+	if err := cmd.Prepare(pctx); err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = cmd.Client.Close()
+	}()
+
+	var ops []base.Operation
+	var isArray bool
+
+	switch i, err := launch.LoadInputFlag(cmd.Input, !cmd.IsString); {
+	case err != nil:
+		return err
+	case len(i) < 1:
+		return errors.Errorf("empty input")
+	default:
+		if ops, err = decodeOperations(cmd.Encoder, i); err != nil {
+			return err
+		}
+
+		isArray = isJSONArray(i)
+	}
+
+	height, err := cmd.nextHeight(pctx)
+	if err != nil {
+		return err
+	}
+
+	sts := &simulationStates{
+		fetch: func(key string) (base.State, bool, error) {
+			ctx, cancel := context.WithTimeout(pctx, cmd.Timeout)
+			defer cancel()
+
+			return cmd.Client.State(ctx, cmd.Remote.ConnInfo(), key, nil)
+		},
+		m: map[string]base.State{},
+	}
+
+	params := steps.DefaultTokenParams()
+	if len(cmd.Design) > 0 {
+		if params, err = steps.LoadTokenParams(cmd.Design); err != nil {
+			return err
+		}
+	}
+
+	newOperationProcessor, err := steps.NewOperationProcessorFunc(params)
+	if err != nil {
+		return err
+	}
+
+	opp, err := newOperationProcessor(height, sts.get)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = opp.Close()
+	}()
+
+	rs := make([]TokenSimulation, len(ops))
+	passed := make([]bool, len(ops))
+
+	// NOTE the context of PreProcess is passed to the next operation like in
+	// proposal, so the reservations of the former are seen by the latter.
+	ctx := pctx
+
+	for i := range ops {
+		op := ops[i]
+		rs[i] = TokenSimulation{Fact: op.Fact().Hash()}
+
+		if err := op.IsValid(base.NetworkID(cmd.NetworkID)); err != nil {
+			rs[i].Stage, rs[i].Reason = "is-valid", err.Error()
+
+			continue
+		}
+
+		switch nctx, reason, err := opp.PreProcess(ctx, op, sts.get); {
+		case err != nil:
+			return errors.WithMessagef(err, "preprocess operation %d", i)
+		case reason != nil:
+			rs[i].Stage, rs[i].Reason = "preprocess", reason.Error()
+			ctx = nctx
+		default:
+			passed[i] = true
+			ctx = nctx
+		}
+	}
+
+	facts := make([]util.Hash, len(ops))
+	values := make([][]base.StateMergeValue, len(ops))
+
+	for i := range ops {
+		facts[i] = ops[i].Fact().Hash()
+
+		if !passed[i] {
+			continue
+		}
+
+		switch smvs, reason, err := opp.Process(pctx, ops[i], sts.get); {
+		case err != nil:
+			return errors.WithMessagef(err, "process operation %d", i)
+		case reason != nil:
+			rs[i].Stage, rs[i].Reason = "process", reason.Error()
+		default:
+			values[i] = smvs
+		}
+	}
+
+	for i := range values {
+		if len(values[i]) < 1 {
+			continue
+		}
+
+		diffs, err := sts.diffs(height, facts[:i+1], values[:i+1])
+		if err != nil {
+			return errors.WithMessagef(err, "merge states of operation %d", i)
+		}
+
+		rs[i].Diffs = diffs
+	}
+
+	if isArray {
+		return cmd.Print(rs, os.Stdout)
+	}
+
+	return cmd.Print(rs[0], os.Stdout)
+}
+
+func (cmd *NetworkClientTokenSimulateCommand) nextHeight(ctx context.Context) (base.Height, error) {
+	nctx, cancel := context.WithTimeout(ctx, cmd.Timeout)
+	defer cancel()
+
+	switch bm, found, err := cmd.Client.LastBlockMap(nctx, cmd.Remote.ConnInfo(), nil); {
+	case err != nil:
+		return base.NilHeight, errors.WithMessage(err, "get last blockmap")
+	case !found:
+		return base.NilHeight, errors.Errorf("last blockmap not found")
+	default:
+		return bm.Manifest().Height() + 1, nil
+	}
+}
+
+// simulationStates keeps the states of the last block fetched from the remote
+// node.
+type simulationStates struct {
+	fetch func(string) (base.State, bool, error)
+	m     map[string]base.State
+}
+
+func (s *simulationStates) get(key string) (base.State, bool, error) {
+	if st, found := s.m[key]; found {
+		return st, st != nil, nil
+	}
+
+	st, found, err := s.fetch(key)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if !found {
+		st = nil
+	}
+
+	s.m[key] = st
+
+	return st, found, nil
+}
+
+// diffs returns the states changed by the last values; the states after are
+// merged from the values in order on the states of the last block. Like the
+// states merger of block, the ignored states are not returned.
+func (s *simulationStates) diffs(
+	height base.Height, facts []util.Hash, values [][]base.StateMergeValue,
+) ([]TokenStateDiff, error) {
+	last := values[len(values)-1]
+
+	var keys []string
+	founds := map[string]struct{}{}
+
+	for i := range last {
+		if _, found := founds[last[i].Key()]; !found {
+			founds[last[i].Key()] = struct{}{}
+			keys = append(keys, last[i].Key())
+		}
+	}
+
+	diffs := make([]TokenStateDiff, 0, len(keys))
+
+	for i := range keys {
+		before, _, err := s.get(keys[i])
+		if err != nil {
+			return nil, err
+		}
+
+		var merger base.StateValueMerger
+
+		for j := range values {
+			for k := range values[j] {
+				smv := values[j][k]
+				if smv.Key() != keys[i] {
+					continue
+				}
+
+				if merger == nil {
+					merger = smv.Merger(height, before)
+				}
+
+				if err := merger.Merge(smv.Value(), facts[j]); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		st, err := merger.CloseValue()
+
+		switch {
+		case errors.Is(err, base.ErrIgnoreStateValue):
+			continue
+		case err != nil:
+			return nil, err
+		case st == nil:
+			continue
+		}
+
+		d := TokenStateDiff{Key: keys[i], After: st.Value()}
+
+		if before != nil {
+			d.Before = before.Value()
+		}
+
+		diffs = append(diffs, d)
+	}
+
+	return diffs, nil
+}
@@ -1,9 +1,12 @@
 package cmds
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/imfact-labs/mitum2/base"
 	"github.com/imfact-labs/mitum2/isaac"
@@ -28,6 +31,7 @@ type NetworkClientCommand struct { //nolint:govet //...
 		Write NetworkClientWriteNodeCommand `cmd:"" name:"write" help:"write node value"`
 	} `cmd:"" name:"design" help:""`
 	Event launchcmd.NetworkClientEventLoggingCommand `cmd:"" name:"event" help:"event log"`
+	Token NetworkClientTokenCommand                  `cmd:"" name:"token" help:"token states"`
 	//revive:enable:nested-structs
 	//revive:enable:line-length-limit
 }
@@ -52,20 +56,12 @@ func (cmd *BaseNetworkClientCommand) Prepare(pctx context.Context) error {
 		cmd.Timeout = isaac.DefaultTimeoutRequest * 2
 	}
 
-	connectionPool, err := launch.NewConnectionPool(
-		1<<9, //nolint:gomnd //...
-		base.NetworkID(cmd.NetworkID),
-		nil,
-	)
+	client, err := newNetworkClient(cmd.Encoders, cmd.Encoder, base.NetworkID(cmd.NetworkID))
 	if err != nil {
 		return err
 	}
 
-	cmd.Client = isaacnetwork.NewBaseClient(
-		cmd.Encoders, cmd.Encoder,
-		connectionPool.Dial,
-		connectionPool.CloseAll,
-	)
+	cmd.Client = client
 	cmd.Client.SetClientID(cmd.ClientID)
 
 	cmd.Log.Debug().
@@ -78,6 +74,50 @@ func (cmd *BaseNetworkClientCommand) Prepare(pctx context.Context) error {
 	return nil
 }
 
+func newNetworkClient(
+	encs *encoder.Encoders, enc encoder.Encoder, networkID base.NetworkID,
+) (*isaacnetwork.BaseClient, error) {
+	connectionPool, err := launch.NewConnectionPool(
+		1<<9, //nolint:gomnd //...
+		networkID,
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return isaacnetwork.NewBaseClient(
+		encs, enc,
+		connectionPool.Dial,
+		connectionPool.CloseAll,
+	), nil
+}
+
+// remoteState gets the state of key from the remote node of conn info string,
+// for the commands without BaseNetworkClientCommand.
+func remoteState(
+	encs *encoder.Encoders, enc encoder.Encoder, networkID base.NetworkID, remote, key string,
+) (base.State, bool, error) {
+	var ci launch.ConnInfoFlag
+	if err := ci.UnmarshalText([]byte(remote)); err != nil {
+		return nil, false, err
+	}
+
+	client, err := newNetworkClient(encs, enc, networkID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	defer func() {
+		_ = client.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), isaac.DefaultTimeoutRequest*2)
+	defer cancel()
+
+	return client.State(ctx, ci.ConnInfo(), key, nil)
+}
+
 func (cmd *BaseNetworkClientCommand) Print(v interface{}, out io.Writer) error {
 	l := cmd.Log.Debug().
 		Str("type", fmt.Sprintf("%T", v))
@@ -98,10 +138,15 @@ func (cmd *BaseNetworkClientCommand) Print(v interface{}, out io.Writer) error {
 	return errors.WithStack(err)
 }
 
+// NetworkClientSendOperationCommand sends the operations of input in order.
+// With --wait, each operation is sent after the previous one is in state, like
+// the operations of --items-file, which can not be in the same block.
 type NetworkClientSendOperationCommand struct { //nolint:govet //...
 	BaseNetworkClientCommand
-	Input    string `arg:"" name:"input" help:"input; default is stdin" default:"-"`
-	IsString bool   `name:"input.is-string" help:"input is string, not file"`
+	Input       string        `arg:"" name:"input" help:"input of operation or json array of operations; default is stdin" default:"-"`
+	IsString    bool          `name:"input.is-string" help:"input is string, not file"`
+	Wait        bool          `name:"wait" help:"send next operation after the previous one is in state"`
+	WaitTimeout time.Duration `name:"wait.timeout" help:"timeout to wait operation in state" default:"1m"`
 }
 
 func (cmd *NetworkClientSendOperationCommand) Run(pctx context.Context) error {
@@ -113,7 +158,7 @@ func (cmd *NetworkClientSendOperationCommand) Run(pctx context.Context) error {
 		_ = cmd.Client.Close()
 	}()
 
-	var op base.Operation
+	var ops []base.Operation
 
 	switch i, err := launch.LoadInputFlag(cmd.Input, !cmd.IsString); {
 	case err != nil:
@@ -125,24 +170,105 @@ func (cmd *NetworkClientSendOperationCommand) Run(pctx context.Context) error {
 			Str("input", string(i)).
 			Msg("input")
 
-		if err := encoder.Decode(cmd.Encoder, i, &op); err != nil {
+		if ops, err = decodeOperations(cmd.Encoder, i); err != nil {
+			return err
+		}
+	}
+
+	for i := range ops {
+		if err := cmd.send(pctx, ops[i]); err != nil {
 			return err
 		}
+
+		if !cmd.Wait {
+			continue
+		}
+
+		if err := cmd.waitInState(pctx, ops[i]); err != nil {
+			return errors.WithMessagef(err, "operation %d", i)
+		}
 	}
 
+	return nil
+}
+
+// waitInState waits until the operation is in state of the remote node. The
+// operation, which fails in the block, is not in state, so it returns error by
+// timeout.
+func (cmd *NetworkClientSendOperationCommand) waitInState(pctx context.Context, op base.Operation) error {
+	ctx, cancel := context.WithTimeout(pctx, cmd.WaitTimeout)
+	defer cancel()
+
+	l := cmd.Log.With().Stringer("fact", op.Fact().Hash()).Logger()
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		switch found, err := cmd.Client.ExistsInStateOperation(ctx, cmd.Remote.ConnInfo(), op.Fact().Hash()); {
+		case err != nil && ctx.Err() == nil:
+			l.Debug().Err(err).Msg("failed to check in state; will retry")
+		case found:
+			l.Info().Msg("in state")
+
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return errors.Errorf("not in state in %v, %v", cmd.WaitTimeout, op.Fact().Hash())
+		case <-ticker.C:
+		}
+	}
+}
+
+func (cmd *NetworkClientSendOperationCommand) send(pctx context.Context, op base.Operation) error {
 	ctx, cancel := context.WithTimeout(pctx, cmd.Timeout)
 	defer cancel()
 
+	l := cmd.Log.With().Stringer("fact", op.Fact().Hash()).Logger()
+
 	switch sent, err := cmd.Client.SendOperation(ctx, cmd.Remote.ConnInfo(), op); {
 	case err != nil:
-		cmd.Log.Error().Err(err).Msg("not sent")
+		l.Error().Err(err).Msg("not sent")
 
 		return err
 	case !sent:
-		cmd.Log.Error().Msg("not sent")
+		l.Error().Msg("not sent")
 	default:
-		cmd.Log.Info().Msg("sent")
+		l.Info().Msg("sent")
 	}
 
 	return nil
 }
+
+// decodeOperations decodes an operation or a JSON array of operations, like
+// the output of token operation commands with --items-file.
+func decodeOperations(enc encoder.Encoder, b []byte) ([]base.Operation, error) {
+	if !isJSONArray(b) {
+		var op base.Operation
+		if err := encoder.Decode(enc, b, &op); err != nil {
+			return nil, err
+		}
+
+		return []base.Operation{op}, nil
+	}
+
+	var l []json.RawMessage
+	if err := util.UnmarshalJSON(b, &l); err != nil {
+		return nil, err
+	}
+
+	ops := make([]base.Operation, len(l))
+	for i := range l {
+		if err := encoder.Decode(enc, l[i], &ops[i]); err != nil {
+			return nil, errors.WithMessagef(err, "operation %d", i)
+		}
+	}
+
+	return ops, nil
+}
+
+func isJSONArray(b []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(b), []byte("["))
+}
@@ -15,7 +15,7 @@ type RegisterModelCommand struct {
 	Symbol        TokenSymbolFlag `arg:"" name:"symbol" help:"token symbol" required:"true"`
 	Name          string          `arg:"" name:"name" help:"token name" required:"true"`
 	Decimal       ccmds.BigFlag   `arg:"" name:"decimal" help:"decimal of token" required:"true"`
-	InitialSupply ccmds.BigFlag   `arg:"" name:"initial-supply" help:"initial supply of token" required:"true"`
+	InitialSupply string          `arg:"" name:"initial-supply" help:"initial supply of token; raw units, or token units with --human or symbol suffix like \"12.5MCT\"" required:"true"`
 }
 
 func (cmd *RegisterModelCommand) Run(pctx context.Context) error { // nolint:dupl
@@ -40,17 +40,26 @@ func (cmd *RegisterModelCommand) Run(pctx context.Context) error { // nolint:dup
 func (cmd *RegisterModelCommand) createOperation() (base.Operation, error) { // nolint:dupl}
 	e := util.StringError(utils.ErrStringCreate("register-model operation"))
 
+	// NOTE the initial supply in token units is converted by the given decimal
+	// and symbol, not by the existing design.
+	cmd.decimal, cmd.symbol = &cmd.Decimal.Big, cmd.Symbol.Symbol
+
+	initialSupply, err := cmd.amount(cmd.InitialSupply)
+	if err != nil {
+		return nil, e.Wrap(err)
+	}
+
 	fact := token.NewRegisterModelFact(
 		[]byte(cmd.Token),
 		cmd.sender, cmd.contract,
 		cmd.Currency.CID, cmd.Symbol.Symbol,
 		cmd.Name,
 		cmd.Decimal.Big,
-		cmd.InitialSupply.Big,
+		initialSupply,
 	)
 
 	op := token.NewRegisterModel(fact)
-	if err := op.Sign(cmd.Privatekey, cmd.NetworkID.NetworkID()); err != nil {
+	if err := cmd.sign(&op); err != nil {
 		return nil, e.Wrap(err)
 	}
 
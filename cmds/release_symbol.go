@@ -0,0 +1,52 @@
+package cmds
+
+import (
+	"context"
+
+	ccmds "github.com/imfact-labs/currency-model/app/cmds"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/token-model/operation/token"
+	"github.com/imfact-labs/token-model/utils"
+)
+
+type ReleaseSymbolCommand struct {
+	OperationCommand
+	Symbol TokenSymbolFlag `arg:"" name:"symbol" help:"token symbol held by contract account" required:"true"`
+}
+
+func (cmd *ReleaseSymbolCommand) Run(pctx context.Context) error { // nolint:dupl
+	if _, err := cmd.prepare(pctx); err != nil {
+		return err
+	}
+
+	if err := cmd.parseFlags(); err != nil {
+		return err
+	}
+
+	op, err := cmd.createOperation()
+	if err != nil {
+		return err
+	}
+
+	ccmds.PrettyPrint(cmd.Out, op)
+
+	return nil
+}
+
+func (cmd *ReleaseSymbolCommand) createOperation() (base.Operation, error) { // nolint:dupl}
+	e := util.StringError(utils.ErrStringCreate("release-symbol operation"))
+
+	fact := token.NewReleaseSymbolFact(
+		[]byte(cmd.Token),
+		cmd.sender, cmd.contract,
+		cmd.Currency.CID, cmd.Symbol.Symbol,
+	)
+
+	op := token.NewReleaseSymbol(fact)
+	if err := cmd.sign(&op); err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	return op, nil
+}
@@ -2,6 +2,8 @@ package cmds
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 
 	apic "github.com/imfact-labs/currency-model/api"
 	ccmds "github.com/imfact-labs/currency-model/app/cmds"
@@ -12,13 +14,16 @@ import (
 	"github.com/imfact-labs/mitum2/util"
 	"github.com/imfact-labs/mitum2/util/logging"
 	"github.com/imfact-labs/mitum2/util/ps"
+	modapi "github.com/imfact-labs/token-model/api"
 	"github.com/imfact-labs/token-model/digest"
 	"github.com/imfact-labs/token-model/runtime/steps"
 	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
 )
 
 type RunCommand struct { //nolint:govet //...
 	ccmds.RunCommand
+	TokenWebhook string `name:"token-webhook" help:"token webhook design file" type:"existingfile" placeholder:"PATH"`
 }
 
 func (cmd *RunCommand) Run(pctx context.Context) error {
@@ -35,6 +40,7 @@ func (cmd *RunCommand) Run(pctx context.Context) error {
 		Interface("http_state", cmd.HTTPState).
 		Interface("dev", cmd.DevFlags).
 		Interface("acl", cmd.ACLFlags).
+		Str("token_webhook", cmd.TokenWebhook).
 		Msg("flags")
 
 	cmd.RunCommand.SetLog(log.Log())
@@ -45,19 +51,59 @@ func (cmd *RunCommand) Run(pctx context.Context) error {
 		}
 	}
 
+	nctx, pps, err := cmd.runPS(pctx, log)
+	if err != nil {
+		return err
+	}
+
+	nctx, err = pps.Run(nctx) //revive:disable-line:modifies-parameter
+	defer func() {
+		log.Log().Debug().Interface("process", pps.Verbose()).Msg("process will be closed")
+
+		if _, err = pps.Close(nctx); err != nil {
+			log.Log().Error().Err(err).Msg("failed to close")
+		}
+	}()
+
+	if err != nil {
+		return err
+	}
+
+	log.Log().Debug().
+		Interface("discovery", cmd.Discovery).
+		Interface("hold", cmd.Hold.Height()).
+		Msg("node started")
+
+	return cmd.RunCommand.RunNode(nctx)
+}
+
+// runPS returns the process of running node with the context; the process is
+// not run yet.
+func (cmd *RunCommand) runPS(pctx context.Context, log *logging.Logging) (context.Context, *ps.PS, error) {
+	webhookDesign, err := loadTokenWebhookDesign(cmd.TokenWebhook)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	nctx := util.ContextWithValues(pctx, map[util.ContextKey]interface{}{
-		launch.DesignFlagContextKey:    cmd.DesignFlag,
-		launch.DevFlagsContextKey:      cmd.DevFlags,
-		launch.DiscoveryFlagContextKey: cmd.Discovery,
-		launch.PrivatekeyContextKey:    string(cmd.PrivatekeyFlags.Flag.Body()),
-		launch.ACLFlagsContextKey:      cmd.ACLFlags,
+		launch.DesignFlagContextKey:           cmd.DesignFlag,
+		launch.DevFlagsContextKey:             cmd.DevFlags,
+		launch.DiscoveryFlagContextKey:        cmd.Discovery,
+		launch.PrivatekeyContextKey:           string(cmd.PrivatekeyFlags.Flag.Body()),
+		launch.ACLFlagsContextKey:             cmd.ACLFlags,
+		digest.ContextValueTokenWebhookDesign: webhookDesign,
+		digest.ContextValueTokenEventNotifier: digest.NewTokenEventNotifier(),
 	})
 
 	pps := cpipeline.DefaultRunPS()
 	registry := mustBuildModuleRegistry()
 
 	_ = pps.AddOK(cdigest.PNameDigester, digest.ProcessDigester, nil, cdigest.PNameDigesterDataBase).
-		AddOK(cdigest.PNameStartDigester, cdigest.ProcessStartDigester, nil, apic.PNameStartAPI)
+		AddOK(cdigest.PNameStartDigester, cdigest.ProcessStartDigester, nil, apic.PNameStartAPI).
+		AddOK(digest.PNameTokenStats, digest.ProcessStartTokenStats, digest.ProcessStopTokenStats,
+			cdigest.PNameStartDigester).
+		AddOK(digest.PNameTokenWebhook, digest.ProcessStartTokenWebhook, digest.ProcessStopTokenWebhook,
+			cdigest.PNameStartDigester)
 	_ = pps.POK(launch.PNameStorage).PostAddOK(ps.Name("check-hold"), cmd.RunCommand.PCheckHold)
 	pstates := pps.POK(launch.PNameStates)
 	entries := registry.Entries()
@@ -75,37 +121,44 @@ func (cmd *RunCommand) Run(pctx context.Context) error {
 	_ = pstates.
 		PreAddOK(ps.Name("when-new-block-saved-in-consensus-state-func"), cmd.RunCommand.PWhenNewBlockSavedInConsensusStateFunc).
 		PreAddOK(ps.Name("when-new-block-saved-in-syncing-state-func"), cmd.RunCommand.PWhenNewBlockSavedInSyncingStateFunc).
-		PreAddOK(ps.Name("when-new-block-confirmed-func"), cmd.RunCommand.PWhenNewBlockConfirmed)
+		PreAddOK(ps.Name("when-new-block-confirmed-func"), cmd.RunCommand.PWhenNewBlockConfirmed).
+		PreAddOK(ps.Name("when-new-block-confirmed-func-token-latest"), digest.PWhenNewBlockConfirmed)
 	_ = pps.POK(launch.PNameEncoder).
 		PostAddOK(launch.PNameAddHinters, steps.PAddHinters)
+	_ = pps.POK(launch.PNameDesign).
+		PostAddOK(steps.PNameLoadTokenParams, steps.PLoadTokenParams)
 	_ = pps.POK(apic.PNameAPI).
 		PostAddOK(ccmds.PNameDigestAPIHandlers, cmd.pDigestAPIHandlers)
 	_ = pps.POK(cdigest.PNameDigester).
-		PostAddOK(ccmds.PNameDigesterFollowUp, cdigest.PdigesterFollowUp)
+		PostAddOK(ccmds.PNameDigesterFollowUp, digest.PdigesterFollowUp)
 
 	_ = pps.SetLogging(log)
 
 	log.Log().Debug().Interface("process", pps.Verbose()).Msg("process ready")
 
-	nctx, err := pps.Run(nctx) //revive:disable-line:modifies-parameter
-	defer func() {
-		log.Log().Debug().Interface("process", pps.Verbose()).Msg("process will be closed")
+	return nctx, pps, nil
+}
 
-		if _, err = pps.Close(nctx); err != nil {
-			log.Log().Error().Err(err).Msg("failed to close")
-		}
-	}()
+func loadTokenWebhookDesign(f string) (*digest.TokenWebhookDesign, error) {
+	if len(f) < 1 {
+		return nil, nil
+	}
 
+	b, err := os.ReadFile(filepath.Clean(f))
 	if err != nil {
-		return err
+		return nil, errors.WithMessage(err, "read token webhook design")
 	}
 
-	log.Log().Debug().
-		Interface("discovery", cmd.Discovery).
-		Interface("hold", cmd.Hold.Height()).
-		Msg("node started")
+	var design digest.TokenWebhookDesign
+	if err := yaml.Unmarshal(b, &design); err != nil {
+		return nil, errors.WithMessage(err, "load token webhook design")
+	}
 
-	return cmd.RunCommand.RunNode(nctx)
+	if err := design.IsValid(nil); err != nil {
+		return nil, err
+	}
+
+	return &design, nil
 }
 
 func (cmd *RunCommand) pDigestAPIHandlers(ctx context.Context) (context.Context, error) {
@@ -157,6 +210,13 @@ func (cmd *RunCommand) pDigestAPIHandlers(ctx context.Context) (context.Context,
 		}
 	}
 
+	var notifier *digest.TokenEventNotifier
+	if err := util.LoadFromContext(ctx, digest.ContextValueTokenEventNotifier, &notifier); err != nil {
+		return ctx, err
+	}
+
+	modapi.SetTokenEventHandler(handlers, notifier)
+
 	dnt.SetEncoder(encs)
 
 	return ctx, nil
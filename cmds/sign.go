@@ -0,0 +1,301 @@
+package cmds
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	ccmds "github.com/imfact-labs/currency-model/app/cmds"
+	ccstate "github.com/imfact-labs/currency-model/state/currency"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/launch"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/encoder"
+	"github.com/pkg/errors"
+)
+
+type OperationInputFlags struct {
+	Input     string              `arg:"" name:"input" help:"operation or json array of operations; default is stdin" default:"-"` // revive:disable-line:line-length-limit
+	IsString  bool                `name:"input.is-string" help:"input is string, not file"`
+	NetworkID ccmds.NetworkIDFlag `name:"network-id" help:"network-id" required:"true" default:"${network_id}"`
+}
+
+// load loads the operations of input; isArray is true when input is a JSON
+// array.
+func (f OperationInputFlags) load(enc encoder.Encoder) (ops []base.Operation, isArray bool, _ error) {
+	if err := f.NetworkID.NetworkID().IsValid(nil); err != nil {
+		return nil, false, err
+	}
+
+	switch i, err := launch.LoadInputFlag(f.Input, !f.IsString); {
+	case err != nil:
+		return nil, false, err
+	case len(i) < 1:
+		return nil, false, errors.Errorf("empty input")
+	default:
+		ops, err := decodeOperations(enc, i)
+		if err != nil {
+			return nil, false, err
+		}
+
+		return ops, isJSONArray(i), nil
+	}
+}
+
+type SignCommand struct {
+	BaseCommand
+	Privatekey ccmds.PrivatekeyFlag `arg:"" name:"privatekey" help:"privatekey to sign operation" required:"true"`
+	OperationInputFlags
+}
+
+func (cmd *SignCommand) Run(pctx context.Context) error {
+	if _, err := cmd.prepare(pctx); err != nil {
+		return err
+	}
+
+	ops, isArray, err := cmd.load(cmd.Encoder)
+	if err != nil {
+		return err
+	}
+
+	for i := range ops {
+		op, err := cmd.sign(ops[i])
+		if err != nil {
+			return errors.WithMessagef(err, "operation %d", i)
+		}
+
+		ops[i] = op
+	}
+
+	if isArray {
+		ccmds.PrettyPrint(cmd.Out, ops)
+	} else {
+		ccmds.PrettyPrint(cmd.Out, ops[0])
+	}
+
+	return nil
+}
+
+// sign appends the sign of privatekey to op; the existing sign of same
+// privatekey is replaced.
+func (cmd *SignCommand) sign(op base.Operation) (base.Operation, error) {
+	e := util.StringError("sign operation")
+
+	ptr := reflect.New(reflect.TypeOf(op))
+	ptr.Elem().Set(reflect.ValueOf(op))
+
+	signer, ok := ptr.Interface().(base.Signer)
+	if !ok {
+		return nil, e.Wrap(errors.Errorf("expected Signer, not %T", op))
+	}
+
+	networkID := cmd.NetworkID.NetworkID()
+
+	if err := signer.Sign(cmd.Privatekey, networkID); err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	signed := ptr.Elem().Interface().(base.Operation) //nolint:forcetypeassert //...
+	if err := signed.IsValid(networkID); err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	return signed, nil
+}
+
+// VerifyCommand verifies the signs of operations by the keys of signer
+// account. Without keys, the keys of the signer of each operation are loaded
+// from the remote node.
+type VerifyCommand struct {
+	BaseCommand
+	OperationInputFlags
+	Threshold  uint          `name:"threshold" help:"threshold of signer keys (default: ${create_account_threshold})" default:"${create_account_threshold}"` // revive:disable-line:line-length-limit
+	Keys       ccmds.KeyFlag `name:"keys" help:"current keys of signer account (ex: \"<public key>,<weight>\") separator @"`
+	KeysRemote string        `name:"keys.remote" help:"remote node conn info to get the keys of signer account" placeholder:"ConnInfo"` // revive:disable-line:line-length-limit
+	keys       map[string]ctypes.AccountKeys
+}
+
+type OperationSignVerification struct {
+	Signer   base.Publickey `json:"signer"`
+	SignedAt time.Time      `json:"signed_at"`
+	Weight   uint           `json:"weight"`
+	Valid    bool           `json:"valid"`
+	Known    bool           `json:"known"`
+	Error    string         `json:"error,omitempty"`
+}
+
+type OperationVerification struct {
+	Fact         util.Hash                   `json:"fact"`
+	Signer       base.Address                `json:"signer"`
+	Signs        []OperationSignVerification `json:"signs"`
+	Weight       uint                        `json:"weight"`
+	Threshold    uint                        `json:"threshold"`
+	ThresholdMet bool                        `json:"threshold_met"`
+	Error        string                      `json:"error,omitempty"`
+}
+
+func (cmd *VerifyCommand) Run(pctx context.Context) error {
+	if _, err := cmd.prepare(pctx); err != nil {
+		return err
+	}
+
+	switch {
+	case len(cmd.Keys.Values) > 0 && len(cmd.KeysRemote) > 0:
+		return errors.Errorf("--keys and --keys.remote can not be used together")
+	case len(cmd.Keys.Values) < 1 && len(cmd.KeysRemote) < 1:
+		return errors.Errorf("expected --keys or --keys.remote")
+	}
+
+	ops, isArray, err := cmd.load(cmd.Encoder)
+	if err != nil {
+		return err
+	}
+
+	vs := make([]OperationVerification, len(ops))
+
+	var failed int
+
+	for i := range ops {
+		keys, err := cmd.signerKeys(ops[i])
+		if err != nil {
+			return err
+		}
+
+		vs[i] = cmd.verify(ops[i], keys)
+
+		if !vs[i].ThresholdMet {
+			failed++
+		}
+	}
+
+	if isArray {
+		ccmds.PrettyPrint(cmd.Out, vs)
+	} else {
+		ccmds.PrettyPrint(cmd.Out, vs[0])
+	}
+
+	if failed > 0 {
+		return errors.Errorf("%d of %d operations not passed threshold", failed, len(ops))
+	}
+
+	return nil
+}
+
+// signerKeys returns the keys of --keys, or the keys of the signer account of
+// op from the remote node.
+func (cmd *VerifyCommand) signerKeys(op base.Operation) (ctypes.AccountKeys, error) {
+	if len(cmd.Keys.Values) > 0 {
+		return cmd.flagKeys()
+	}
+
+	i, ok := op.Fact().(interface{ Signer() base.Address })
+	if !ok {
+		return nil, errors.Errorf("unknown signer of operation, %q; use --keys", op.Fact().Hash())
+	}
+
+	signer := i.Signer()
+
+	if keys, found := cmd.keys[signer.String()]; found {
+		return keys, nil
+	}
+
+	switch st, found, err := remoteState(
+		cmd.Encoders, cmd.Encoder, cmd.NetworkID.NetworkID(), cmd.KeysRemote, ccstate.AccountStateKey(signer)); {
+	case err != nil:
+		return nil, errors.WithMessage(err, "get signer account")
+	case !found:
+		return nil, errors.Errorf("signer account not found, %v", signer)
+	default:
+		ac, err := ccstate.LoadAccountStateValue(st)
+		if err != nil {
+			return nil, err
+		}
+
+		if ac.Keys() == nil {
+			return nil, errors.Errorf("signer account has no keys, %v", signer)
+		}
+
+		if cmd.keys == nil {
+			cmd.keys = map[string]ctypes.AccountKeys{}
+		}
+
+		cmd.keys[signer.String()] = ac.Keys()
+
+		return ac.Keys(), nil
+	}
+}
+
+func (cmd *VerifyCommand) flagKeys() (ctypes.AccountKeys, error) {
+	ks := make([]ctypes.AccountKey, len(cmd.Keys.Values))
+	for i := range cmd.Keys.Values {
+		ks[i] = cmd.Keys.Values[i]
+	}
+
+	keys, err := ctypes.NewBaseAccountKeys(ks, cmd.Threshold)
+	if err != nil {
+		return nil, errors.WithMessage(err, "invalid keys")
+	}
+
+	return keys, nil
+}
+
+func (cmd *VerifyCommand) verify(op base.Operation, keys ctypes.AccountKeys) OperationVerification {
+	networkID := cmd.NetworkID.NetworkID()
+
+	fact := op.Fact()
+	v := OperationVerification{
+		Fact:      fact.Hash(),
+		Threshold: keys.Threshold(),
+	}
+
+	if i, ok := fact.(interface{ Signer() base.Address }); ok {
+		v.Signer = i.Signer()
+	}
+
+	signs := op.Signs()
+	v.Signs = make([]OperationSignVerification, len(signs))
+
+	var valids []base.Sign
+
+	for i := range signs {
+		s := OperationSignVerification{
+			Signer:   signs[i].Signer(),
+			SignedAt: signs[i].SignedAt(),
+		}
+
+		if ky, found := keys.Key(signs[i].Signer()); found {
+			s.Known, s.Weight = true, ky.Weight()
+		}
+
+		switch err := signs[i].Verify(networkID, fact.Hash().Bytes()); {
+		case err != nil:
+			s.Error = err.Error()
+		case !s.Known:
+			s.Valid = true
+			s.Error = "unknown key"
+		default:
+			s.Valid = true
+			v.Weight += s.Weight
+
+			valids = append(valids, signs[i])
+		}
+
+		v.Signs[i] = s
+	}
+
+	switch {
+	case len(valids) < len(signs):
+		v.Error = "invalid or unknown signs found"
+	case len(signs) < 1:
+		v.Error = "empty signs"
+	default:
+		if err := ctypes.CheckThreshold(valids, keys); err != nil {
+			v.Error = err.Error()
+		} else {
+			v.ThresholdMet = true
+		}
+	}
+
+	return v
+}
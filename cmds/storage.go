@@ -8,4 +8,5 @@ type Storage struct { //nolint:govet //...
 	ValidateBlocks ValidateBlocksCommand          `cmd:"" help:"validate blocks in storage"`
 	Status         launchcmd.StorageStatusCommand `cmd:"" help:"storage status"`
 	Database       launchcmd.DatabaseCommand      `cmd:"" help:""`
+	Token          StorageTokenCommand            `cmd:"" help:"token storage"`
 }
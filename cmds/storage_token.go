@@ -0,0 +1,58 @@
+package cmds
+
+import (
+	"context"
+
+	mongodbst "github.com/imfact-labs/currency-model/digest/mongodb"
+	"github.com/imfact-labs/token-model/digest"
+	"github.com/pkg/errors"
+)
+
+type StorageTokenCommand struct { //nolint:govet //...
+	RebuildLatest StorageTokenRebuildLatestCommand `cmd:"" name:"rebuild-latest" help:"rebuild latest token states and stats of digest from history"` // revive:disable-line:line-length-limit
+	Export        StorageTokenExportCommand        `cmd:"" name:"export" help:"export token designs, balances and allowances at height"`              // revive:disable-line:line-length-limit
+	Import        StorageTokenImportCommand        `cmd:"" name:"import" help:"print genesis token facts from token dump"`                            // revive:disable-line:line-length-limit
+	Diff          StorageTokenDiffCommand          `cmd:"" name:"diff" help:"print the changes of token states in height range"`                      // revive:disable-line:line-length-limit
+}
+
+type StorageTokenRebuildLatestCommand struct { //nolint:govet //...
+	BaseCommand
+	URI string `arg:"" name:"uri" help:"digest mongodb uri (ex: \"mongodb://127.0.0.1:27017/mc\")" required:"true"`
+}
+
+func (cmd *StorageTokenRebuildLatestCommand) Run(pctx context.Context) error {
+	if _, err := cmd.prepare(pctx); err != nil {
+		return err
+	}
+
+	st, err := mongodbst.NewDatabaseFromURI(cmd.URI, cmd.Encoders)
+	if err != nil {
+		return errors.WithMessage(err, "open digest database")
+	}
+
+	defer func() {
+		_ = st.Close()
+	}()
+
+	for _, col := range []string{
+		digest.DefaultColNameTokenLatest,
+		digest.DefaultColNameTokenBalanceLatest,
+		digest.DefaultColNameTokenStats,
+	} {
+		if err := st.CreateIndex(col, digest.DefaultIndexes[col]); err != nil {
+			return err
+		}
+	}
+
+	if err := digest.RebuildTokenLatest(pctx, st); err != nil {
+		return err
+	}
+
+	if err := digest.RebuildTokenStats(pctx, st); err != nil {
+		return err
+	}
+
+	cmd.Log.Info().Msg("latest token states rebuilt")
+
+	return nil
+}
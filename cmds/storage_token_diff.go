@@ -0,0 +1,316 @@
+package cmds
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"sort"
+
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/isaac"
+	"github.com/imfact-labs/mitum2/launch"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/logging"
+	"github.com/imfact-labs/mitum2/util/ps"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+var PNameStorageTokenDiff = ps.Name("storage-token-diff")
+
+// TokenStateChange is the change of token design or token balance state in
+// block; Before is null when the state is created in the block. Facts are the
+// hashes of operation facts, which changed the state.
+type TokenStateChange struct {
+	TokenStateDiff
+	Height   base.Height `json:"height"`
+	Contract string      `json:"contract"`
+	Address  string      `json:"address,omitempty"`
+	Facts    []util.Hash `json:"facts"`
+}
+
+type StorageTokenDiffCommand struct { //nolint:govet //...
+	launch.DesignFlag
+	launch.PrivatekeyFlags
+	HeightRange     launch.RangeFlag `name:"range" help:"<from>-<to>" default:""`
+	Contract        string           `name:"contract" help:"only the states of contract" placeholder:"address"`
+	log             *zerolog.Logger
+	launch.DevFlags `embed:"" prefix:"dev."`
+	fromHeight      base.Height
+	toHeight        base.Height
+}
+
+func (cmd *StorageTokenDiffCommand) Run(pctx context.Context) error {
+	var log *logging.Logging
+	if err := util.LoadFromContextOK(pctx, launch.LoggingContextKey, &log); err != nil {
+		return err
+	}
+
+	cmd.fromHeight, cmd.toHeight = base.NilHeight, base.NilHeight
+
+	if h := cmd.HeightRange.From(); h != nil {
+		cmd.fromHeight = base.Height(*h)
+
+		if err := cmd.fromHeight.IsValid(nil); err != nil {
+			return errors.WithMessagef(err, "invalid from height; from=%d", *h)
+		}
+	}
+
+	if h := cmd.HeightRange.To(); h != nil {
+		cmd.toHeight = base.Height(*h)
+
+		if err := cmd.toHeight.IsValid(nil); err != nil {
+			return errors.WithMessagef(err, "invalid to height; to=%d", *h)
+		}
+
+		if cmd.fromHeight > cmd.toHeight {
+			return errors.Errorf("from height is higher than to; from=%d to=%d", cmd.fromHeight, cmd.toHeight)
+		}
+	}
+
+	log.Log().Debug().
+		Interface("design", cmd.DesignFlag).
+		Interface("privatekey", cmd.PrivatekeyFlags).
+		Interface("dev", cmd.DevFlags).
+		Interface("from_height", cmd.fromHeight).
+		Interface("to_height", cmd.toHeight).
+		Str("contract", cmd.Contract).
+		Msg("flags")
+
+	cmd.log = log.Log()
+
+	nctx := util.ContextWithValues(pctx, map[util.ContextKey]interface{}{
+		launch.DesignFlagContextKey: cmd.DesignFlag,
+		launch.DevFlagsContextKey:   cmd.DevFlags,
+		launch.PrivatekeyContextKey: string(cmd.PrivatekeyFlags.Flag.Body()),
+	})
+
+	pps := newStorageTokenPS("cmd-storage-token-diff", log, PNameStorageTokenDiff, cmd.pDiff)
+
+	cmd.log.Debug().Interface("process", pps.Verbose()).Msg("process ready")
+
+	nctx, err := pps.Run(nctx)
+	defer func() {
+		cmd.log.Debug().Interface("process", pps.Verbose()).Msg("process will be closed")
+
+		if _, err = pps.Close(nctx); err != nil {
+			cmd.log.Error().Err(err).Msg("failed to close")
+		}
+	}()
+
+	return err
+}
+
+func (cmd *StorageTokenDiffCommand) pDiff(pctx context.Context) (context.Context, error) {
+	e := util.StringError("diff token states")
+
+	var design launch.NodeDesign
+	var newReaders func(context.Context, string, *isaac.BlockItemReadersArgs) (*isaac.BlockItemReaders, error)
+
+	if err := util.LoadFromContextOK(pctx,
+		launch.DesignContextKey, &design,
+		launch.NewBlockItemReadersFuncContextKey, &newReaders,
+	); err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	readers, err := newReaders(pctx, launch.LocalFSDataDirectory(design.Storage.Base), nil)
+	if err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	defer readers.Close()
+
+	switch fromHeight, _, last, err := checkLastHeight(pctx, readers.Root(), cmd.fromHeight, cmd.toHeight); {
+	case err != nil:
+		return pctx, e.Wrap(err)
+	default:
+		cmd.fromHeight = fromHeight
+		cmd.toHeight = last
+	}
+
+	sts := map[string]base.State{}
+
+	if cmd.fromHeight > base.GenesisHeight {
+		keys, err := cmd.keys(readers)
+		if err != nil {
+			return pctx, e.Wrap(err)
+		}
+
+		if err := lastTokenStates(readers, cmd.fromHeight-1, keys, sts); err != nil {
+			return pctx, e.Wrap(err)
+		}
+	}
+
+	w := bufio.NewWriter(os.Stdout)
+
+	var count int
+
+	for h := cmd.fromHeight; h <= cmd.toHeight; h++ {
+		diffs, err := cmd.diffs(readers, h, sts)
+		if err != nil {
+			return pctx, e.Wrap(err)
+		}
+
+		for i := range diffs {
+			b, err := util.MarshalJSON(diffs[i])
+			if err != nil {
+				return pctx, e.Wrap(err)
+			}
+
+			if _, err := w.Write(append(b, '\n')); err != nil {
+				return pctx, e.Wrap(err)
+			}
+		}
+
+		count += len(diffs)
+	}
+
+	if err := w.Flush(); err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	cmd.log.Debug().
+		Interface("from_height", cmd.fromHeight).
+		Interface("to_height", cmd.toHeight).
+		Int("diffs", count).
+		Msg("token states compared")
+
+	return pctx, nil
+}
+
+// diffs returns the changed token states of height ordered by key; sts is
+// updated with the states of height.
+func (cmd *StorageTokenDiffCommand) diffs(
+	readers *isaac.BlockItemReaders, height base.Height, sts map[string]base.State,
+) ([]TokenStateChange, error) {
+	var diffs []TokenStateChange
+
+	if _, _, _, err := isaac.BlockItemReadersDecodeItems[base.State](
+		readers.Item,
+		height,
+		base.BlockItemStates,
+		func(_, _ uint64, st base.State) error {
+			contract, address, ok, err := cmd.parseKey(st.Key())
+			switch {
+			case err != nil:
+				return err
+			case !ok:
+				return nil
+			}
+
+			d := TokenStateChange{
+				TokenStateDiff: TokenStateDiff{
+					Key:   st.Key(),
+					After: st.Value(),
+				},
+				Height:   height,
+				Contract: contract,
+				Address:  address,
+				Facts:    st.Operations(),
+			}
+
+			if prev, found := sts[st.Key()]; found {
+				d.Before = prev.Value()
+			}
+
+			sts[st.Key()] = st
+			diffs = append(diffs, d)
+
+			return nil
+		},
+		nil,
+	); err != nil {
+		return nil, errors.WithMessagef(err, "states; height=%d", height)
+	}
+
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].Key < diffs[j].Key
+	})
+
+	return diffs, nil
+}
+
+// keys returns the keys of the token states changed in the range.
+func (cmd *StorageTokenDiffCommand) keys(readers *isaac.BlockItemReaders) (map[string]struct{}, error) {
+	keys := map[string]struct{}{}
+
+	for h := cmd.fromHeight; h <= cmd.toHeight; h++ {
+		if _, _, _, err := isaac.BlockItemReadersDecodeItems[base.State](
+			readers.Item,
+			h,
+			base.BlockItemStates,
+			func(_, _ uint64, st base.State) error {
+				switch _, _, ok, err := cmd.parseKey(st.Key()); {
+				case err != nil:
+					return err
+				case ok:
+					keys[st.Key()] = struct{}{}
+				}
+
+				return nil
+			},
+			nil,
+		); err != nil {
+			return nil, errors.WithMessagef(err, "states; height=%d", h)
+		}
+	}
+
+	return keys, nil
+}
+
+// parseKey parses the token state key; ok is false when the key is not of
+// token state or not of --contract.
+func (cmd *StorageTokenDiffCommand) parseKey(key string) (contract, address string, ok bool, _ error) {
+	contract, address, err := parseTokenStateKey(key)
+
+	switch {
+	case err != nil:
+		return "", "", false, err
+	case len(contract) < 1,
+		len(cmd.Contract) > 0 && contract != cmd.Contract:
+		return "", "", false, nil
+	default:
+		return contract, address, true, nil
+	}
+}
+
+// lastTokenStates finds the last states of keys until height into sts; the
+// blocks are read backwards from height, and it stops when every key is found,
+// so the keys created in the range make it read down to genesis.
+func lastTokenStates(
+	readers *isaac.BlockItemReaders,
+	height base.Height,
+	keys map[string]struct{},
+	sts map[string]base.State,
+) error {
+	missing := make(map[string]struct{}, len(keys))
+
+	for k := range keys {
+		if _, found := sts[k]; !found {
+			missing[k] = struct{}{}
+		}
+	}
+
+	for h := height; len(missing) > 0 && h >= base.GenesisHeight; h-- {
+		if _, _, _, err := isaac.BlockItemReadersDecodeItems[base.State](
+			readers.Item,
+			h,
+			base.BlockItemStates,
+			func(_, _ uint64, st base.State) error {
+				if _, found := missing[st.Key()]; found {
+					sts[st.Key()] = st
+
+					delete(missing, st.Key())
+				}
+
+				return nil
+			},
+			nil,
+		); err != nil {
+			return errors.WithMessagef(err, "states; height=%d", h)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,460 @@
+package cmds
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"sort"
+
+	csteps "github.com/imfact-labs/currency-model/app/runtime/steps"
+	statee "github.com/imfact-labs/currency-model/state/extension"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/isaac"
+	"github.com/imfact-labs/mitum2/launch"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/encoder"
+	"github.com/imfact-labs/mitum2/util/logging"
+	"github.com/imfact-labs/mitum2/util/ps"
+	"github.com/imfact-labs/mitum2/util/valuehash"
+	"github.com/imfact-labs/token-model/runtime/steps"
+	"github.com/imfact-labs/token-model/state"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+var PNameStorageTokenExport = ps.Name("storage-token-export")
+
+const (
+	TokenDumpFormatJSON   = "json"
+	TokenDumpFormatNDJSON = "ndjson"
+
+	TokenDumpRecordDesign  = "design"
+	TokenDumpRecordBalance = "balance"
+)
+
+// TokenDumpHeader describes where the token dump comes from; Hash is the
+// sha256 of the records, each record is encoded in compact json and followed
+// by newline. The hash is same in json and ndjson format.
+type TokenDumpHeader struct {
+	NetworkID string      `json:"network_id"`
+	Height    base.Height `json:"height"`
+	Manifest  string      `json:"manifest"`
+	Hash      string      `json:"hash"`
+}
+
+// TokenDumpRecord is the design or the balance of contract; the allowances are
+// kept in the approve list of design policy.
+type TokenDumpRecord struct {
+	Type     string          `json:"type"`
+	Contract string          `json:"contract"`
+	Owner    string          `json:"owner,omitempty"`
+	Design   json.RawMessage `json:"design,omitempty"`
+	Address  string          `json:"address,omitempty"`
+	Amount   string          `json:"amount,omitempty"`
+}
+
+type TokenDump struct {
+	TokenDumpHeader
+	Records []TokenDumpRecord `json:"records"`
+}
+
+func (d TokenDump) GenerateHash() (util.Hash, error) {
+	bs := make([][]byte, len(d.Records))
+
+	for i := range d.Records {
+		b, err := util.MarshalJSON(d.Records[i])
+		if err != nil {
+			return nil, err
+		}
+
+		bs[i] = append(b, '\n')
+	}
+
+	return valuehash.NewSHA256(util.ConcatBytesSlice(bs...)), nil
+}
+
+func (d TokenDump) Write(w io.Writer, format string) error {
+	bw := bufio.NewWriter(w)
+
+	switch format {
+	case TokenDumpFormatJSON:
+		b, err := util.MarshalJSON(d)
+		if err != nil {
+			return err
+		}
+
+		if _, err := bw.Write(append(b, '\n')); err != nil {
+			return err
+		}
+	case TokenDumpFormatNDJSON:
+		b, err := util.MarshalJSON(d.TokenDumpHeader)
+		if err != nil {
+			return err
+		}
+
+		if _, err := bw.Write(append(b, '\n')); err != nil {
+			return err
+		}
+
+		for i := range d.Records {
+			b, err := util.MarshalJSON(d.Records[i])
+			if err != nil {
+				return err
+			}
+
+			if _, err := bw.Write(append(b, '\n')); err != nil {
+				return err
+			}
+		}
+	default:
+		return errors.Errorf("unknown token dump format, %q", format)
+	}
+
+	return bw.Flush()
+}
+
+// ReadTokenDump reads json or ndjson token dump and checks the hash.
+func ReadTokenDump(r io.Reader) (TokenDump, error) {
+	var d TokenDump
+
+	dec := json.NewDecoder(r)
+	if err := dec.Decode(&d); err != nil {
+		return d, errors.WithMessage(err, "read token dump header")
+	}
+
+	for dec.More() {
+		var rc TokenDumpRecord
+		if err := dec.Decode(&rc); err != nil {
+			return d, errors.WithMessagef(err, "read token dump record, %d", len(d.Records))
+		}
+
+		d.Records = append(d.Records, rc)
+	}
+
+	switch h, err := d.GenerateHash(); {
+	case err != nil:
+		return d, err
+	case h.String() != d.Hash:
+		return d, errors.Errorf("token dump hash does not match; expected=%s hash=%s", d.Hash, h)
+	}
+
+	return d, nil
+}
+
+type StorageTokenExportCommand struct { //nolint:govet //...
+	launch.DesignFlag
+	launch.PrivatekeyFlags
+	Height          launch.HeightFlag `name:"height" help:"export states at height; default is last height" placeholder:"height"` // revive:disable-line:line-length-limit
+	Format          string            `name:"format" help:"json or ndjson" enum:"json,ndjson" default:"json"`
+	Output          string            `name:"output" help:"output file; default is stdout" placeholder:"file"`
+	log             *zerolog.Logger
+	launch.DevFlags `embed:"" prefix:"dev."`
+}
+
+func (cmd *StorageTokenExportCommand) Run(pctx context.Context) error {
+	var log *logging.Logging
+	if err := util.LoadFromContextOK(pctx, launch.LoggingContextKey, &log); err != nil {
+		return err
+	}
+
+	if cmd.Height.IsSet() {
+		if err := cmd.Height.Height().IsValid(nil); err != nil {
+			return errors.WithMessagef(err, "invalid height; height=%d", cmd.Height.Height())
+		}
+	}
+
+	log.Log().Debug().
+		Interface("design", cmd.DesignFlag).
+		Interface("privatekey", cmd.PrivatekeyFlags).
+		Interface("dev", cmd.DevFlags).
+		Interface("height", cmd.Height).
+		Str("format", cmd.Format).
+		Str("output", cmd.Output).
+		Msg("flags")
+
+	cmd.log = log.Log()
+
+	nctx := util.ContextWithValues(pctx, map[util.ContextKey]interface{}{
+		launch.DesignFlagContextKey: cmd.DesignFlag,
+		launch.DevFlagsContextKey:   cmd.DevFlags,
+		launch.PrivatekeyContextKey: string(cmd.PrivatekeyFlags.Flag.Body()),
+	})
+
+	pps := newStorageTokenPS("cmd-storage-token-export", log, PNameStorageTokenExport, cmd.pExport)
+
+	cmd.log.Debug().Interface("process", pps.Verbose()).Msg("process ready")
+
+	nctx, err := pps.Run(nctx)
+	defer func() {
+		cmd.log.Debug().Interface("process", pps.Verbose()).Msg("process will be closed")
+
+		if _, err = pps.Close(nctx); err != nil {
+			cmd.log.Error().Err(err).Msg("failed to close")
+		}
+	}()
+
+	return err
+}
+
+// newStorageTokenPS returns the process to load the local storage and block
+// item readers, like ValidateBlocksCommand; f is added after the storage is
+// loaded.
+func newStorageTokenPS(name string, log *logging.Logging, pname ps.Name, f ps.Func) *ps.PS {
+	pps := ps.NewPS(name)
+	_ = pps.SetLogging(log)
+
+	_ = pps.
+		AddOK(launch.PNameEncoder, csteps.PEncoder, nil).
+		AddOK(launch.PNameDesign, launch.PLoadDesign, nil, launch.PNameEncoder).
+		AddOK(launch.PNameLocal, launch.PLocal, nil, launch.PNameDesign).
+		AddOK(launch.PNameBlockItemReaders, launch.PBlockItemReaders, nil, launch.PNameDesign).
+		AddOK(launch.PNameStorage, launch.PStorage, launch.PCloseStorage, launch.PNameLocal)
+
+	_ = pps.POK(launch.PNameEncoder).
+		PostAddOK(launch.PNameAddHinters, steps.PAddHinters)
+
+	_ = pps.POK(launch.PNameDesign).
+		PostAddOK(launch.PNameCheckDesign, launch.PCheckDesign)
+
+	_ = pps.POK(launch.PNameBlockItemReaders).
+		PreAddOK(launch.PNameBlockItemReadersDecompressFunc, launch.PBlockItemReadersDecompressFunc).
+		PostAddOK(launch.PNameRemotesBlockItemReaderFunc, launch.PRemotesBlockItemReaderFunc)
+
+	_ = pps.POK(launch.PNameStorage).
+		PreAddOK(launch.PNameCheckLocalFS, launch.PCheckLocalFS).
+		PreAddOK(launch.PNameLoadDatabase, launch.PLoadDatabase).
+		PostAddOK(launch.PNameCheckLeveldbStorage, launch.PCheckLeveldbStorage).
+		PostAddOK(launch.PNameLoadFromDatabase, launch.PLoadFromDatabase).
+		PostAddOK(launch.PNameCheckBlocksOfStorage, launch.PCheckBlocksOfStorage).
+		PostAddOK(launch.PNamePatchBlockItemReaders, launch.PPatchBlockItemReaders).
+		PostAddOK(pname, f)
+
+	return pps
+}
+
+func (cmd *StorageTokenExportCommand) pExport(pctx context.Context) (context.Context, error) {
+	e := util.StringError("export token states")
+
+	var encs *encoder.Encoders
+	var design launch.NodeDesign
+	var isaacparams *isaac.Params
+	var newReaders func(context.Context, string, *isaac.BlockItemReadersArgs) (*isaac.BlockItemReaders, error)
+
+	if err := util.LoadFromContextOK(pctx,
+		launch.EncodersContextKey, &encs,
+		launch.DesignContextKey, &design,
+		launch.ISAACParamsContextKey, &isaacparams,
+		launch.NewBlockItemReadersFuncContextKey, &newReaders,
+	); err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	readers, err := newReaders(pctx, launch.LocalFSDataDirectory(design.Storage.Base), nil)
+	if err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	defer readers.Close()
+
+	toHeight := base.NilHeight
+	if cmd.Height.IsSet() {
+		toHeight = cmd.Height.Height()
+	}
+
+	_, _, last, err := checkLastHeight(pctx, readers.Root(), base.NilHeight, toHeight)
+	if err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	var manifest util.Hash
+
+	switch m, found, err := isaac.BlockItemReadersDecode[base.BlockMap](readers.Item, last, base.BlockItemMap, nil); {
+	case err != nil:
+		return pctx, e.Wrap(err)
+	case !found:
+		return pctx, e.Errorf("blockmap not found; height=%d", last)
+	default:
+		manifest = m.Manifest().Hash()
+	}
+
+	sts, err := collectTokenStates(readers, base.GenesisHeight, last, nil)
+	if err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	records, err := tokenDumpRecords(encs.JSON(), sts)
+	if err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	dump := TokenDump{
+		TokenDumpHeader: TokenDumpHeader{
+			NetworkID: string(isaacparams.NetworkID()),
+			Height:    last,
+			Manifest:  manifest.String(),
+		},
+		Records: records,
+	}
+
+	switch h, err := dump.GenerateHash(); {
+	case err != nil:
+		return pctx, e.Wrap(err)
+	default:
+		dump.Hash = h.String()
+	}
+
+	var w io.Writer = os.Stdout
+
+	if len(cmd.Output) > 0 {
+		f, err := os.Create(cmd.Output)
+		if err != nil {
+			return pctx, e.Wrap(err)
+		}
+
+		defer func() {
+			_ = f.Close()
+		}()
+
+		w = f
+	}
+
+	if err := dump.Write(w, cmd.Format); err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	cmd.log.Debug().
+		Interface("height", last).
+		Int("records", len(records)).
+		Str("hash", dump.Hash).
+		Msg("token states exported")
+
+	return pctx, nil
+}
+
+// collectTokenStates replays the states of blocks from fromHeight to toHeight
+// and returns the last token design, token balance and contract account
+// states by key. When sts is not nil, the states are merged into it.
+func collectTokenStates(
+	readers *isaac.BlockItemReaders,
+	fromHeight, toHeight base.Height,
+	sts map[string]base.State,
+) (map[string]base.State, error) {
+	if sts == nil {
+		sts = map[string]base.State{}
+	}
+
+	for h := fromHeight; h <= toHeight; h++ {
+		if _, _, _, err := isaac.BlockItemReadersDecodeItems[base.State](
+			readers.Item,
+			h,
+			base.BlockItemStates,
+			func(_, _ uint64, st base.State) error {
+				switch k := st.Key(); {
+				case state.IsStateDesignKey(k),
+					state.IsStateTokenBalanceKey(k),
+					statee.IsStateContractAccountKey(k):
+					sts[k] = st
+				}
+
+				return nil
+			},
+			nil,
+		); err != nil {
+			return nil, errors.WithMessagef(err, "states; height=%d", h)
+		}
+	}
+
+	return sts, nil
+}
+
+// tokenDumpRecords returns the records ordered by contract; the design record
+// of contract is followed by the balance records ordered by address.
+func tokenDumpRecords(enc encoder.Encoder, sts map[string]base.State) ([]TokenDumpRecord, error) {
+	var contracts []string
+	balances := map[string][]TokenDumpRecord{}
+
+	for k := range sts {
+		contract, address, err := parseTokenStateKey(k)
+		switch {
+		case err != nil:
+			return nil, err
+		case len(contract) < 1:
+			continue
+		case len(address) < 1:
+			contracts = append(contracts, contract)
+		default:
+			amount, err := state.StateTokenBalanceValue(sts[k])
+			if err != nil {
+				return nil, err
+			}
+
+			balances[contract] = append(balances[contract], TokenDumpRecord{
+				Type:     TokenDumpRecordBalance,
+				Contract: contract,
+				Address:  address,
+				Amount:   amount.String(),
+			})
+		}
+	}
+
+	sort.Strings(contracts)
+
+	records := []TokenDumpRecord{}
+
+	for i := range contracts {
+		contract := contracts[i]
+
+		design, err := state.StateDesignValue(sts[state.StateKeyDesign(contract)])
+		if err != nil {
+			return nil, err
+		}
+
+		b, err := util.MarshalJSON(*design)
+		if err != nil {
+			return nil, err
+		}
+
+		rc := TokenDumpRecord{
+			Type:     TokenDumpRecordDesign,
+			Contract: contract,
+			Design:   b,
+		}
+
+		switch ca, err := base.DecodeAddress(contract, enc); {
+		case err != nil:
+			return nil, err
+		default:
+			if st, found := sts[statee.StateKeyContractAccount(ca)]; found {
+				status, err := statee.StateContractAccountValue(st)
+				if err != nil {
+					return nil, err
+				}
+
+				rc.Owner = status.Owner().String()
+			}
+		}
+
+		records = append(records, rc)
+
+		bs := balances[contract]
+		sort.Slice(bs, func(i, j int) bool {
+			return bs[i].Address < bs[j].Address
+		})
+
+		records = append(records, bs...)
+	}
+
+	return records, nil
+}
+
+// parseTokenStateKey parses the token design and token balance key; the
+// contract is empty for the other keys.
+func parseTokenStateKey(k string) (contract string, address string, _ error) {
+	if !state.IsStateDesignKey(k) && !state.IsStateTokenBalanceKey(k) {
+		return "", "", nil
+	}
+
+	return state.ParseTokenStateKey(k)
+}
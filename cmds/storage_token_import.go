@@ -0,0 +1,203 @@
+package cmds
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+
+	ccmds "github.com/imfact-labs/currency-model/app/cmds"
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/encoder"
+	"github.com/imfact-labs/token-model/operation/token"
+	"github.com/imfact-labs/token-model/types"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// StorageTokenImportCommand converts the token dump of "storage token export"
+// to the RegisterGenesisTokenFacts; the printed facts can be added to the facts
+// of genesis design to launch new network with the same token states.
+type StorageTokenImportCommand struct { //nolint:govet //...
+	BaseCommand
+	Dump           string              `arg:"" name:"dump" help:"token dump file; \"-\" for stdin"`
+	GenesisNodeKey ccmds.PublickeyFlag `name:"genesis-node-key" help:"publickey of genesis node" required:"true"`
+	Output         string              `name:"output" help:"output file; default is stdout" placeholder:"file"`
+}
+
+func (cmd *StorageTokenImportCommand) Run(pctx context.Context) error {
+	if _, err := cmd.prepare(pctx); err != nil {
+		return err
+	}
+
+	var r io.Reader = os.Stdin
+
+	if cmd.Dump != "-" {
+		f, err := os.Open(cmd.Dump)
+		if err != nil {
+			return errors.WithMessage(err, "open token dump")
+		}
+
+		defer func() {
+			_ = f.Close()
+		}()
+
+		r = f
+	}
+
+	dump, err := ReadTokenDump(r)
+	if err != nil {
+		return err
+	}
+
+	facts, err := cmd.facts(dump)
+	if err != nil {
+		return err
+	}
+
+	l := make([]map[string]interface{}, len(facts))
+
+	for i := range facts {
+		m, err := genesisFactMap(facts[i])
+		if err != nil {
+			return err
+		}
+
+		l[i] = m
+	}
+
+	b, err := yaml.Marshal(map[string]interface{}{"facts": l})
+	if err != nil {
+		return err
+	}
+
+	if len(cmd.Output) > 0 {
+		return os.WriteFile(cmd.Output, b, 0o600)
+	}
+
+	_, err = cmd.Out.Write(b)
+
+	return err
+}
+
+func (cmd *StorageTokenImportCommand) facts(dump TokenDump) ([]token.RegisterGenesisTokenFact, error) {
+	var facts []token.RegisterGenesisTokenFact
+
+	for i := 0; i < len(dump.Records); {
+		rc := dump.Records[i]
+		if rc.Type != TokenDumpRecordDesign {
+			return nil, errors.Errorf("expected design record, not %q of %v", rc.Type, rc.Contract)
+		}
+
+		var balances []token.GenesisBalance
+
+		i++
+
+		for ; i < len(dump.Records) && dump.Records[i].Type == TokenDumpRecordBalance; i++ {
+			brc := dump.Records[i]
+			if brc.Contract != rc.Contract {
+				return nil, errors.Errorf("balance record of %v found in %v", brc.Contract, rc.Contract)
+			}
+
+			gb, err := cmd.balance(brc)
+			if err != nil {
+				return nil, err
+			}
+
+			balances = append(balances, gb)
+		}
+
+		fact, err := cmd.fact(rc, balances)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "contract, %v", rc.Contract)
+		}
+
+		facts = append(facts, fact)
+	}
+
+	return facts, nil
+}
+
+func (cmd *StorageTokenImportCommand) balance(rc TokenDumpRecord) (token.GenesisBalance, error) {
+	var gb token.GenesisBalance
+
+	address, err := base.DecodeAddress(rc.Address, cmd.Encoder)
+	if err != nil {
+		return gb, err
+	}
+
+	amount, err := common.NewBigFromString(rc.Amount)
+	if err != nil {
+		return gb, err
+	}
+
+	return token.NewGenesisBalance(address, amount), nil
+}
+
+func (cmd *StorageTokenImportCommand) fact(
+	rc TokenDumpRecord, balances []token.GenesisBalance,
+) (token.RegisterGenesisTokenFact, error) {
+	var fact token.RegisterGenesisTokenFact
+
+	contract, err := base.DecodeAddress(rc.Contract, cmd.Encoder)
+	if err != nil {
+		return fact, err
+	}
+
+	if len(rc.Owner) < 1 {
+		return fact, errors.Errorf("empty owner")
+	}
+
+	owner, err := base.DecodeAddress(rc.Owner, cmd.Encoder)
+	if err != nil {
+		return fact, err
+	}
+
+	var design types.Design
+	if err := encoder.Decode(cmd.Encoder, rc.Design, &design); err != nil {
+		return fact, err
+	}
+
+	fact = token.NewRegisterGenesisTokenFact(
+		[]byte("genesis"),
+		cmd.GenesisNodeKey.Publickey,
+		owner,
+		contract,
+		design.Symbol(),
+		design.Name(),
+		design.Decimal(),
+		balances,
+		design.Policy().Canonical().ApproveList(),
+	)
+
+	if err := fact.IsValid(nil); err != nil {
+		return fact, err
+	}
+
+	if ts := design.Policy().TotalSupply(); !ts.Equal(fact.TotalSupply()) {
+		return fact, errors.Errorf("sum of balances, %v not matched with total supply, %v", fact.TotalSupply(), ts)
+	}
+
+	return fact, nil
+}
+
+// genesisFactMap returns the fact without the token and hash; the genesis block
+// generator sets the token of RegisterGenesisTokenFact.
+func genesisFactMap(fact token.RegisterGenesisTokenFact) (map[string]interface{}, error) {
+	b, err := util.MarshalJSON(fact)
+	if err != nil {
+		return nil, err
+	}
+
+	var m map[string]interface{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+
+	delete(m, "token")
+	delete(m, "hash")
+
+	return m, nil
+}
@@ -1,10 +1,15 @@
 package cmds
 
 type TokenCommand struct {
-	RegisterToken RegisterModelCommand `cmd:"" name:"register-model" help:"register token to contract account"`
-	Mint          MintCommand          `cmd:"" name:"mint" help:"mint token to receiver"`
-	Burn          BurnCommand          `cmd:"" name:"burn" help:"burn token of target"`
-	Approve       ApproveCommand       `cmd:"" name:"approve" help:"approve token to approved account"`
-	Transfer      TransferCommand      `cmd:"" name:"transfer" help:"transfer token to receiver"`
-	TransferFrom  TransferFromCommand  `cmd:"" name:"transfer-from" help:"transfer token to receiver from target"`
+	RegisterToken  RegisterModelCommand  `cmd:"" name:"register-model" help:"register token to contract account"`
+	Mint           MintCommand           `cmd:"" name:"mint" help:"mint token to receiver"`
+	Burn           BurnCommand           `cmd:"" name:"burn" help:"burn token of target"`
+	Approve        ApproveCommand        `cmd:"" name:"approve" help:"approve token to approved account"`
+	Transfer       TransferCommand       `cmd:"" name:"transfer" help:"transfer token to receiver"`
+	TransferFrom   TransferFromCommand   `cmd:"" name:"transfer-from" help:"transfer token to receiver from target"`
+	ReleaseSymbol  ReleaseSymbolCommand  `cmd:"" name:"release-symbol" help:"release token symbol held by contract account"`
+	BackfillSymbol BackfillSymbolCommand `cmd:"" name:"backfill-symbol" help:"add symbol registry entry of token registered before symbol registry"`
+	Sign           SignCommand           `cmd:"" name:"sign" help:"add sign to token operation"`
+	Verify         VerifyCommand         `cmd:"" name:"verify" help:"verify signs and threshold of token operation"`
+	EstimateFee    EstimateFeeCommand    `cmd:"" name:"estimate-fee" help:"estimate fee of token operation"`
 }
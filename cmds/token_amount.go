@@ -0,0 +1,228 @@
+package cmds
+
+import (
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/token-model/state"
+	"github.com/imfact-labs/token-model/types"
+	"github.com/pkg/errors"
+)
+
+// TokenAmountFlags converts the amounts in token units, like "12.5" with
+// --human or "12.5MCT" with symbol suffix, to the raw integer units by the
+// decimal of token design. The design is loaded from --token-design file or
+// from the remote node of --token-design.remote.
+type TokenAmountFlags struct {
+	//revive:disable:line-length-limit
+	Human        bool   `name:"human" help:"amounts are in token units like \"12.5\", not raw integer units"`
+	TokenDesign  string `name:"token-design" help:"token design file for token units; output of \"network client token design\" or design json" type:"existingfile" placeholder:"PATH"`
+	DesignRemote string `name:"token-design.remote" help:"remote node conn info to get token design for token units" placeholder:"ConnInfo"`
+	//revive:enable:line-length-limit
+	decimal *common.Big
+	symbol  types.TokenSymbol
+}
+
+// amount parses s into the raw integer units. s without --human and symbol
+// suffix is the raw integer units as before.
+func (cmd *OperationCommand) amount(s string) (common.Big, error) {
+	s = strings.TrimSpace(s)
+
+	if !cmd.Human && isRawTokenAmount(s) {
+		b, err := common.NewBigFromString(s)
+		if err != nil {
+			return common.Big{}, errors.Wrapf(err, "invalid amount, %q", s)
+		}
+
+		return b, nil
+	}
+
+	if err := cmd.loadTokenDesign(); err != nil {
+		return common.Big{}, err
+	}
+
+	n := s
+	if i := strings.TrimSpace(strings.TrimSuffix(s, cmd.symbol.String())); len(cmd.symbol) > 0 && i != s {
+		n = i
+	}
+
+	b, err := parseTokenAmount(n, *cmd.decimal)
+	if err != nil {
+		return common.Big{}, errors.WithMessagef(err, "invalid amount, %q", s)
+	}
+
+	return b, nil
+}
+
+func (cmd *OperationCommand) loadTokenDesign() error {
+	if cmd.decimal != nil {
+		return nil
+	}
+
+	var design types.Design
+
+	switch {
+	case len(cmd.TokenDesign) > 0:
+		i, err := cmd.loadTokenDesignFile()
+		if err != nil {
+			return err
+		}
+
+		design = i
+	case len(cmd.DesignRemote) > 0:
+		i, err := cmd.loadTokenDesignRemote()
+		if err != nil {
+			return err
+		}
+
+		design = i
+	default:
+		return errors.Errorf("amount in token units needs --token-design or --token-design.remote")
+	}
+
+	decimal := design.Decimal()
+	cmd.decimal, cmd.symbol = &decimal, design.Symbol()
+
+	return nil
+}
+
+func (cmd *OperationCommand) loadTokenDesignFile() (types.Design, error) {
+	b, err := os.ReadFile(filepath.Clean(cmd.TokenDesign))
+	if err != nil {
+		return types.Design{}, errors.WithMessage(err, "read token design")
+	}
+
+	// NOTE the output of "network client token design" has the design under
+	// "design" with the contract.
+	var u struct {
+		Contract string          `json:"contract"`
+		Design   json.RawMessage `json:"design"`
+	}
+
+	if err := json.Unmarshal(b, &u); err != nil {
+		return types.Design{}, errors.WithMessage(err, "load token design")
+	}
+
+	if len(u.Design) > 0 {
+		if len(u.Contract) > 0 && u.Contract != cmd.contract.String() {
+			return types.Design{}, errors.Errorf("token design of other contract, %q", u.Contract)
+		}
+
+		b = u.Design
+	}
+
+	var design types.Design
+	if err := design.DecodeJSON(b, cmd.Encoder); err != nil {
+		return types.Design{}, errors.WithMessage(err, "load token design")
+	}
+
+	return design, nil
+}
+
+func (cmd *OperationCommand) loadTokenDesignRemote() (types.Design, error) {
+	key := state.NewStateKeyGenerator(cmd.contract.String()).Design()
+
+	switch st, found, err := remoteState(
+		cmd.Encoders, cmd.Encoder, cmd.NetworkID.NetworkID(), cmd.DesignRemote, key); {
+	case err != nil:
+		return types.Design{}, errors.WithMessage(err, "get token design")
+	case !found:
+		return types.Design{}, errors.Errorf("token design not found, contract %v", cmd.contract)
+	default:
+		design, err := state.StateDesignValue(st)
+		if err != nil {
+			return types.Design{}, err
+		}
+
+		return *design, nil
+	}
+}
+
+// maxTokenAmountDecimal limits the decimal of the token units, so the amount
+// in token units is not padded with the unbounded zeros.
+const maxTokenAmountDecimal = 255
+
+// tokenAmountDecimal returns the decimal of design as the number of decimal
+// places.
+func tokenAmountDecimal(decimal common.Big) (int, error) {
+	switch {
+	case decimal.Int == nil:
+		return 0, nil
+	case !decimal.Int.IsInt64(), decimal.Int.Int64() > maxTokenAmountDecimal:
+		return 0, errors.Errorf("too big decimal, %v; token units support up to %d decimal places",
+			decimal, maxTokenAmountDecimal)
+	case decimal.Int.Sign() < 0:
+		return 0, errors.Errorf("negative decimal, %v", decimal)
+	default:
+		return int(decimal.Int.Int64()), nil
+	}
+}
+
+func isRawTokenAmount(s string) bool {
+	return len(s) > 0 && strings.Trim(s, "0123456789") == ""
+}
+
+// parseTokenAmount parses the amount in token units, like "12.5", into the raw
+// integer units by decimal; the fraction longer than decimal is not allowed.
+func parseTokenAmount(s string, decimal common.Big) (common.Big, error) {
+	d, err := tokenAmountDecimal(decimal)
+	if err != nil {
+		return common.Big{}, err
+	}
+
+	whole, fraction, _ := strings.Cut(s, ".")
+
+	switch {
+	case len(whole) < 1 && len(fraction) < 1,
+		!isRawTokenAmount(whole) && len(whole) > 0,
+		!isRawTokenAmount(fraction) && len(fraction) > 0:
+		return common.Big{}, errors.Errorf("not number")
+	}
+
+	if fraction = strings.TrimRight(fraction, "0"); len(fraction) > d {
+		return common.Big{}, errors.Errorf("too much precision; token supports %d decimal places", d)
+	}
+
+	i, ok := new(big.Int).SetString(whole+fraction+strings.Repeat("0", d-len(fraction)), 10)
+	if !ok {
+		return common.Big{}, errors.Errorf("not number")
+	}
+
+	return common.NewBigFromBigInt(i), nil
+}
+
+// formatTokenAmount formats amount by decimal, like "1234.5" for 12345 with
+// decimal 1; trailing zeros of the fraction are removed. The amount is not
+// formatted when decimal is out of the token units.
+func formatTokenAmount(amount, decimal common.Big) string {
+	if amount.Int == nil {
+		return "0"
+	}
+
+	s := amount.Int.String()
+
+	d, err := tokenAmountDecimal(decimal)
+	if err != nil || d < 1 {
+		return s
+	}
+
+	var sign string
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+
+	if len(s) <= d {
+		s = strings.Repeat("0", d-len(s)+1) + s
+	}
+
+	whole, fraction := s[:len(s)-d], strings.TrimRight(s[len(s)-d:], "0")
+	if len(fraction) < 1 {
+		return sign + whole
+	}
+
+	return sign + whole + "." + fraction
+}
@@ -0,0 +1,299 @@
+package cmds
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"time"
+
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/currency-model/operation/test"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/token-model/operation/token"
+	"github.com/imfact-labs/token-model/types"
+	"github.com/pkg/errors"
+)
+
+// TokenBenchResult is the result of one benchmark; the allocations are
+// measured by runtime.MemStats, so the allocations of the other goroutines are
+// also counted.
+type TokenBenchResult struct {
+	Name        string        `json:"name"`
+	Operations  int           `json:"operations"`
+	Items       int           `json:"items"`
+	ApproveList int           `json:"approve_list"`
+	Elapsed     time.Duration `json:"elapsed"`
+	OpsPerSec   float64       `json:"ops_per_sec"`
+	AllocsPerOp uint64        `json:"allocs_per_op"`
+	BytesPerOp  uint64        `json:"bytes_per_op"`
+}
+
+// TokenBenchCommand runs the token processors and the mergers of the processed
+// state values, the token balances and the design, with the synthetic states
+// and operations in memory.
+type TokenBenchCommand struct { //nolint:govet //...
+	BaseCommand
+	Operations  int `name:"operations" help:"number of operations" default:"1000"`
+	Items       int `name:"items" help:"number of items in operation" default:"10"`
+	ApproveList int `name:"approve-list" help:"number of approve boxes in design" default:"10"`
+}
+
+func (cmd *TokenBenchCommand) Run(pctx context.Context) error {
+	if _, err := cmd.prepare(pctx); err != nil {
+		return err
+	}
+
+	switch {
+	case cmd.Operations < 1:
+		return errors.Errorf("operations should be over zero")
+	case cmd.Items < 1 || cmd.Items > token.MaxTransferItems:
+		return errors.Errorf("items should be between 1 and %d", token.MaxTransferItems)
+	case cmd.ApproveList < 1:
+		return errors.Errorf("approve list should be over zero")
+	}
+
+	b := newTokenBench(cmd.Items, cmd.ApproveList)
+
+	for _, f := range []func(int) ([]TokenBenchResult, error){
+		b.transfer,
+		b.transferFrom,
+	} {
+		results, err := f(cmd.Operations)
+		if err != nil {
+			return err
+		}
+
+		for i := range results {
+			out, err := util.MarshalJSON(results[i])
+			if err != nil {
+				return err
+			}
+
+			cmd.print("%s", out)
+		}
+	}
+
+	return nil
+}
+
+type tokenBench struct {
+	tp          *test.TestProcessor
+	contract    base.Address
+	sender      base.Address
+	receivers   []base.Address
+	targets     []base.Address
+	items       int
+	approveList int
+}
+
+func newTokenBench(items, approveList int) *tokenBench {
+	tp := &test.TestProcessor{}
+	tp.Setup(test.NewMockStateGetter())
+
+	b := &tokenBench{
+		tp:          tp,
+		contract:    tokenBenchAddress("contract", 0),
+		receivers:   make([]base.Address, items),
+		targets:     make([]base.Address, approveList),
+		items:       items,
+		approveList: approveList,
+	}
+
+	b.sender, _, _ = tp.NewTestAccountState(base.NewMPrivatekey().String(), true)
+
+	for i := range b.receivers {
+		b.receivers[i] = tokenBenchAddress("receiver", i)
+	}
+
+	supply := common.NewBig(1 << 62)
+
+	boxes := make([]types.ApproveBox, approveList)
+
+	for i := range b.targets {
+		b.targets[i], _, _ = tp.NewTestAccountState(base.NewMPrivatekey().String(), true)
+		boxes[i] = types.NewApproveBox(b.targets[i], []types.ApproveInfo{types.NewApproveInfo(b.sender, supply)})
+
+		token.NewTestTokenBalanceState(tp, b.contract, b.targets[i], 1<<62, true)
+	}
+
+	token.NewTestTokenBalanceState(tp, b.contract, b.sender, 1<<62, true)
+	token.NewTestDesignState(tp, b.contract, types.NewDesign(
+		types.TokenSymbol("BENCH"), "bench", common.NewBig(0), types.NewPolicy(supply, boxes),
+	), true)
+
+	return b
+}
+
+// tokenBenchAddress returns the valid address from the prefix and index.
+func tokenBenchAddress(prefix string, i int) ctypes.Address {
+	h := util.SHA256Checksum([]byte(fmt.Sprintf("%s-%d", prefix, i)))
+
+	var buf [42]byte
+
+	copy(buf[:2], "0x")
+	copy(buf[2:], h[:40])
+
+	return ctypes.NewAddress(string(ctypes.ChecksumHex(buf)))
+}
+
+func (b *tokenBench) transfer(n int) ([]TokenBenchResult, error) {
+	return b.run("transfer", token.NewTransferProcessor(), b.transferOperations(n))
+}
+
+func (b *tokenBench) transferOperations(n int) []base.Operation {
+	ops := make([]base.Operation, n)
+
+	for i := range ops {
+		items := make([]token.TransferItem, b.items)
+		for j := range items {
+			items[j] = token.NewTransferItem(b.contract, b.receivers[j], common.NewBig(1))
+		}
+
+		ops[i] = token.NewTransfer(token.NewTransferFact(
+			[]byte(fmt.Sprintf("bench-%d", i)), b.sender, items, b.tp.GenesisCurrency,
+		))
+	}
+
+	return ops
+}
+
+func (b *tokenBench) transferFrom(n int) ([]TokenBenchResult, error) {
+	return b.run("transfer-from", token.NewTransferFromProcessor(base.GenesisHeight), b.transferFromOperations(n))
+}
+
+func (b *tokenBench) transferFromOperations(n int) []base.Operation {
+	ops := make([]base.Operation, n)
+
+	// NOTE the last approve box is used to find the approve box of the whole
+	// approve list.
+	target := b.targets[len(b.targets)-1]
+
+	for i := range ops {
+		items := make([]token.TransferFromItem, b.items)
+		for j := range items {
+			items[j] = token.NewTransferFromItem(b.contract, b.receivers[j], target, common.NewBig(1))
+		}
+
+		ops[i] = token.NewTransferFrom(token.NewTransferFromFact(
+			[]byte(fmt.Sprintf("bench-%d", i)), b.sender, items, b.tp.GenesisCurrency,
+		))
+	}
+
+	return ops
+}
+
+func (b *tokenBench) run(name string, f ctypes.GetNewProcessor, ops []base.Operation) ([]TokenBenchResult, error) {
+	opp, err := f(base.Height(2), b.tp.GetStateFunc, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = opp.Close()
+	}()
+
+	ctx := context.Background()
+
+	pre, err := b.measure(name+"-preprocess", len(ops), func(i int) error {
+		switch _, reason, err := opp.PreProcess(ctx, ops[i], b.tp.GetStateFunc); {
+		case err != nil:
+			return err
+		case reason != nil:
+			return reason
+		default:
+			return nil
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stvs := make([][]base.StateMergeValue, len(ops))
+
+	process, err := b.measure(name+"-process", len(ops), func(i int) error {
+		switch values, reason, err := opp.Process(ctx, ops[i], b.tp.GetStateFunc); {
+		case err != nil:
+			return err
+		case reason != nil:
+			return reason
+		default:
+			stvs[i] = values
+
+			return nil
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	mergers := map[string]base.StateValueMerger{}
+
+	merge, err := b.measure(name+"-merge", len(ops), func(i int) error {
+		for j := range stvs[i] {
+			v := stvs[i][j]
+
+			m, found := mergers[v.Key()]
+			if !found {
+				st, _, err := b.tp.GetStateFunc(v.Key())
+				if err != nil {
+					return err
+				}
+
+				m = v.Merger(base.Height(2), st)
+				mergers[v.Key()] = m
+			}
+
+			if err := m.Merge(v.Value(), ops[i].Fact().Hash()); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for k := range mergers {
+		if _, err := mergers[k].CloseValue(); err != nil {
+			return nil, errors.WithMessagef(err, "close merger, %q", k)
+		}
+	}
+
+	return []TokenBenchResult{pre, process, merge}, nil
+}
+
+func (b *tokenBench) measure(name string, n int, f func(int) error) (TokenBenchResult, error) {
+	r := TokenBenchResult{
+		Name:        name,
+		Operations:  n,
+		Items:       b.items,
+		ApproveList: b.approveList,
+	}
+
+	runtime.GC()
+
+	var before, after runtime.MemStats
+
+	runtime.ReadMemStats(&before)
+
+	started := time.Now()
+
+	for i := 0; i < n; i++ {
+		if err := f(i); err != nil {
+			return r, errors.WithMessagef(err, "%s; operation=%d", name, i)
+		}
+	}
+
+	r.Elapsed = time.Since(started)
+
+	runtime.ReadMemStats(&after)
+
+	r.OpsPerSec = float64(n) / r.Elapsed.Seconds()
+	r.AllocsPerOp = (after.Mallocs - before.Mallocs) / uint64(n)
+	r.BytesPerOp = (after.TotalAlloc - before.TotalAlloc) / uint64(n)
+
+	return r, nil
+}
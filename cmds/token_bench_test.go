@@ -0,0 +1,146 @@
+package cmds
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/token-model/operation/token"
+)
+
+// tokenBenchApproveLists is the growing sizes of the approve list of the
+// benchmark design.
+var tokenBenchApproveLists = []int{1, 10, 100, 1000}
+
+const (
+	tokenBenchItems      = 10
+	tokenBenchOperations = 100
+)
+
+func BenchmarkTransferProcessor(b *testing.B) {
+	for _, n := range tokenBenchApproveLists {
+		b.Run(fmt.Sprintf("approve-list=%d", n), func(b *testing.B) {
+			t := newTokenBench(tokenBenchItems, n)
+
+			benchmarkTokenProcessor(b, t, token.NewTransferProcessor(), t.transferOperations(tokenBenchOperations))
+		})
+	}
+}
+
+func BenchmarkTransferFromProcessor(b *testing.B) {
+	for _, n := range tokenBenchApproveLists {
+		b.Run(fmt.Sprintf("approve-list=%d", n), func(b *testing.B) {
+			t := newTokenBench(tokenBenchItems, n)
+
+			benchmarkTokenProcessor(b, t, token.NewTransferFromProcessor(base.GenesisHeight), t.transferFromOperations(tokenBenchOperations))
+		})
+	}
+}
+
+// BenchmarkTokenMerger merges the state values of transfer-from, the token
+// balances and the design with the whole approve list.
+func BenchmarkTokenMerger(b *testing.B) {
+	for _, n := range tokenBenchApproveLists {
+		b.Run(fmt.Sprintf("approve-list=%d", n), func(b *testing.B) {
+			t := newTokenBench(tokenBenchItems, n)
+			ops := t.transferFromOperations(tokenBenchOperations)
+			stvs := processTokenBench(b, t, token.NewTransferFromProcessor(base.GenesisHeight), ops)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				mergers := map[string]base.StateValueMerger{}
+
+				for j := range stvs {
+					for k := range stvs[j] {
+						v := stvs[j][k]
+
+						m, found := mergers[v.Key()]
+						if !found {
+							st, _, err := t.tp.GetStateFunc(v.Key())
+							if err != nil {
+								b.Fatal(err)
+							}
+
+							m = v.Merger(base.Height(2), st)
+							mergers[v.Key()] = m
+						}
+
+						if err := m.Merge(v.Value(), ops[j].Fact().Hash()); err != nil {
+							b.Fatal(err)
+						}
+					}
+				}
+
+				for k := range mergers {
+					if _, err := mergers[k].CloseValue(); err != nil {
+						b.Fatalf("close merger, %q: %v", k, err)
+					}
+				}
+			}
+		})
+	}
+}
+
+func benchmarkTokenProcessor(b *testing.B, t *tokenBench, f ctypes.GetNewProcessor, ops []base.Operation) {
+	opp, err := f(base.Height(2), t.tp.GetStateFunc, nil, nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	defer func() {
+		_ = opp.Close()
+	}()
+
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		op := ops[i%len(ops)]
+
+		if _, reason, err := opp.PreProcess(ctx, op, t.tp.GetStateFunc); err != nil || reason != nil {
+			b.Fatalf("preprocess: %v, %v", err, reason)
+		}
+
+		if _, reason, err := opp.Process(ctx, op, t.tp.GetStateFunc); err != nil || reason != nil {
+			b.Fatalf("process: %v, %v", err, reason)
+		}
+	}
+}
+
+// processTokenBench processes the operations and returns the state merge
+// values of each operation.
+func processTokenBench(
+	b *testing.B, t *tokenBench, f ctypes.GetNewProcessor, ops []base.Operation,
+) [][]base.StateMergeValue {
+	opp, err := f(base.Height(2), t.tp.GetStateFunc, nil, nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	defer func() {
+		_ = opp.Close()
+	}()
+
+	stvs := make([][]base.StateMergeValue, len(ops))
+
+	for i := range ops {
+		if _, reason, err := opp.PreProcess(context.Background(), ops[i], t.tp.GetStateFunc); err != nil || reason != nil {
+			b.Fatalf("preprocess: %v, %v", err, reason)
+		}
+
+		values, reason, err := opp.Process(context.Background(), ops[i], t.tp.GetStateFunc)
+		if err != nil || reason != nil {
+			b.Fatalf("process: %v, %v", err, reason)
+		}
+
+		stvs[i] = values
+	}
+
+	return stvs
+}
@@ -0,0 +1,874 @@
+package cmds
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	ccmds "github.com/imfact-labs/currency-model/app/cmds"
+	"github.com/imfact-labs/currency-model/common"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	isaacnetwork "github.com/imfact-labs/mitum2/isaac/network"
+	"github.com/imfact-labs/mitum2/launch"
+	"github.com/imfact-labs/token-model/operation/token"
+	"github.com/imfact-labs/token-model/state"
+	"github.com/imfact-labs/token-model/types"
+	"github.com/pkg/errors"
+	"golang.org/x/term"
+)
+
+// TokenShellCommand is the interactive shell for token operations. The
+// privatekey, network id, remote node and contract are kept in the session,
+// so the operations are created, signed and sent without the long flags of
+// each command; every operation is confirmed before signing and sending.
+type TokenShellCommand struct { //nolint:govet //...
+	BaseCommand
+	//revive:disable:line-length-limit
+	Privatekey PrivatekeyFlag      `name:"privatekey" help:"privatekey to sign operations; also set by \"set key\" in shell" placeholder:"PRIVATEKEY"`
+	Sender     string              `name:"sender" help:"sender address" placeholder:"ADDRESS"`
+	Contract   string              `name:"contract" help:"token contract account" placeholder:"ADDRESS"`
+	Currency   string              `name:"currency" help:"currency id for fee" placeholder:"CURRENCY"`
+	NetworkID  ccmds.NetworkIDFlag `name:"network-id" help:"network-id" default:"${network_id}"`
+	Remote     string              `name:"remote" help:"remote node conn info" placeholder:"ConnInfo"`
+	API        string              `name:"api" help:"digest api url for history, like \"http://localhost:54320\"" placeholder:"URL"`
+	Timeout    time.Duration       `name:"timeout" help:"timeout of remote requests" default:"9s"`
+	//revive:enable:line-length-limit
+	session tokenShellSession
+	reader  tokenShellReader
+}
+
+type tokenShellSession struct {
+	priv      base.Privatekey
+	sender    base.Address
+	contract  base.Address
+	currency  ctypes.CurrencyID
+	networkID base.NetworkID
+	remote    *launch.ConnInfoFlag
+	api       string
+	human     bool
+	design    *types.Design
+	client    *isaacnetwork.BaseClient
+}
+
+type tokenShellReader interface {
+	ReadLine() (string, error)
+	ReadPassword(prompt string) (string, error)
+}
+
+type tokenShellCommandInfo struct {
+	usage string
+	help  string
+	run   func(context.Context, []string) error
+}
+
+var tokenShellSettings = []string{"key", "sender", "contract", "currency", "network-id", "remote", "api", "human"}
+
+func (cmd *TokenShellCommand) Run(pctx context.Context) error {
+	if _, err := cmd.prepare(pctx); err != nil {
+		return err
+	}
+
+	cmd.session = tokenShellSession{human: true}
+
+	defer cmd.closeClient()
+
+	if err := cmd.setFromFlags(); err != nil {
+		return err
+	}
+
+	if fd := int(os.Stdin.Fd()); term.IsTerminal(fd) {
+		old, err := term.MakeRaw(fd)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		defer func() {
+			_ = term.Restore(fd, old)
+		}()
+
+		t := term.NewTerminal(struct {
+			io.Reader
+			io.Writer
+		}{os.Stdin, os.Stdout}, "token> ")
+		t.AutoCompleteCallback = cmd.complete
+
+		cmd.reader, cmd.Out = t, t
+
+		cmd.print(`type "help" for commands; tab completes commands`)
+	} else {
+		cmd.reader = &tokenShellLineReader{s: bufio.NewScanner(os.Stdin)}
+	}
+
+	return cmd.loop(pctx)
+}
+
+func (cmd *TokenShellCommand) loop(ctx context.Context) error {
+	commands := cmd.commands()
+
+	for {
+		line, err := cmd.reader.ReadLine()
+
+		switch {
+		case errors.Is(err, io.EOF):
+			return nil
+		case err != nil:
+			return errors.WithStack(err)
+		}
+
+		args := strings.Fields(line)
+		if len(args) < 1 {
+			continue
+		}
+
+		if args[0] == "exit" || args[0] == "quit" {
+			return nil
+		}
+
+		c, found := commands[args[0]]
+		if !found {
+			cmd.print("unknown command, %q", args[0])
+
+			continue
+		}
+
+		if err := c.run(ctx, args[1:]); err != nil {
+			cmd.print("error: %v", err)
+		}
+	}
+}
+
+func (cmd *TokenShellCommand) commands() map[string]tokenShellCommandInfo {
+	return map[string]tokenShellCommandInfo{
+		"balance": {
+			usage: "balance [address]", help: "token balance; default is sender", run: cmd.balance,
+		},
+		"transfer": {
+			usage: "transfer <receiver> <amount>", help: "transfer token to receiver", run: cmd.transfer,
+		},
+		"approve": {
+			usage: "approve <spender> <amount>", help: "approve token to spender", run: cmd.approve,
+		},
+		"history": {
+			usage: "history [address]", help: "recent token operations of account from --api", run: cmd.history,
+		},
+		"set": {
+			usage: "set <name> [value]",
+			help:  "set session value of " + strings.Join(tokenShellSettings, ", ") + "; \"set key\" reads key without echo",
+			run:   cmd.setCommand,
+		},
+		"show": {
+			usage: "show", help: "show session values", run: cmd.show,
+		},
+		"help": {
+			usage: "help", help: "show commands", run: cmd.help,
+		},
+	}
+}
+
+func (cmd *TokenShellCommand) commandNames() []string {
+	commands := cmd.commands()
+
+	names := make([]string, 0, len(commands)+2)
+	for i := range commands {
+		names = append(names, i)
+	}
+
+	names = append(names, "exit", "quit")
+	sort.Strings(names)
+
+	return names
+}
+
+func (cmd *TokenShellCommand) help(context.Context, []string) error {
+	commands := cmd.commands()
+	names := cmd.commandNames()
+
+	for i := range names {
+		c, found := commands[names[i]]
+		if !found {
+			continue
+		}
+
+		cmd.print("  %-30s %s", c.usage, c.help)
+	}
+
+	cmd.print("  %-30s %s", "exit", "exit shell")
+
+	return nil
+}
+
+// complete completes the command name and the setting name of "set" by tab.
+func (cmd *TokenShellCommand) complete(line string, pos int, key rune) (string, int, bool) {
+	if key != '\t' {
+		return "", 0, false
+	}
+
+	head := line[:pos]
+	fields := strings.Fields(head)
+	inWord := len(head) > 0 && !strings.HasSuffix(head, " ")
+
+	var candidates []string
+	var word string
+
+	switch {
+	case len(fields) < 1:
+		candidates = cmd.commandNames()
+	case len(fields) == 1 && inWord:
+		candidates, word = cmd.commandNames(), fields[0]
+	case fields[0] == "set" && len(fields) == 1:
+		candidates = tokenShellSettings
+	case fields[0] == "set" && len(fields) == 2 && inWord:
+		candidates, word = tokenShellSettings, fields[1]
+	default:
+		return "", 0, false
+	}
+
+	var matches []string
+
+	for i := range candidates {
+		if strings.HasPrefix(candidates[i], word) {
+			matches = append(matches, candidates[i])
+		}
+	}
+
+	if len(matches) < 1 {
+		return "", 0, false
+	}
+
+	completed := matches[0]
+
+	if len(matches) == 1 {
+		completed += " "
+	} else {
+		for i := range matches[1:] {
+			for !strings.HasPrefix(matches[i+1], completed) {
+				completed = completed[:len(completed)-1]
+			}
+		}
+	}
+
+	newHead := head[:len(head)-len(word)] + completed
+
+	return newHead + line[pos:], len(newHead), true
+}
+
+func (cmd *TokenShellCommand) setFromFlags() error {
+	if cmd.Privatekey.Privatekey != nil {
+		cmd.session.priv = cmd.Privatekey.Privatekey
+	}
+
+	for _, i := range [][2]string{
+		{"network-id", string(cmd.NetworkID)},
+		{"sender", cmd.Sender},
+		{"contract", cmd.Contract},
+		{"currency", cmd.Currency},
+		{"remote", cmd.Remote},
+		{"api", cmd.API},
+	} {
+		if len(i[1]) < 1 {
+			continue
+		}
+
+		if err := cmd.set(i[0], i[1]); err != nil {
+			return errors.WithMessagef(err, "--%s", i[0])
+		}
+	}
+
+	return nil
+}
+
+func (cmd *TokenShellCommand) setCommand(_ context.Context, args []string) error {
+	switch {
+	case len(args) == 1 && args[0] == "key":
+		s, err := cmd.reader.ReadPassword("privatekey: ")
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		return cmd.set("key", strings.TrimSpace(s))
+	case len(args) != 2:
+		return errors.Errorf("usage: %s", cmd.commands()["set"].usage)
+	default:
+		return cmd.set(args[0], args[1])
+	}
+}
+
+func (cmd *TokenShellCommand) set(name, value string) error {
+	s := &cmd.session
+
+	switch name {
+	case "key":
+		var k PrivatekeyFlag
+		if err := k.UnmarshalText([]byte(value)); err != nil {
+			return err
+		}
+
+		s.priv = k.Privatekey
+	case "sender":
+		a, err := base.DecodeAddress(value, cmd.Encoder)
+		if err != nil {
+			return errors.Wrapf(err, "invalid sender, %q", value)
+		}
+
+		s.sender = a
+	case "contract":
+		a, err := base.DecodeAddress(value, cmd.Encoder)
+		if err != nil {
+			return errors.Wrapf(err, "invalid contract account, %q", value)
+		}
+
+		s.contract, s.design = a, nil
+	case "currency":
+		cid := ctypes.CurrencyID(value)
+		if err := cid.IsValid(nil); err != nil {
+			return err
+		}
+
+		s.currency = cid
+	case "network-id":
+		networkID := base.NetworkID(value)
+		if err := networkID.IsValid(nil); err != nil {
+			return err
+		}
+
+		s.networkID = networkID
+		cmd.closeClient()
+	case "remote":
+		var ci launch.ConnInfoFlag
+		if err := ci.UnmarshalText([]byte(value)); err != nil {
+			return err
+		}
+
+		s.remote, s.design = &ci, nil
+	case "api":
+		if _, err := url.Parse(value); err != nil {
+			return errors.Wrapf(err, "invalid api url, %q", value)
+		}
+
+		s.api = strings.TrimRight(value, "/")
+	case "human":
+		switch value {
+		case "on", "true":
+			s.human = true
+		case "off", "false":
+			s.human = false
+		default:
+			return errors.Errorf(`expected "on" or "off", not %q`, value)
+		}
+	default:
+		return errors.Errorf("unknown setting, %q", name)
+	}
+
+	return nil
+}
+
+func (cmd *TokenShellCommand) show(context.Context, []string) error {
+	s := cmd.session
+
+	var key, remote string
+
+	if s.priv != nil {
+		key = s.priv.Publickey().String() + " (publickey)"
+	}
+
+	if s.remote != nil {
+		remote = s.remote.String()
+	}
+
+	human := "off"
+	if s.human {
+		human = "on"
+	}
+
+	for _, i := range [][2]string{
+		{"key", key},
+		{"sender", addressString(s.sender)},
+		{"contract", addressString(s.contract)},
+		{"currency", s.currency.String()},
+		{"network-id", string(s.networkID)},
+		{"remote", remote},
+		{"api", s.api},
+		{"human", human},
+	} {
+		cmd.print("  %-12s %s", i[0], i[1])
+	}
+
+	return nil
+}
+
+func (cmd *TokenShellCommand) balance(ctx context.Context, args []string) error {
+	account := cmd.session.sender
+
+	switch {
+	case len(args) > 1:
+		return errors.Errorf("usage: %s", cmd.commands()["balance"].usage)
+	case len(args) == 1:
+		a, err := base.DecodeAddress(args[0], cmd.Encoder)
+		if err != nil {
+			return errors.Wrapf(err, "invalid account, %q", args[0])
+		}
+
+		account = a
+	case account == nil:
+		return errors.Errorf(`expected address or "set sender"`)
+	}
+
+	design, err := cmd.design(ctx)
+	if err != nil {
+		return err
+	}
+
+	amount := common.ZeroBig
+
+	key := state.NewStateKeyGenerator(cmd.session.contract.String()).TokenBalance(account.String())
+
+	switch st, found, err := cmd.state(ctx, key); {
+	case err != nil:
+		return err
+	case found:
+		i, err := state.StateTokenBalanceValue(st)
+		if err != nil {
+			return err
+		}
+
+		amount = i
+	}
+
+	cmd.print("%s %s (raw %s)", formatTokenAmount(amount, design.Decimal()), design.Symbol(), amount.String())
+
+	return nil
+}
+
+func (cmd *TokenShellCommand) transfer(ctx context.Context, args []string) error {
+	if len(args) != 2 {
+		return errors.Errorf("usage: %s", cmd.commands()["transfer"].usage)
+	}
+
+	receiver, err := base.DecodeAddress(args[0], cmd.Encoder)
+	if err != nil {
+		return errors.Wrapf(err, "invalid receiver, %q", args[0])
+	}
+
+	oc, design, err := cmd.operationCommand(ctx)
+	if err != nil {
+		return err
+	}
+
+	amount, err := oc.amount(args[1])
+	if err != nil {
+		return err
+	}
+
+	item := token.NewTransferItem(oc.contract, receiver, amount)
+	if err := item.IsValid(nil); err != nil {
+		return err
+	}
+
+	op := token.NewTransfer(token.NewTransferFact(
+		[]byte(oc.Token), oc.sender, []token.TransferItem{item}, cmd.session.currency,
+	))
+
+	if !cmd.confirm("transfer", amount, design, [2]string{"to", receiver.String()}) {
+		return nil
+	}
+
+	if err := oc.sign(&op); err != nil {
+		return err
+	}
+
+	return cmd.send(ctx, op)
+}
+
+func (cmd *TokenShellCommand) approve(ctx context.Context, args []string) error {
+	if len(args) != 2 {
+		return errors.Errorf("usage: %s", cmd.commands()["approve"].usage)
+	}
+
+	spender, err := base.DecodeAddress(args[0], cmd.Encoder)
+	if err != nil {
+		return errors.Wrapf(err, "invalid spender, %q", args[0])
+	}
+
+	oc, design, err := cmd.operationCommand(ctx)
+	if err != nil {
+		return err
+	}
+
+	amount, err := oc.amount(args[1])
+	if err != nil {
+		return err
+	}
+
+	item := token.NewApproveItem(oc.contract, spender, amount)
+	if err := item.IsValid(nil); err != nil {
+		return err
+	}
+
+	op := token.NewApprove(token.NewApproveFact(
+		[]byte(oc.Token), oc.sender, []token.ApproveItem{item}, cmd.session.currency,
+	))
+
+	if !cmd.confirm("approve", amount, design, [2]string{"spender", spender.String()}) {
+		return nil
+	}
+
+	if err := oc.sign(&op); err != nil {
+		return err
+	}
+
+	return cmd.send(ctx, op)
+}
+
+// operationCommand prepares OperationCommand from the session to share the
+// amount parsing and signing of the operation commands; the token design is
+// loaded from the remote node for the amount in token units.
+func (cmd *TokenShellCommand) operationCommand(ctx context.Context) (*OperationCommand, types.Design, error) {
+	s := cmd.session
+
+	switch {
+	case s.priv == nil:
+		return nil, types.Design{}, errors.Errorf(`expected "set key"`)
+	case s.sender == nil:
+		return nil, types.Design{}, errors.Errorf(`expected "set sender"`)
+	case len(s.currency) < 1:
+		return nil, types.Design{}, errors.Errorf(`expected "set currency"`)
+	}
+
+	design, err := cmd.design(ctx)
+	if err != nil {
+		return nil, types.Design{}, err
+	}
+
+	decimal := design.Decimal()
+
+	oc := &OperationCommand{
+		BaseCommand: cmd.BaseCommand,
+		OperationFlags: OperationFlags{
+			Privatekey: PrivatekeyFlag{Privatekey: s.priv},
+			NetworkID:  ccmds.NetworkIDFlag(s.networkID),
+		},
+		TokenAmountFlags: TokenAmountFlags{
+			Human:   s.human,
+			decimal: &decimal,
+			symbol:  design.Symbol(),
+		},
+		sender:   s.sender,
+		contract: s.contract,
+	}
+
+	if err := oc.OperationFlags.IsValid(nil); err != nil {
+		return nil, types.Design{}, err
+	}
+
+	return oc, design, nil
+}
+
+func (cmd *TokenShellCommand) confirm(
+	name string, amount common.Big, design types.Design, target [2]string,
+) bool {
+	s := cmd.session
+
+	for _, i := range [][2]string{
+		{name, fmt.Sprintf("%s %s (raw %s)", formatTokenAmount(amount, design.Decimal()), design.Symbol(), amount)},
+		{"from", s.sender.String()},
+		target,
+		{"contract", s.contract.String()},
+		{"currency", s.currency.String()},
+		{"network-id", string(s.networkID)},
+		{"remote", s.remote.String()},
+	} {
+		cmd.print("  %-12s %s", i[0], i[1])
+	}
+
+	if t, ok := cmd.reader.(*term.Terminal); ok {
+		t.SetPrompt("sign and send? [y/N] ")
+		defer t.SetPrompt("token> ")
+	} else {
+		cmd.print("sign and send? [y/N]")
+	}
+
+	line, err := cmd.reader.ReadLine()
+	if err != nil {
+		return false
+	}
+
+	switch strings.ToLower(strings.TrimSpace(line)) {
+	case "y", "yes":
+		return true
+	default:
+		cmd.print("canceled")
+
+		return false
+	}
+}
+
+func (cmd *TokenShellCommand) send(ctx context.Context, op base.Operation) error {
+	if err := op.IsValid(cmd.session.networkID); err != nil {
+		return err
+	}
+
+	client, err := cmd.client()
+	if err != nil {
+		return err
+	}
+
+	nctx, cancel := context.WithTimeout(ctx, cmd.Timeout)
+	defer cancel()
+
+	switch sent, err := client.SendOperation(nctx, cmd.session.remote.ConnInfo(), op); {
+	case err != nil:
+		return errors.WithMessage(err, "send operation")
+	case !sent:
+		return errors.Errorf("operation not sent, fact %v", op.Fact().Hash())
+	default:
+		cmd.print("sent, fact %v", op.Fact().Hash())
+
+		return nil
+	}
+}
+
+func (cmd *TokenShellCommand) design(ctx context.Context) (types.Design, error) {
+	if cmd.session.design != nil {
+		return *cmd.session.design, nil
+	}
+
+	if cmd.session.contract == nil {
+		return types.Design{}, errors.Errorf(`expected "set contract"`)
+	}
+
+	switch st, found, err := cmd.state(ctx, state.NewStateKeyGenerator(cmd.session.contract.String()).Design()); {
+	case err != nil:
+		return types.Design{}, err
+	case !found:
+		return types.Design{}, errors.Errorf("token design not found, contract %v", cmd.session.contract)
+	default:
+		design, err := state.StateDesignValue(st)
+		if err != nil {
+			return types.Design{}, err
+		}
+
+		cmd.session.design = design
+
+		return *design, nil
+	}
+}
+
+func (cmd *TokenShellCommand) state(ctx context.Context, key string) (base.State, bool, error) {
+	client, err := cmd.client()
+	if err != nil {
+		return nil, false, err
+	}
+
+	nctx, cancel := context.WithTimeout(ctx, cmd.Timeout)
+	defer cancel()
+
+	return client.State(nctx, cmd.session.remote.ConnInfo(), key, nil)
+}
+
+func (cmd *TokenShellCommand) client() (*isaacnetwork.BaseClient, error) {
+	if cmd.session.remote == nil {
+		return nil, errors.Errorf(`expected "set remote"`)
+	}
+
+	if cmd.session.client != nil {
+		return cmd.session.client, nil
+	}
+
+	client, err := newNetworkClient(cmd.Encoders, cmd.Encoder, cmd.session.networkID)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd.session.client = client
+
+	return client, nil
+}
+
+func (cmd *TokenShellCommand) closeClient() {
+	if cmd.session.client != nil {
+		_ = cmd.session.client.Close()
+
+		cmd.session.client = nil
+	}
+}
+
+// history shows the token operations of account in the recent operations
+// from the digest api; the operations of other contracts are skipped.
+func (cmd *TokenShellCommand) history(ctx context.Context, args []string) error {
+	account := cmd.session.sender
+
+	switch {
+	case len(args) > 1:
+		return errors.Errorf("usage: %s", cmd.commands()["history"].usage)
+	case len(args) == 1:
+		a, err := base.DecodeAddress(args[0], cmd.Encoder)
+		if err != nil {
+			return errors.Wrapf(err, "invalid account, %q", args[0])
+		}
+
+		account = a
+	case account == nil:
+		return errors.Errorf(`expected address or "set sender"`)
+	}
+
+	switch {
+	case len(cmd.session.api) < 1:
+		return errors.Errorf(`expected "set api"`)
+	case cmd.session.contract == nil:
+		return errors.Errorf(`expected "set contract"`)
+	}
+
+	ops, err := cmd.requestOperations(ctx, account)
+	if err != nil {
+		return err
+	}
+
+	var n int
+
+	for i := range ops {
+		op := ops[i].Embedded
+
+		name, ok := tokenShellOperationName(op.Operation.Fact, cmd.session.contract.String())
+		if !ok {
+			continue
+		}
+
+		result := "ok"
+		if !op.InState {
+			result = "failed: " + op.Reason
+		}
+
+		cmd.print("%8d  %s  %-13s %s  %s", op.Height, op.ConfirmedAt, name, op.Operation.Fact.Hash, result)
+
+		n++
+	}
+
+	if n < 1 {
+		cmd.print("no token operations")
+	}
+
+	return nil
+}
+
+type tokenShellOperationValue struct {
+	Embedded struct {
+		Operation struct {
+			Fact tokenShellFact `json:"fact"`
+		} `json:"operation"`
+		ConfirmedAt string `json:"confirmed_at"`
+		Reason      string `json:"reason"`
+		Height      int64  `json:"height"`
+		InState     bool   `json:"in_state"`
+	} `json:"_embedded"`
+}
+
+type tokenShellFact struct {
+	Hint     string `json:"_hint"`
+	Hash     string `json:"hash"`
+	Contract string `json:"contract"`
+	Items    []struct {
+		Contract string `json:"contract"`
+	} `json:"items"`
+}
+
+func (cmd *TokenShellCommand) requestOperations(
+	ctx context.Context, account base.Address,
+) ([]tokenShellOperationValue, error) {
+	nctx, cancel := context.WithTimeout(ctx, cmd.Timeout)
+	defer cancel()
+
+	u := cmd.session.api + "/account/" + url.PathEscape(account.String()) + "/operations?reverse=1"
+
+	req, err := http.NewRequestWithContext(nctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, errors.WithMessage(err, "request operations")
+	}
+
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	switch {
+	case res.StatusCode == http.StatusNotFound:
+		return nil, nil
+	case res.StatusCode != http.StatusOK:
+		return nil, errors.Errorf("request operations, %q", res.Status)
+	}
+
+	var body struct {
+		Embedded []tokenShellOperationValue `json:"_embedded"`
+	}
+
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		return nil, errors.WithMessage(err, "load operations")
+	}
+
+	return body.Embedded, nil
+}
+
+// tokenShellOperationName returns the short operation name of token fact, like
+// "transfer" of "mitum-token-transfer-operation-fact-v0.0.1", when the fact is
+// for contract.
+func tokenShellOperationName(fact tokenShellFact, contract string) (string, bool) {
+	name, found := strings.CutPrefix(fact.Hint, "mitum-token-")
+	if !found {
+		return "", false
+	}
+
+	name, _, _ = strings.Cut(name, "-operation-fact-")
+
+	if fact.Contract == contract {
+		return name, true
+	}
+
+	for i := range fact.Items {
+		if fact.Items[i].Contract == contract {
+			return name, true
+		}
+	}
+
+	return "", false
+}
+
+func addressString(a base.Address) string {
+	if a == nil {
+		return ""
+	}
+
+	return a.String()
+}
+
+// tokenShellLineReader reads the commands from non-terminal input like pipe.
+type tokenShellLineReader struct {
+	s *bufio.Scanner
+}
+
+func (r *tokenShellLineReader) ReadLine() (string, error) {
+	if !r.s.Scan() {
+		if err := r.s.Err(); err != nil {
+			return "", err
+		}
+
+		return "", io.EOF
+	}
+
+	return r.s.Text(), nil
+}
+
+func (r *tokenShellLineReader) ReadPassword(string) (string, error) {
+	return r.ReadLine()
+}
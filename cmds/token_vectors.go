@@ -0,0 +1,337 @@
+package cmds
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"sort"
+
+	"github.com/imfact-labs/currency-model/common"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/currency-model/utils/bsonenc"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/encoder"
+	"github.com/imfact-labs/mitum2/util/hint"
+	"github.com/imfact-labs/token-model/operation/token"
+	"github.com/imfact-labs/token-model/state"
+	"github.com/imfact-labs/token-model/types"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// TokenVector is the reference encoding of fact or state value for the client
+// implementations. JSON is the compact json, BSON is the hex of bson with the
+// keys sorted, Bytes is the hex of Bytes() of fact or HashBytes() of state
+// value and Hash is the fact hash.
+type TokenVector struct {
+	Name  string `json:"name"`
+	Hint  string `json:"hint"`
+	JSON  string `json:"json"`
+	BSON  string `json:"bson"`
+	Bytes string `json:"bytes"`
+	Hash  string `json:"hash,omitempty"`
+}
+
+type TokenVectorsCommand struct { //nolint:govet //...
+	Generate TokenVectorsGenerateCommand `cmd:"" name:"generate" help:"generate encoding vectors of token facts and state values"` // revive:disable-line:line-length-limit
+	Verify   TokenVectorsVerifyCommand   `cmd:"" name:"verify" help:"verify encoding vectors of token facts and state values"`     // revive:disable-line:line-length-limit
+}
+
+type TokenVectorsGenerateCommand struct { //nolint:govet //...
+	BaseCommand
+	Output string `name:"output" help:"output file; default is stdout" placeholder:"file"`
+}
+
+func (cmd *TokenVectorsGenerateCommand) Run(pctx context.Context) error {
+	if _, err := cmd.prepare(pctx); err != nil {
+		return err
+	}
+
+	vectors, err := TokenVectors()
+	if err != nil {
+		return err
+	}
+
+	b, err := util.MarshalJSONIndent(map[string]interface{}{"vectors": vectors})
+	if err != nil {
+		return err
+	}
+
+	b = append(b, '\n')
+
+	if len(cmd.Output) > 0 {
+		return os.WriteFile(cmd.Output, b, 0o600)
+	}
+
+	_, err = cmd.Out.Write(b)
+
+	return err
+}
+
+type TokenVectorsVerifyCommand struct { //nolint:govet //...
+	BaseCommand
+	Vectors string `arg:"" name:"vectors" help:"vectors file" type:"existingfile"`
+}
+
+func (cmd *TokenVectorsVerifyCommand) Run(pctx context.Context) error {
+	if _, err := cmd.prepare(pctx); err != nil {
+		return err
+	}
+
+	b, err := os.ReadFile(cmd.Vectors)
+	if err != nil {
+		return errors.WithMessage(err, "read vectors")
+	}
+
+	var u struct {
+		Vectors []TokenVector `json:"vectors"`
+	}
+
+	if err := json.Unmarshal(b, &u); err != nil {
+		return errors.WithMessage(err, "decode vectors")
+	}
+
+	expected, err := TokenVectors()
+	if err != nil {
+		return err
+	}
+
+	if len(u.Vectors) != len(expected) {
+		return errors.Errorf("number of vectors not matched; expected=%d vectors=%d", len(expected), len(u.Vectors))
+	}
+
+	var failed int
+
+	for i := range expected {
+		if err := cmd.verify(expected[i], u.Vectors[i]); err != nil {
+			cmd.Log.Error().Err(err).Str("name", expected[i].Name).Msg("vector not matched")
+
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return errors.Errorf("vectors not matched; failed=%d", failed)
+	}
+
+	cmd.print("%d vectors verified", len(expected))
+
+	return nil
+}
+
+func (cmd *TokenVectorsVerifyCommand) verify(expected, v TokenVector) error {
+	switch {
+	case expected.Name != v.Name:
+		return errors.Errorf("name; expected=%q vector=%q", expected.Name, v.Name)
+	case expected.Hint != v.Hint:
+		return errors.Errorf("hint; expected=%q vector=%q", expected.Hint, v.Hint)
+	case expected.JSON != v.JSON:
+		return errors.Errorf("json; expected=%s vector=%s", expected.JSON, v.JSON)
+	case expected.BSON != v.BSON:
+		return errors.Errorf("bson; expected=%s vector=%s", expected.BSON, v.BSON)
+	case expected.Bytes != v.Bytes:
+		return errors.Errorf("bytes; expected=%s vector=%s", expected.Bytes, v.Bytes)
+	case expected.Hash != v.Hash:
+		return errors.Errorf("hash; expected=%s vector=%s", expected.Hash, v.Hash)
+	}
+
+	benc, found := cmd.Encoders.Find(bsonenc.BSONEncoderHint)
+	if !found {
+		return errors.Errorf("bson encoder not found")
+	}
+
+	bb, err := hex.DecodeString(v.BSON)
+	if err != nil {
+		return errors.WithMessage(err, "bson")
+	}
+
+	for enc, b := range map[encoder.Encoder][]byte{cmd.Encoders.JSON(): []byte(v.JSON), benc: bb} {
+		i, err := enc.Decode(b)
+		if err != nil {
+			return errors.WithMessagef(err, "decode by %v", enc.Hint())
+		}
+
+		switch d, err := newTokenVector(v.Name, i); {
+		case err != nil:
+			return errors.WithMessagef(err, "decoded by %v", enc.Hint())
+		case d != v:
+			return errors.Errorf("decoded by %v not matched; decoded=%+v", enc.Hint(), d)
+		}
+	}
+
+	return nil
+}
+
+// TokenVectors returns the vectors of the token facts and state values from
+// the fixed values; the order and the values should not be changed, except
+// new vectors are appended.
+func TokenVectors() ([]TokenVector, error) {
+	sender := ctypes.NewAddress("0xCE8133c8498F5034296947645dd9E83c9c5288E9")
+	contract := ctypes.NewAddress("0x6DC072838c96e06B4bbc763A8D0687Dc4a22f95E")
+	receiver := ctypes.NewAddress("0x936E736056C2B51702b505B72433C8A305ad2675")
+	target := ctypes.NewAddress("0xE7Cc9f5F0E730048a6ff16C4e2f1B7b2546a1587")
+
+	gk, err := base.ParseMPublickey("cJt4J76YkqUwBCKkATEev3vedPu24qFXkRUY8518taXrmpu")
+	if err != nil {
+		return nil, err
+	}
+
+	ft := []byte("token-vector")
+	cid := ctypes.CurrencyID("MCC")
+	approveList := []types.ApproveBox{
+		types.NewApproveBox(sender, []types.ApproveInfo{
+			types.NewApproveInfo(receiver, common.NewBig(30)),
+		}),
+	}
+	design := types.NewDesign(
+		types.TokenSymbol("VEC"), "vector token", common.NewBig(18), types.NewPolicy(common.NewBig(1000), approveList),
+	)
+
+	sources := []struct {
+		name string
+		v    interface{}
+	}{
+		{name: "register-model-fact", v: token.NewRegisterModelFact(
+			ft, sender, contract, cid, types.TokenSymbol("VEC"), "vector token", common.NewBig(18), common.NewBig(1000),
+		)},
+		{name: "mint-fact", v: token.NewMintFact(ft, sender, contract, cid, receiver, common.NewBig(100))},
+		{name: "burn-fact", v: token.NewBurnFact(ft, sender, contract, cid, sender, common.NewBig(10))},
+		{name: "approve-fact", v: token.NewApproveFact(ft, sender, []token.ApproveItem{
+			token.NewApproveItem(contract, receiver, common.NewBig(30)),
+		}, cid)},
+		{name: "transfer-fact", v: token.NewTransferFact(ft, sender, []token.TransferItem{
+			token.NewTransferItem(contract, receiver, common.NewBig(20)),
+			token.NewTransferItem(contract, target, common.NewBig(5)),
+		}, cid)},
+		{name: "transfer-from-fact", v: token.NewTransferFromFact(ft, receiver, []token.TransferFromItem{
+			token.NewTransferFromItem(contract, target, sender, common.NewBig(15)),
+		}, cid)},
+		{name: "release-symbol-fact", v: token.NewReleaseSymbolFact(
+			ft, sender, contract, cid, types.TokenSymbol("VEC"),
+		)},
+		{name: "backfill-symbol-fact", v: token.NewBackfillSymbolFact(
+			ft, sender, contract, cid, types.TokenSymbol("VEC"),
+		)},
+		{name: "register-genesis-token-fact", v: token.NewRegisterGenesisTokenFact(
+			[]byte("genesis"), gk, sender, contract, types.TokenSymbol("VEC"), "vector token", common.NewBig(18),
+			[]token.GenesisBalance{
+				token.NewGenesisBalance(sender, common.NewBig(900)),
+				token.NewGenesisBalance(receiver, common.NewBig(100)),
+			},
+			approveList,
+		)},
+		{name: "design-state-value", v: state.NewDesignStateValue(design)},
+		{name: "token-balance-state-value", v: state.NewTokenBalanceStateValue(common.NewBig(900))},
+		{name: "symbol-state-value", v: state.NewSymbolStateValue(contract, false)},
+	}
+
+	vectors := make([]TokenVector, len(sources))
+
+	for i := range sources {
+		v, err := newTokenVector(sources[i].name, sources[i].v)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "vector, %q", sources[i].name)
+		}
+
+		vectors[i] = v
+	}
+
+	return vectors, nil
+}
+
+func newTokenVector(name string, i interface{}) (TokenVector, error) {
+	v := TokenVector{Name: name}
+
+	hr, ok := i.(hint.Hinter)
+	if !ok {
+		return v, errors.Errorf("expected hint.Hinter, not %T", i)
+	}
+
+	v.Hint = hr.Hint().String()
+
+	if iv, ok := i.(util.IsValider); ok {
+		if err := iv.IsValid(nil); err != nil {
+			return v, err
+		}
+	}
+
+	jb, err := util.MarshalJSON(i)
+	if err != nil {
+		return v, err
+	}
+
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, jb); err != nil {
+		return v, err
+	}
+
+	v.JSON = buf.String()
+
+	bb, err := bsonenc.Marshal(i)
+	if err != nil {
+		return v, err
+	}
+
+	bb, err = canonicalBSON(bb)
+	if err != nil {
+		return v, err
+	}
+
+	v.BSON = hex.EncodeToString(bb)
+
+	switch t := i.(type) {
+	case base.Fact:
+		br, ok := i.(util.Byter)
+		if !ok {
+			return v, errors.Errorf("expected util.Byter, not %T", i)
+		}
+
+		v.Bytes = hex.EncodeToString(br.Bytes())
+		v.Hash = t.Hash().String()
+	case base.StateValue:
+		v.Bytes = hex.EncodeToString(t.HashBytes())
+	default:
+		return v, errors.Errorf("expected fact or state value, not %T", i)
+	}
+
+	return v, nil
+}
+
+// canonicalBSON sorts the keys of bson document; the bson of facts and state
+// values is marshaled from map, so the order of keys is not fixed.
+func canonicalBSON(b []byte) ([]byte, error) {
+	var d bson.D
+	if err := bson.Unmarshal(b, &d); err != nil {
+		return nil, err
+	}
+
+	return bson.Marshal(sortBSON(d))
+}
+
+func sortBSON(i interface{}) interface{} {
+	switch t := i.(type) {
+	case bson.D:
+		d := make(bson.D, len(t))
+		for j := range t {
+			d[j] = bson.E{Key: t[j].Key, Value: sortBSON(t[j].Value)}
+		}
+
+		sort.SliceStable(d, func(a, b int) bool {
+			return d[a].Key < d[b].Key
+		})
+
+		return d
+	case bson.A:
+		a := make(bson.A, len(t))
+		for j := range t {
+			a[j] = sortBSON(t[j])
+		}
+
+		return a
+	default:
+		return i
+	}
+}
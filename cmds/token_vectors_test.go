@@ -0,0 +1,58 @@
+package cmds
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/imfact-labs/currency-model/utils/bsonenc"
+	"github.com/imfact-labs/mitum2/util/encoder"
+	jsonenc "github.com/imfact-labs/mitum2/util/encoder/json"
+	"github.com/imfact-labs/token-model/runtime/steps"
+)
+
+func TestTokenVectors(t *testing.T) {
+	b, err := os.ReadFile(filepath.Join("..", "vectors", "token-vectors.json"))
+	if err != nil {
+		t.Fatalf("read vectors: %v", err)
+	}
+
+	var u struct {
+		Vectors []TokenVector `json:"vectors"`
+	}
+
+	if err := json.Unmarshal(b, &u); err != nil {
+		t.Fatalf("decode vectors: %v", err)
+	}
+
+	jenc := jsonenc.NewEncoder()
+	encs := encoder.NewEncoders(jenc, jenc)
+
+	if err := encs.AddEncoder(bsonenc.NewEncoder()); err != nil {
+		t.Fatalf("add bson encoder: %v", err)
+	}
+
+	if err := steps.LoadHinters(encs); err != nil {
+		t.Fatalf("load hinters: %v", err)
+	}
+
+	cmd := TokenVectorsVerifyCommand{BaseCommand: BaseCommand{Encoder: jenc, Encoders: encs}}
+
+	expected, err := TokenVectors()
+	if err != nil {
+		t.Fatalf("token vectors: %v", err)
+	}
+
+	if len(u.Vectors) != len(expected) {
+		t.Fatalf("number of vectors not matched; expected=%d vectors=%d", len(expected), len(u.Vectors))
+	}
+
+	for i := range expected {
+		t.Run(expected[i].Name, func(t *testing.T) {
+			if err := cmd.verify(expected[i], u.Vectors[i]); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,320 @@
+//go:build integration
+
+package cmds
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	cdigest "github.com/imfact-labs/currency-model/digest"
+	mongodbst "github.com/imfact-labs/currency-model/digest/mongodb"
+	"github.com/imfact-labs/currency-model/utils/bsonenc"
+	"github.com/imfact-labs/mitum2/base"
+	isaacdatabase "github.com/imfact-labs/mitum2/isaac/database"
+	"github.com/imfact-labs/mitum2/util/encoder"
+	jsonenc "github.com/imfact-labs/mitum2/util/encoder/json"
+	"github.com/imfact-labs/mitum2/util/logging"
+	"github.com/imfact-labs/mitum2/util/valuehash"
+	"github.com/imfact-labs/token-model/digest"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// testWebhookReceiver is the webhook url; it checks the signature of every
+// request and fails the deliveries while failing is set.
+type testWebhookReceiver struct {
+	sync.Mutex
+	t          *testing.T
+	secret     string
+	failing    bool
+	delivered  []string
+	failed     map[string][]time.Time
+	deliveries map[string]int
+}
+
+func (rc *testWebhookReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	timestamp := r.Header.Get(digest.TokenWebhookTimestampHeader)
+
+	mac := hmac.New(sha256.New, []byte(rc.secret))
+	_, _ = mac.Write([]byte(timestamp + "."))
+	_, _ = mac.Write(body)
+
+	if r.Header.Get(digest.TokenWebhookSignatureHeader) != "sha256="+hex.EncodeToString(mac.Sum(nil)) {
+		rc.t.Errorf("wrong signature, %q", r.Header.Get(digest.TokenWebhookSignatureHeader))
+		w.WriteHeader(http.StatusUnauthorized)
+
+		return
+	}
+
+	var payload digest.TokenWebhookPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		rc.t.Errorf("decode payload: %v", err)
+		w.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	if payload.Delivery != r.Header.Get(digest.TokenWebhookDeliveryHeader) {
+		rc.t.Errorf("delivery header not matched; %q != %q", payload.Delivery, r.Header.Get(digest.TokenWebhookDeliveryHeader))
+	}
+
+	rc.Lock()
+	defer rc.Unlock()
+
+	if rc.failing {
+		rc.failed[payload.Delivery] = append(rc.failed[payload.Delivery], time.Now())
+		w.WriteHeader(http.StatusServiceUnavailable)
+
+		return
+	}
+
+	rc.delivered = append(rc.delivered, payload.Delivery)
+	rc.deliveries[payload.Delivery]++
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (rc *testWebhookReceiver) setFailing(failing bool) {
+	rc.Lock()
+	defer rc.Unlock()
+
+	rc.failing = failing
+}
+
+func (rc *testWebhookReceiver) failedCount(delivery string) int {
+	rc.Lock()
+	defer rc.Unlock()
+
+	return len(rc.failed[delivery])
+}
+
+func (rc *testWebhookReceiver) deliveredCount() int {
+	rc.Lock()
+	defer rc.Unlock()
+
+	return len(rc.delivered)
+}
+
+// TestTokenWebhook delivers the token events in the digest database of
+// fakeMongo to the receiver, and checks the failed delivery is retried with
+// backoff and the cursor in the digest database makes the restarted webhook
+// deliver the rest without dropping or duplicating the events.
+func TestTokenWebhook(t *testing.T) {
+	fm := newFakeMongo(t)
+	defer fm.Close()
+
+	st := newTestTokenWebhookDatabase(t, fm)
+	defer func() {
+		_ = st.Close()
+	}()
+
+	contract := "0x18EB4051f96B01c91D50480D738371FA27ab6Ef5fca"
+	deliveryIDs := map[base.Height]string{}
+
+	addEvent := func(height base.Height) {
+		fact := valuehash.RandomSHA256().String()
+		deliveryIDs[height] = contract + ":" + fact
+
+		if err := st.MongoClient().Bulk(context.Background(), digest.DefaultColNameTokenEvent, []mongo.WriteModel{
+			mongo.NewInsertOneModel().SetDocument(bson.D{
+				{Key: "contract", Value: contract},
+				{Key: "height", Value: height},
+				{Key: "operations", Value: bson.A{fact}},
+				{Key: "address", Value: "holder"},
+				{Key: "amount", Value: "10"},
+				{Key: "delta", Value: "10"},
+			}),
+		}, false); err != nil {
+			t.Fatalf("insert token event: %v", err)
+		}
+	}
+
+	rc := &testWebhookReceiver{
+		t:          t,
+		secret:     "showme",
+		failed:     map[string][]time.Time{},
+		deliveries: map[string]int{},
+	}
+
+	srv := httptest.NewServer(rc)
+	defer srv.Close()
+
+	design := digest.TokenWebhookDesign{
+		URLs:       []string{srv.URL},
+		Secret:     rc.secret,
+		Interval:   time.Millisecond * 100,
+		MaxBackoff: time.Second,
+	}
+
+	if err := design.IsValid(nil); err != nil {
+		t.Fatalf("design: %v", err)
+	}
+
+	start := func() *digest.TokenWebhook {
+		wh := digest.NewTokenWebhook(st, design)
+		if err := wh.Start(context.Background()); err != nil {
+			t.Fatalf("start webhook: %v", err)
+		}
+
+		return wh
+	}
+
+	loadCursor := func() base.Height {
+		var cursor struct {
+			Height base.Height `bson:"height"`
+		}
+
+		switch err := st.MongoClient().GetByFilter(
+			digest.DefaultColNameTokenWebhook,
+			bson.D{{Key: "url", Value: srv.URL}},
+			func(res *mongo.SingleResult) error {
+				return res.Decode(&cursor)
+			},
+		); {
+		case err == nil:
+			return cursor.Height
+		case errors.Is(err, mongo.ErrNoDocuments):
+			return base.NilHeight
+		default:
+			t.Fatalf("load cursor: %v", err)
+
+			return base.NilHeight
+		}
+	}
+
+	waitFor := func(name string, f func() bool) {
+		t.Helper()
+
+		deadline := time.Now().Add(time.Second * 30)
+		for !f() {
+			if time.Now().After(deadline) {
+				t.Fatalf("wait %s: timeout", name)
+			}
+
+			time.Sleep(time.Millisecond * 50)
+		}
+	}
+
+	// NOTE the events before the webhook starts are not delivered to the
+	// new url
+	addEvent(1)
+
+	wh := start()
+	waitFor("cursor of new url", func() bool { return loadCursor() == 2 })
+
+	// NOTE the first delivery fails with 5xx and is retried with backoff
+	rc.setFailing(true)
+	addEvent(2)
+	addEvent(3)
+
+	waitFor("retried delivery", func() bool { return rc.failedCount(deliveryIDs[2]) >= 3 })
+
+	rc.Lock()
+	failed := rc.failed[deliveryIDs[2]]
+	for i := 1; i < len(failed); i++ {
+		if d := failed[i].Sub(failed[i-1]); d < time.Millisecond*900 {
+			t.Errorf("retried without backoff; %v", d)
+		}
+	}
+	rc.Unlock()
+
+	rc.setFailing(false)
+	waitFor("delivery", func() bool { return rc.deliveredCount() >= 2 })
+	waitFor("cursor after delivery", func() bool { return loadCursor() == 4 })
+
+	if err := wh.Stop(); err != nil {
+		t.Fatalf("stop webhook: %v", err)
+	}
+
+	// NOTE the webhook stops while the delivery of 4 is failing; after
+	// restart, it continues from the cursor in the digest database.
+	rc.setFailing(true)
+	addEvent(4)
+
+	wh = start()
+	waitFor("failed delivery before restart", func() bool { return rc.failedCount(deliveryIDs[4]) >= 1 })
+
+	if err := wh.Stop(); err != nil {
+		t.Fatalf("stop webhook: %v", err)
+	}
+
+	addEvent(5)
+	rc.setFailing(false)
+
+	wh = start()
+	defer func() {
+		_ = wh.Stop()
+	}()
+
+	waitFor("delivery after restart", func() bool { return rc.deliveredCount() >= 4 })
+
+	// NOTE give the time to deliver again, if it does
+	time.Sleep(design.Interval * 5)
+
+	rc.Lock()
+	delivered := append([]string(nil), rc.delivered...)
+	rc.Unlock()
+
+	expected := []string{deliveryIDs[2], deliveryIDs[3], deliveryIDs[4], deliveryIDs[5]}
+	if len(delivered) != len(expected) {
+		t.Fatalf("deliveries not matched; expected=%v delivered=%v", expected, delivered)
+	}
+
+	for i := range expected {
+		if delivered[i] != expected[i] {
+			t.Fatalf("delivery %d not matched; expected=%q delivered=%q", i, expected[i], delivered[i])
+		}
+	}
+
+	if height := loadCursor(); height != 6 {
+		t.Fatalf("cursor not saved after the last event; height=%v", height)
+	}
+}
+
+func newTestTokenWebhookDatabase(t *testing.T, fm *fakeMongo) *cdigest.Database {
+	t.Helper()
+
+	jenc := jsonenc.NewEncoder()
+	encs := encoder.NewEncoders(jenc, jenc)
+
+	if err := encs.AddEncoder(bsonenc.NewEncoder()); err != nil {
+		t.Fatalf("add bson encoder: %v", err)
+	}
+
+	mst, err := mongodbst.NewDatabaseFromURI(fm.URI("webhook"), encs)
+	if err != nil {
+		t.Fatalf("digest database: %v", err)
+	}
+
+	if err := mst.Initialize(); err != nil {
+		t.Fatalf("initialize digest database: %v", err)
+	}
+
+	st, err := cdigest.NewDatabase(&isaacdatabase.Center{Logging: logging.NewLogging(nil)}, mst)
+	if err != nil {
+		t.Fatalf("digest database: %v", err)
+	}
+
+	if err := st.Initialize(digest.DefaultIndexes); err != nil {
+		t.Fatalf("initialize digest database: %v", err)
+	}
+
+	return st
+}
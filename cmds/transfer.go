@@ -8,11 +8,13 @@ import (
 	"github.com/imfact-labs/mitum2/util"
 	"github.com/imfact-labs/token-model/operation/token"
 	"github.com/imfact-labs/token-model/utils"
+	"github.com/pkg/errors"
 )
 
 type TransferCommand struct {
 	OperationCommand
-	ReceiverAmount AddressTokenAmountFlag `arg:"" name:"receiver-amount" help:"receiver token amount (ex: \"<address>,<amount>\") separator @" required:"true"`
+	ItemsFileFlag
+	ReceiverAmount AddressTokenAmountFlag `arg:"" name:"receiver-amount" help:"receiver token amount (ex: \"<address>,<amount>\") separator @" optional:""`
 }
 
 func (cmd *TransferCommand) Run(pctx context.Context) error { // nolint:dupl
@@ -24,6 +26,17 @@ func (cmd *TransferCommand) Run(pctx context.Context) error { // nolint:dupl
 		return err
 	}
 
+	if cmd.IsSet() {
+		ops, err := cmd.createOperations()
+		if err != nil {
+			return err
+		}
+
+		ccmds.PrettyPrint(cmd.Out, ops)
+
+		return nil
+	}
+
 	op, err := cmd.createOperation()
 	if err != nil {
 		return err
@@ -39,6 +52,13 @@ func (cmd *TransferCommand) parseFlags() error {
 		return err
 	}
 
+	switch {
+	case cmd.IsSet() && len(cmd.ReceiverAmount.Address()) > 0:
+		return errors.Errorf("receiver-amount and --items-file can not be used together")
+	case !cmd.IsSet() && len(cmd.ReceiverAmount.Address()) < 1:
+		return errors.Errorf("expected receiver-amount or --items-file")
+	}
+
 	return nil
 }
 
@@ -46,7 +66,12 @@ func (cmd *TransferCommand) createOperation() (base.Operation, error) { // nolin
 	e := util.StringError(utils.ErrStringCreate("transfer operation"))
 	var items []token.TransferItem
 	for i := range cmd.ReceiverAmount.Address() {
-		item := token.NewTransferItem(cmd.contract, cmd.ReceiverAmount.Address()[i], cmd.ReceiverAmount.Amount()[i])
+		amount, err := cmd.amount(cmd.ReceiverAmount.Amount()[i])
+		if err != nil {
+			return nil, e.Wrap(err)
+		}
+
+		item := token.NewTransferItem(cmd.contract, cmd.ReceiverAmount.Address()[i], amount)
 		if err := item.IsValid(nil); err != nil {
 			return nil, err
 		}
@@ -58,9 +83,56 @@ func (cmd *TransferCommand) createOperation() (base.Operation, error) { // nolin
 	)
 
 	op := token.NewTransfer(fact)
-	if err := op.Sign(cmd.Privatekey, cmd.NetworkID.NetworkID()); err != nil {
+	if err := cmd.sign(&op); err != nil {
 		return nil, e.Wrap(err)
 	}
 
 	return op, nil
 }
+
+// createOperations creates transfer operations from the items file; the items
+// are split by MaxTransferItems.
+func (cmd *TransferCommand) createOperations() ([]base.Operation, error) {
+	e := util.StringError(utils.ErrStringCreate("transfer operation"))
+
+	rows, err := cmd.load("receiver", "amount")
+	if err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	items := make([]token.TransferItem, len(rows))
+	for i := range rows {
+		receiver, err := rows[i].address("receiver")
+		if err != nil {
+			return nil, e.Wrap(err)
+		}
+
+		amount, err := rows[i].amount("amount", cmd.amount)
+		if err != nil {
+			return nil, e.Wrap(err)
+		}
+
+		item := token.NewTransferItem(cmd.contract, receiver, amount)
+		if err := item.IsValid(nil); err != nil {
+			return nil, e.Wrap(errors.WithMessagef(err, "row %d", rows[i].line))
+		}
+		items[i] = item
+	}
+
+	chunks := splitItems(items, token.MaxTransferItems)
+	ops := make([]base.Operation, len(chunks))
+
+	for i := range chunks {
+		fact := token.NewTransferFact(
+			[]byte(cmd.Token), cmd.sender, chunks[i], cmd.Currency.CID,
+		)
+
+		op := token.NewTransfer(fact)
+		if err := cmd.sign(&op); err != nil {
+			return nil, e.Wrap(err)
+		}
+		ops[i] = op
+	}
+
+	return ops, nil
+}
@@ -13,8 +13,9 @@ import (
 
 type TransferFromCommand struct {
 	OperationCommand
-	Receiver     ccmds.AddressFlag      `arg:"" name:"receiver" help:"token receiver" required:"true"`
-	TargetAmount AddressTokenAmountFlag `arg:"" name:"target" help:"target approving" required:"true"`
+	ItemsFileFlag
+	Receiver     ccmds.AddressFlag      `arg:"" name:"receiver" help:"token receiver" optional:""`
+	TargetAmount AddressTokenAmountFlag `arg:"" name:"target" help:"target approving" optional:""`
 	receiver     base.Address
 }
 
@@ -27,6 +28,17 @@ func (cmd *TransferFromCommand) Run(pctx context.Context) error { // nolint:dupl
 		return err
 	}
 
+	if cmd.IsSet() {
+		ops, err := cmd.createOperations()
+		if err != nil {
+			return err
+		}
+
+		ccmds.PrettyPrint(cmd.Out, ops)
+
+		return nil
+	}
+
 	op, err := cmd.createOperation()
 	if err != nil {
 		return err
@@ -42,6 +54,15 @@ func (cmd *TransferFromCommand) parseFlags() error {
 		return err
 	}
 
+	switch {
+	case cmd.IsSet() && (len(cmd.Receiver.String()) > 0 || len(cmd.TargetAmount.Address()) > 0):
+		return errors.Errorf("receiver and target can not be used with --items-file")
+	case cmd.IsSet():
+		return nil
+	case len(cmd.TargetAmount.Address()) < 1:
+		return errors.Errorf("expected receiver and target, or --items-file")
+	}
+
 	receiver, err := cmd.Receiver.Encode(cmd.Encoders.JSON())
 	if err != nil {
 		return errors.Wrapf(err, "invalid receiver format, %q", cmd.Receiver.String())
@@ -55,7 +76,12 @@ func (cmd *TransferFromCommand) createOperation() (base.Operation, error) { // n
 	e := util.StringError(utils.ErrStringCreate("transfer-from operation"))
 	var items []token.TransferFromItem
 	for i := range cmd.TargetAmount.Address() {
-		item := token.NewTransferFromItem(cmd.contract, cmd.receiver, cmd.TargetAmount.Address()[i], cmd.TargetAmount.Amount()[i])
+		amount, err := cmd.amount(cmd.TargetAmount.Amount()[i])
+		if err != nil {
+			return nil, e.Wrap(err)
+		}
+
+		item := token.NewTransferFromItem(cmd.contract, cmd.receiver, cmd.TargetAmount.Address()[i], amount)
 		if err := item.IsValid(nil); err != nil {
 			return nil, err
 		}
@@ -67,9 +93,61 @@ func (cmd *TransferFromCommand) createOperation() (base.Operation, error) { // n
 	)
 
 	op := token.NewTransferFrom(fact)
-	if err := op.Sign(cmd.Privatekey, cmd.NetworkID.NetworkID()); err != nil {
+	if err := cmd.sign(&op); err != nil {
 		return nil, e.Wrap(err)
 	}
 
 	return op, nil
 }
+
+// createOperations creates transfer-from operations from the items file; the
+// items are split by MaxTransferFromItems.
+func (cmd *TransferFromCommand) createOperations() ([]base.Operation, error) {
+	e := util.StringError(utils.ErrStringCreate("transfer-from operation"))
+
+	rows, err := cmd.load("receiver", "target", "amount")
+	if err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	items := make([]token.TransferFromItem, len(rows))
+	for i := range rows {
+		receiver, err := rows[i].address("receiver")
+		if err != nil {
+			return nil, e.Wrap(err)
+		}
+
+		target, err := rows[i].address("target")
+		if err != nil {
+			return nil, e.Wrap(err)
+		}
+
+		amount, err := rows[i].amount("amount", cmd.amount)
+		if err != nil {
+			return nil, e.Wrap(err)
+		}
+
+		item := token.NewTransferFromItem(cmd.contract, receiver, target, amount)
+		if err := item.IsValid(nil); err != nil {
+			return nil, e.Wrap(errors.WithMessagef(err, "row %d", rows[i].line))
+		}
+		items[i] = item
+	}
+
+	chunks := splitItems(items, token.MaxTransferFromItems)
+	ops := make([]base.Operation, len(chunks))
+
+	for i := range chunks {
+		fact := token.NewTransferFromFact(
+			[]byte(cmd.Token), cmd.sender, chunks[i], cmd.Currency.CID,
+		)
+
+		op := token.NewTransferFrom(fact)
+		if err := cmd.sign(&op); err != nil {
+			return nil, e.Wrap(err)
+		}
+		ops[i] = op
+	}
+
+	return ops, nil
+}
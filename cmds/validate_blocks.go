@@ -23,6 +23,7 @@ type ValidateBlocksCommand struct { //nolint:govet //...
 	launch.DesignFlag
 	launch.PrivatekeyFlags
 	HeightRange     launch.RangeFlag `name:"range" help:"<from>-<to>" default:""`
+	TokenAudit      bool             `name:"token-audit" help:"check total supply, balances and allowances of token"`
 	log             *zerolog.Logger
 	launch.DevFlags `embed:"" prefix:"dev."`
 	fromHeight      base.Height
@@ -64,6 +65,7 @@ func (cmd *ValidateBlocksCommand) Run(pctx context.Context) error {
 		Interface("dev", cmd.DevFlags).
 		Interface("from_height", cmd.fromHeight).
 		Interface("to_height", cmd.toHeight).
+		Bool("token_audit", cmd.TokenAudit).
 		Msg("flags")
 
 	cmd.log = log.Log()
@@ -185,6 +187,12 @@ func (cmd *ValidateBlocksCommand) pValidateBlocks(pctx context.Context) (context
 		return pctx, e.Wrap(err)
 	}
 
+	if cmd.TokenAudit {
+		if err := cmd.auditTokens(readers, last); err != nil {
+			return pctx, e.Wrap(err)
+		}
+	}
+
 	return pctx, nil
 }
 
@@ -0,0 +1,255 @@
+package cmds
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/isaac"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/token-model/state"
+	"github.com/imfact-labs/token-model/types"
+	"github.com/pkg/errors"
+)
+
+// TokenAuditViolation is the broken token invariant of contract at height;
+// Facts are the hashes of operation facts, which changed the token states of
+// contract at height.
+type TokenAuditViolation struct {
+	Height   base.Height `json:"height"`
+	Contract string      `json:"contract"`
+	Reason   string      `json:"reason"`
+	Facts    []util.Hash `json:"facts"`
+}
+
+// tokenAuditor keeps the last token designs and balances by contract; the
+// sum of balances is updated by the changed balances, so the contracts, not
+// changed in block, are not checked again.
+type tokenAuditor struct {
+	designs  map[string]types.Design
+	balances map[string]map[string]common.Big
+	sums     map[string]common.Big
+}
+
+func newTokenAuditor() *tokenAuditor {
+	return &tokenAuditor{
+		designs:  map[string]types.Design{},
+		balances: map[string]map[string]common.Big{},
+		sums:     map[string]common.Big{},
+	}
+}
+
+// apply updates the token states of height and checks the contracts, which
+// have the changed states,
+//   - sum of balances should be same with total supply of design
+//   - balance and allowance should not be negative
+//   - owner of allowances should have balance state
+func (a *tokenAuditor) apply(height base.Height, sts []base.State) ([]TokenAuditViolation, error) {
+	facts := map[string][]util.Hash{}
+	reasons := map[string][]string{}
+
+	for i := range sts {
+		st := sts[i]
+
+		contract, address, err := parseTokenStateKey(st.Key())
+		switch {
+		case err != nil:
+			return nil, err
+		case len(contract) < 1:
+			continue
+		}
+
+		facts[contract] = appendTokenAuditFacts(facts[contract], st.Operations())
+
+		if len(address) < 1 {
+			design, err := state.StateDesignValue(st)
+			if err != nil {
+				return nil, errors.WithMessagef(err, "design of %v", contract)
+			}
+
+			a.designs[contract] = *design
+
+			continue
+		}
+
+		amount, err := state.StateTokenBalanceValue(st)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "balance of %v in %v", address, contract)
+		}
+
+		if !amount.OverNil() {
+			reasons[contract] = append(reasons[contract], fmt.Sprintf("negative balance, %v of %v", amount, address))
+		}
+
+		a.setBalance(contract, address, amount)
+	}
+
+	contracts := make([]string, 0, len(facts))
+	for contract := range facts {
+		contracts = append(contracts, contract)
+	}
+
+	sort.Strings(contracts)
+
+	var violations []TokenAuditViolation
+
+	for i := range contracts {
+		contract := contracts[i]
+
+		rs := reasons[contract]
+		rs = append(rs, a.check(contract)...)
+
+		for j := range rs {
+			violations = append(violations, TokenAuditViolation{
+				Height:   height,
+				Contract: contract,
+				Reason:   rs[j],
+				Facts:    facts[contract],
+			})
+		}
+	}
+
+	return violations, nil
+}
+
+func appendTokenAuditFacts(facts, ops []util.Hash) []util.Hash {
+	for i := range ops {
+		if util.CountFilteredSlice(facts, func(h util.Hash) bool {
+			return h.Equal(ops[i])
+		}) < 1 {
+			facts = append(facts, ops[i])
+		}
+	}
+
+	return facts
+}
+
+func (a *tokenAuditor) setBalance(contract, address string, amount common.Big) {
+	bs, found := a.balances[contract]
+	if !found {
+		bs = map[string]common.Big{}
+		a.balances[contract] = bs
+	}
+
+	sum, found := a.sums[contract]
+	if !found {
+		sum = common.ZeroBig
+	}
+
+	if prev, found := bs[address]; found {
+		sum = sum.Sub(prev)
+	}
+
+	bs[address] = amount
+	a.sums[contract] = sum.Add(amount)
+}
+
+func (a *tokenAuditor) check(contract string) []string {
+	design, found := a.designs[contract]
+	if !found {
+		return []string{"token balances found without design"}
+	}
+
+	var reasons []string
+
+	sum, found := a.sums[contract]
+	if !found {
+		sum = common.ZeroBig
+	}
+
+	if ts := design.Policy().TotalSupply(); !ts.Equal(sum) {
+		reasons = append(reasons, fmt.Sprintf("sum of balances, %v not matched with total supply, %v", sum, ts))
+	}
+
+	apl := design.Policy().ApproveList()
+	for i := range apl {
+		owner := apl[i].Account().String()
+
+		if _, found := a.balances[contract][owner]; !found {
+			reasons = append(reasons, fmt.Sprintf("balance of allowance owner, %v not found", owner))
+		}
+
+		infos := apl[i].Approved()
+		for j := range infos {
+			if !infos[j].Amount().OverNil() {
+				reasons = append(reasons, fmt.Sprintf("negative allowance, %v of %v to %v",
+					infos[j].Amount(), owner, infos[j].Account()))
+			}
+		}
+	}
+
+	return reasons
+}
+
+// legacyDesigns returns the contracts, whose design has the legacy approve
+// list; they are migrated when the policy is changed by operation from the
+// canonical approve list height.
+func (a *tokenAuditor) legacyDesigns() []string {
+	var contracts []string
+
+	for contract := range a.designs {
+		if a.designs[contract].Policy().IsLegacy() {
+			contracts = append(contracts, contract)
+		}
+	}
+
+	sort.Strings(contracts)
+
+	return contracts
+}
+
+// auditTokens replays the token states from genesis to last and reports the
+// violations from the from height; the states under the from height are
+// applied, but not reported.
+func (cmd *ValidateBlocksCommand) auditTokens(readers *isaac.BlockItemReaders, last base.Height) error {
+	a := newTokenAuditor()
+
+	var count int
+
+	for h := base.GenesisHeight; h <= last; h++ {
+		var sts []base.State
+
+		if _, _, _, err := isaac.BlockItemReadersDecodeItems[base.State](
+			readers.Item,
+			h,
+			base.BlockItemStates,
+			func(_, _ uint64, st base.State) error {
+				if state.IsStateDesignKey(st.Key()) || state.IsStateTokenBalanceKey(st.Key()) {
+					sts = append(sts, st)
+				}
+
+				return nil
+			},
+			nil,
+		); err != nil {
+			return errors.WithMessagef(err, "states; height=%d", h)
+		}
+
+		violations, err := a.apply(h, sts)
+		switch {
+		case err != nil:
+			return errors.WithMessagef(err, "height=%d", h)
+		case h < cmd.fromHeight:
+			continue
+		}
+
+		for i := range violations {
+			cmd.log.Error().Interface("violation", violations[i]).Msg("token audit failed")
+		}
+
+		count += len(violations)
+	}
+
+	if contracts := a.legacyDesigns(); len(contracts) > 0 {
+		cmd.log.Info().Strs("contracts", contracts).Msg("designs with legacy approve list found")
+	}
+
+	if count > 0 {
+		return errors.Errorf("token audit failed; violations=%d", count)
+	}
+
+	cmd.log.Debug().Interface("from_height", cmd.fromHeight).Interface("to_height", last).Msg("token audited")
+
+	return nil
+}
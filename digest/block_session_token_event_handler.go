@@ -0,0 +1,105 @@
+package digest
+
+import (
+	cdigest "github.com/imfact-labs/currency-model/digest"
+	cstate "github.com/imfact-labs/currency-model/state"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/token-model/state"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// PrepareTokenEvent records the token states changed by operations, so the
+// event stream can replay them by height. Balance rows keep the change from
+// the previous block, which TokenEvents splits by operation; design rows keep
+// the total supply.
+func PrepareTokenEvent(bs *cdigest.BlockSession, st base.State) (string, []mongo.WriteModel, error) {
+	if len(st.Operations()) < 1 {
+		return "", nil, nil
+	}
+
+	switch {
+	case state.IsStateDesignKey(st.Key()):
+		j, err := handleTokenDesignEventState(st)
+		if err != nil {
+			return "", nil, err
+		}
+
+		return DefaultColNameTokenEvent, j, nil
+	case state.IsStateTokenBalanceKey(st.Key()):
+		j, err := handleTokenBalanceEventState(bs, st)
+		if err != nil {
+			return "", nil, err
+		}
+
+		return DefaultColNameTokenEvent, j, nil
+	}
+
+	return "", nil, nil
+}
+
+func handleTokenDesignEventState(st base.State) ([]mongo.WriteModel, error) {
+	stateKeys, err := cstate.ParseStateKey(st.Key(), state.TokenPrefix, 3)
+	if err != nil {
+		return nil, err
+	}
+
+	design, err := state.StateDesignValue(st)
+	if err != nil {
+		return nil, err
+	}
+
+	return []mongo.WriteModel{
+		mongo.NewInsertOneModel().SetDocument(bson.D{
+			{Key: "contract", Value: stateKeys[1]},
+			{Key: "height", Value: st.Height()},
+			{Key: "operations", Value: stateOperations(st)},
+			{Key: "total_supply", Value: design.Policy().TotalSupply().String()},
+		}),
+	}, nil
+}
+
+func handleTokenBalanceEventState(bs *cdigest.BlockSession, st base.State) ([]mongo.WriteModel, error) {
+	stateKeys, err := cstate.ParseStateKey(st.Key(), state.TokenPrefix, 4)
+	if err != nil {
+		return nil, err
+	}
+	contract, address := stateKeys[1], stateKeys[2]
+
+	amount, err := state.StateTokenBalanceValue(st)
+	if err != nil {
+		return nil, err
+	}
+
+	// NOTE the previous balance is read from history; the latest collection
+	// may already have this block when the block is digested again.
+	delta := amount
+	if st.Height() > base.GenesisHeight {
+		switch previous, err := TokenBalance(bs.Database(), contract, address, st.Height()-1); {
+		case err != nil:
+			return nil, err
+		case previous != nil && previous.Int != nil:
+			delta = amount.Sub(*previous)
+		}
+	}
+
+	return []mongo.WriteModel{
+		mongo.NewInsertOneModel().SetDocument(bson.D{
+			{Key: "contract", Value: contract},
+			{Key: "height", Value: st.Height()},
+			{Key: "operations", Value: stateOperations(st)},
+			{Key: "address", Value: address},
+			{Key: "amount", Value: amount.String()},
+			{Key: "delta", Value: delta.String()},
+		}),
+	}, nil
+}
+
+func stateOperations(st base.State) []string {
+	ops := make([]string, len(st.Operations()))
+	for i := range st.Operations() {
+		ops[i] = st.Operations()[i].String()
+	}
+
+	return ops
+}
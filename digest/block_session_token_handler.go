@@ -1,9 +1,17 @@
 package digest
 
 import (
+	"context"
+	"sort"
+	"sync"
+
 	cdigest "github.com/imfact-labs/currency-model/digest"
+	cstate "github.com/imfact-labs/currency-model/state"
 	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
 	"github.com/imfact-labs/token-model/state"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 )
 
@@ -28,6 +36,138 @@ func PrepareToken(bs *cdigest.BlockSession, st base.State) (string, []mongo.Writ
 	return "", nil, nil
 }
 
+// TokenLatestModels collects the upserts of the latest token design and
+// balance states and the symbol registry entries by height, so hot queries do
+// not need to sort the history collections by height. The block session
+// commits the collections only with inserted documents, so the upserts are
+// written by Write after the block is committed; the latest collections never
+// have the states of the block, which is not in the history collections.
+type TokenLatestModels struct {
+	sync.Mutex
+	blocks map[base.Height]tokenLatestBlock
+}
+
+type tokenLatestBlock struct {
+	bs     *cdigest.BlockSession
+	models map[string][]mongo.WriteModel
+}
+
+func NewTokenLatestModels() *TokenLatestModels {
+	return &TokenLatestModels{
+		blocks: map[base.Height]tokenLatestBlock{},
+	}
+}
+
+// PrepareTokenLatest collects the upserts of the latest token design and
+// balance states; each upsert is guarded by height, so digesting the block
+// again does not overwrite the newer states.
+func (l *TokenLatestModels) PrepareTokenLatest(bs *cdigest.BlockSession, st base.State) (string, []mongo.WriteModel, error) {
+	var col string
+	var models []mongo.WriteModel
+	var err error
+
+	switch {
+	case state.IsStateDesignKey(st.Key()):
+		col = DefaultColNameTokenLatest
+		models, err = handleTokenLatestState(bs, st)
+	case state.IsStateTokenBalanceKey(st.Key()):
+		col = DefaultColNameTokenBalanceLatest
+		models, err = handleTokenBalanceLatestState(bs, st)
+	default:
+		return "", nil, nil
+	}
+
+	if err != nil {
+		return "", nil, err
+	}
+
+	l.add(bs, col, models)
+
+	return "", nil, nil
+}
+
+// PrepareTokenSymbol collects the upserts of the symbol registry entries by
+// symbol.
+func (l *TokenLatestModels) PrepareTokenSymbol(bs *cdigest.BlockSession, st base.State) (string, []mongo.WriteModel, error) {
+	if !state.IsStateSymbolKey(st.Key()) {
+		return "", nil, nil
+	}
+
+	doc, err := NewTokenSymbolDoc(st, bs.Database().Encoder())
+	if err != nil {
+		return "", nil, err
+	}
+
+	l.add(bs, DefaultColNameTokenSymbol, []mongo.WriteModel{
+		newLatestModel(bson.D{{Key: "symbol", Value: doc.symbol}}, st.Height(), doc),
+	})
+
+	return "", nil, nil
+}
+
+// add keeps the models of the block session. When the block is prepared
+// again after the failed commit, the models of the previous session are
+// replaced.
+func (l *TokenLatestModels) add(bs *cdigest.BlockSession, col string, models []mongo.WriteModel) {
+	l.Lock()
+	defer l.Unlock()
+
+	height := bs.BlockMap().Manifest().Height()
+
+	b, found := l.blocks[height]
+	if !found || b.bs != bs {
+		b = tokenLatestBlock{bs: bs, models: map[string][]mongo.WriteModel{}}
+		l.blocks[height] = b
+	}
+
+	b.models[col] = append(b.models[col], models...)
+}
+
+// Write writes the collected models of the committed blocks. The models of
+// the blocks, which are not yet committed, are kept until the next Write.
+func (l *TokenLatestModels) Write(ctx context.Context, db *cdigest.Database) error {
+	l.Lock()
+	defer l.Unlock()
+
+	heights := make([]base.Height, 0, len(l.blocks))
+	for height := range l.blocks {
+		heights = append(heights, height)
+	}
+
+	sort.Slice(heights, func(i, j int) bool {
+		return heights[i] < heights[j]
+	})
+
+	for i := range heights {
+		switch m, _, _, _, _, _, err := db.ManifestByHeight(heights[i]); {
+		case errors.Is(err, util.ErrNotFound):
+			continue
+		case err != nil:
+			return err
+		case m == nil:
+			continue
+		}
+
+		b := l.blocks[heights[i]]
+
+		cols := make([]string, 0, len(b.models))
+		for col := range b.models {
+			cols = append(cols, col)
+		}
+
+		sort.Strings(cols)
+
+		for j := range cols {
+			if err := writeLatestModels(ctx, db, cols[j], b.models[cols[j]]); err != nil {
+				return err
+			}
+		}
+
+		delete(l.blocks, heights[i])
+	}
+
+	return nil
+}
 func handleTokenState(bs *cdigest.BlockSession, st base.State) ([]mongo.WriteModel, error) {
 	if tokenDoc, err := NewTokenDoc(st, bs.Database().Encoder()); err != nil {
 		return nil, err
@@ -47,3 +187,82 @@ func handleTokenBalanceState(bs *cdigest.BlockSession, st base.State) ([]mongo.W
 		}, nil
 	}
 }
+
+func handleTokenLatestState(bs *cdigest.BlockSession, st base.State) ([]mongo.WriteModel, error) {
+	tokenDoc, err := NewTokenDoc(st, bs.Database().Encoder())
+	if err != nil {
+		return nil, err
+	}
+
+	stateKeys, err := cstate.ParseStateKey(st.Key(), state.TokenPrefix, 3)
+	if err != nil {
+		return nil, err
+	}
+
+	return []mongo.WriteModel{
+		newLatestModel(bson.D{{Key: "contract", Value: stateKeys[1]}}, st.Height(), tokenDoc),
+	}, nil
+}
+
+func handleTokenBalanceLatestState(bs *cdigest.BlockSession, st base.State) ([]mongo.WriteModel, error) {
+	tokenBalanceDoc, err := NewTokenBalanceDoc(st, bs.Database().Encoder())
+	if err != nil {
+		return nil, err
+	}
+
+	stateKeys, err := cstate.ParseStateKey(st.Key(), state.TokenPrefix, 4)
+	if err != nil {
+		return nil, err
+	}
+
+	return []mongo.WriteModel{
+		newLatestModel(bson.D{
+			{Key: "contract", Value: stateKeys[1]},
+			{Key: "address", Value: stateKeys[2]},
+		}, st.Height(), tokenBalanceDoc),
+	}, nil
+}
+
+// newLatestModel replaces the document of the unique keys only when the stored
+// one is not newer than height.
+func newLatestModel(keys bson.D, height base.Height, doc interface{}) mongo.WriteModel {
+	filter := make(bson.D, len(keys), len(keys)+1)
+	copy(filter, keys)
+	filter = append(filter, bson.E{Key: "height", Value: bson.D{{Key: "$lte", Value: height}}})
+
+	return mongo.NewReplaceOneModel().
+		SetFilter(filter).
+		SetReplacement(doc).
+		SetUpsert(true)
+}
+
+// writeLatestModels writes the models of newLatestModel. When the stored
+// document is newer, the upsert fails by the unique index of keys; it is
+// ignored.
+func writeLatestModels(ctx context.Context, db *cdigest.Database, col string, models []mongo.WriteModel) error {
+	if len(models) < 1 {
+		return nil
+	}
+
+	switch err := db.MongoClient().Bulk(ctx, col, models, false); {
+	case err == nil, isDuplicateKeyOnly(err):
+		return nil
+	default:
+		return errors.WithMessagef(err, "write %s", col)
+	}
+}
+
+func isDuplicateKeyOnly(err error) bool {
+	var bwe mongo.BulkWriteException
+	if !errors.As(err, &bwe) || bwe.WriteConcernError != nil || len(bwe.WriteErrors) < 1 {
+		return false
+	}
+
+	for i := range bwe.WriteErrors {
+		if bwe.WriteErrors[i].Code != 11000 { //nolint:gomnd //...
+			return false
+		}
+	}
+
+	return true
+}
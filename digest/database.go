@@ -1,31 +1,59 @@
 package digest
 
 import (
+	"context"
+
 	"github.com/imfact-labs/currency-model/common"
 	cdigest "github.com/imfact-labs/currency-model/digest"
+	mongodbst "github.com/imfact-labs/currency-model/digest/mongodb"
 	"github.com/imfact-labs/currency-model/digest/util"
 	"github.com/imfact-labs/mitum2/base"
 	utilm "github.com/imfact-labs/mitum2/util"
 	"github.com/imfact-labs/token-model/state"
 	"github.com/imfact-labs/token-model/types"
 	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
 )
 
 var (
-	DefaultColNameToken        = "digest_token"
-	DefaultColNameTokenBalance = "digest_token_bl"
+	DefaultColNameToken              = "digest_token"
+	DefaultColNameTokenBalance       = "digest_token_bl"
+	DefaultColNameTokenLatest        = "digest_token_latest"
+	DefaultColNameTokenBalanceLatest = "digest_token_bl_latest"
+	DefaultColNameTokenSymbol        = "digest_token_symbol"
 )
 
-func Token(st *cdigest.Database, contract string) (*types.Design, error) {
+var maxLimit int64 = 50
+
+// NormalizeLimit returns the limit applied by the queries; the limit out of
+// range is clamped to maxLimit, so the queries are never unlimited.
+func NormalizeLimit(limit int64) int64 {
+	switch {
+	case limit < 1, limit > maxLimit:
+		return maxLimit
+	default:
+		return limit
+	}
+}
+
+type TokenSupply struct {
+	Height      base.Height `json:"height"`
+	TotalSupply common.Big  `json:"total_supply"`
+}
+
+// Token returns the token design of contract. If height is over base.NilHeight,
+// the design as of that height is returned.
+func Token(st *cdigest.Database, contract string, height base.Height) (*types.Design, error) {
 	filter := util.NewBSONFilter("contract", contract)
+	col, opt := latestOrHistory(DefaultColNameTokenLatest, DefaultColNameToken, filter, height)
 
 	var design *types.Design
 	var sta base.State
 	var err error
 	if err := st.MongoClient().GetByFilter(
-		DefaultColNameToken,
+		col,
 		filter.D(),
 		func(res *mongo.SingleResult) error {
 			sta, err = cdigest.LoadState(res.Decode, st.Encoders())
@@ -40,7 +68,7 @@ func Token(st *cdigest.Database, contract string) (*types.Design, error) {
 
 			return nil
 		},
-		options.FindOne().SetSort(util.NewBSONFilter("height", -1).D()),
+		opt,
 	); err != nil {
 		return nil, utilm.ErrNotFound.Errorf("token design, contract %s", contract)
 	}
@@ -48,15 +76,61 @@ func Token(st *cdigest.Database, contract string) (*types.Design, error) {
 	return design, nil
 }
 
-func TokenBalance(st *cdigest.Database, contract, account string) (*common.Big, error) {
+type TokenSymbolEntry struct {
+	Symbol   string      `json:"symbol"`
+	Contract string      `json:"contract"`
+	Released bool        `json:"released"`
+	Height   base.Height `json:"height"`
+}
+
+// TokenSymbol returns the symbol registry entry of symbol. Symbols of
+// contracts registered before the registry have no entry.
+func TokenSymbol(st *cdigest.Database, symbol string) (*TokenSymbolEntry, error) {
+	filter := util.NewBSONFilter("symbol", symbol)
+
+	var entry *TokenSymbolEntry
+	if err := st.MongoClient().GetByFilter(
+		DefaultColNameTokenSymbol,
+		filter.D(),
+		func(res *mongo.SingleResult) error {
+			sta, err := cdigest.LoadState(res.Decode, st.Encoders())
+			if err != nil {
+				return err
+			}
+
+			sv, err := state.StateSymbolValue(sta)
+			if err != nil {
+				return err
+			}
+
+			entry = &TokenSymbolEntry{
+				Symbol:   symbol,
+				Contract: sv.Contract().String(),
+				Released: sv.Released(),
+				Height:   sta.Height(),
+			}
+
+			return nil
+		},
+	); err != nil {
+		return nil, utilm.ErrNotFound.Errorf("token symbol, symbol %s", symbol)
+	}
+
+	return entry, nil
+}
+
+// TokenBalance returns the token balance of account. If height is over
+// base.NilHeight, the balance as of that height is returned.
+func TokenBalance(st *cdigest.Database, contract, account string, height base.Height) (*common.Big, error) {
 	filter := util.NewBSONFilter("contract", contract)
 	filter = filter.Add("address", account)
+	col, opt := latestOrHistory(DefaultColNameTokenBalanceLatest, DefaultColNameTokenBalance, filter, height)
 
 	var amount common.Big
 	var sta base.State
 	var err error
 	if err := st.MongoClient().GetByFilter(
-		DefaultColNameTokenBalance,
+		col,
 		filter.D(),
 		func(res *mongo.SingleResult) error {
 			sta, err = cdigest.LoadState(res.Decode, st.Encoders())
@@ -71,7 +145,7 @@ func TokenBalance(st *cdigest.Database, contract, account string) (*common.Big,
 
 			return nil
 		},
-		options.FindOne().SetSort(util.NewBSONFilter("height", -1).D()),
+		opt,
 	); err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			return nil, nil
@@ -81,3 +155,190 @@ func TokenBalance(st *cdigest.Database, contract, account string) (*common.Big,
 
 	return &amount, nil
 }
+
+// TokenSupplyHistory iterates the total supply of contract recorded by each
+// design state, ordered by height.
+func TokenSupplyHistory(
+	st *cdigest.Database,
+	contract string,
+	offset base.Height,
+	reverse bool,
+	limit int64,
+	callback func(TokenSupply) (bool, error),
+) error {
+	filter := util.NewBSONFilter("contract", contract)
+
+	sr := 1
+	op := "$gt"
+	if reverse {
+		sr = -1
+		op = "$lt"
+	}
+
+	if offset > base.NilHeight {
+		filter = filter.AddOp("height", offset, op)
+	}
+
+	opt := options.Find().
+		SetSort(util.NewBSONFilter("height", sr).D()).
+		SetLimit(NormalizeLimit(limit))
+
+	return st.MongoClient().Find(
+		context.Background(),
+		DefaultColNameToken,
+		filter.D(),
+		func(cursor *mongo.Cursor) (bool, error) {
+			sta, err := cdigest.LoadState(cursor.Decode, st.Encoders())
+			if err != nil {
+				return false, err
+			}
+
+			design, err := state.StateDesignValue(sta)
+			if err != nil {
+				return false, err
+			}
+
+			return callback(TokenSupply{
+				Height:      sta.Height(),
+				TotalSupply: design.Policy().TotalSupply(),
+			})
+		},
+		opt,
+	)
+}
+
+var tokenLatestCollections = []struct {
+	from, to string
+	keys     bson.D
+}{
+	{
+		from: DefaultColNameToken,
+		to:   DefaultColNameTokenLatest,
+		keys: bson.D{{Key: "contract", Value: "$contract"}},
+	},
+	{
+		from: DefaultColNameTokenBalance,
+		to:   DefaultColNameTokenBalanceLatest,
+		keys: bson.D{{Key: "contract", Value: "$contract"}, {Key: "address", Value: "$address"}},
+	},
+}
+
+// RebuildTokenLatest rebuilds the latest design and balance collections from
+// the history collections.
+func RebuildTokenLatest(ctx context.Context, st *mongodbst.Database) error {
+	for i := range tokenLatestCollections {
+		c := tokenLatestCollections[i]
+
+		if err := rebuildTokenLatest(ctx, st.Client(), c.from, c.to, c.keys); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// BackfillTokenLatest rebuilds the latest collections, which are behind the
+// history collections, like the digest databases created before the latest
+// collections were added. The rebuilt collections are returned.
+func BackfillTokenLatest(ctx context.Context, client *mongodbst.Client) ([]string, error) {
+	var rebuilt []string
+
+	for i := range tokenLatestCollections {
+		c := tokenLatestCollections[i]
+
+		history, err := topHeight(ctx, client, c.from)
+		if err != nil {
+			return nil, err
+		}
+
+		latest, err := topHeight(ctx, client, c.to)
+		if err != nil {
+			return nil, err
+		}
+
+		if history <= latest {
+			continue
+		}
+
+		if err := rebuildTokenLatest(ctx, client, c.from, c.to, c.keys); err != nil {
+			return nil, err
+		}
+
+		rebuilt = append(rebuilt, c.to)
+	}
+
+	return rebuilt, nil
+}
+
+func rebuildTokenLatest(ctx context.Context, client *mongodbst.Client, from, to string, keys bson.D) error {
+	pipeline := mongo.Pipeline{
+		{{Key: "$sort", Value: bson.D{{Key: "height", Value: -1}}}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: keys},
+			{Key: "doc", Value: bson.D{{Key: "$first", Value: "$$ROOT"}}},
+		}}},
+		{{Key: "$replaceRoot", Value: bson.D{{Key: "newRoot", Value: "$doc"}}}},
+		{{Key: "$out", Value: to}},
+	}
+
+	if err := client.Aggregate(
+		ctx,
+		from,
+		pipeline,
+		func(*mongo.Cursor) (bool, error) {
+			return false, nil
+		},
+		options.Aggregate().SetAllowDiskUse(true),
+	); err != nil {
+		return errors.Wrapf(err, "rebuild %s", to)
+	}
+
+	return nil
+}
+
+// topHeight returns the highest height of documents in col; base.NilHeight
+// for the empty collection.
+func topHeight(ctx context.Context, client *mongodbst.Client, col string) (base.Height, error) {
+	height := base.NilHeight
+
+	if err := client.Find(
+		ctx,
+		col,
+		bson.D{},
+		func(cursor *mongo.Cursor) (bool, error) {
+			var doc struct {
+				Height int64 `bson:"height"`
+			}
+
+			if err := cursor.Decode(&doc); err != nil {
+				return false, err
+			}
+
+			height = base.Height(doc.Height)
+
+			return false, nil
+		},
+		options.Find().
+			SetSort(bson.D{{Key: "height", Value: -1}}).
+			SetProjection(bson.D{{Key: "height", Value: 1}}).
+			SetLimit(1),
+	); err != nil {
+		return base.NilHeight, errors.Wrapf(err, "top height of %s", col)
+	}
+
+	return height, nil
+}
+
+// latestOrHistory returns the latest collection when height is not given,
+// otherwise the history collection filtered by height.
+func latestOrHistory(
+	latest, history string, filter *util.BSONFilter, height base.Height,
+) (string, *options.FindOneOptionsBuilder) {
+	if height <= base.NilHeight {
+		return latest, options.FindOne()
+	}
+
+	_ = filter.AddOp("height", height, "$lte")
+
+	return history, options.FindOne().SetSort(util.NewBSONFilter("height", -1).D())
+}
@@ -0,0 +1,383 @@
+package digest
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/imfact-labs/currency-model/common"
+	cdigest "github.com/imfact-labs/currency-model/digest"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util/hint"
+	"github.com/imfact-labs/mitum2/util/valuehash"
+	"github.com/imfact-labs/token-model/operation/token"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+var DefaultColNameTokenEvent = "digest_token_event"
+
+var tokenEventTypes = map[hint.Type]string{
+	token.RegisterModelFactHint.Type(): "register-model",
+	token.MintFactHint.Type():          "mint",
+	token.BurnFactHint.Type():          "burn",
+	token.ApproveFactHint.Type():       "approve",
+	token.TransferFactHint.Type():      "transfer",
+	token.TransferFromFactHint.Type():  "transfer-from",
+}
+
+type TokenBalanceChange struct {
+	Address string     `json:"address"`
+	Amount  common.Big `json:"amount"`
+	Delta   common.Big `json:"delta"`
+}
+
+// TokenEvent is a token operation with the token balances it changed. Index
+// is the position of the operation in the block, so height, index and contract
+// point an event. Each change has the balance right after the operation and the amount
+// changed by the operation.
+type TokenEvent struct {
+	Contract    string               `json:"contract"`
+	Height      base.Height          `json:"height"`
+	Index       uint64               `json:"index"`
+	Type        string               `json:"type"`
+	FactHash    string               `json:"fact_hash"`
+	InState     bool                 `json:"in_state"`
+	Changes     []TokenBalanceChange `json:"changes,omitempty"`
+	TotalSupply *common.Big          `json:"total_supply,omitempty"`
+	addresses   []string
+	deltas      map[string]common.Big
+}
+
+// Touches returns true if account is changed by the event or appears in the
+// operation fact, like the approved account of approve.
+func (ev TokenEvent) Touches(account string) bool {
+	for i := range ev.Changes {
+		if ev.Changes[i].Address == account {
+			return true
+		}
+	}
+
+	for i := range ev.addresses {
+		if ev.addresses[i] == account {
+			return true
+		}
+	}
+
+	return false
+}
+
+type tokenEventDoc struct {
+	Contract    string      `bson:"contract"`
+	Height      base.Height `bson:"height"`
+	Operations  []string    `bson:"operations"`
+	Address     string      `bson:"address,omitempty"`
+	Amount      string      `bson:"amount,omitempty"`
+	Delta       string      `bson:"delta,omitempty"`
+	TotalSupply string      `bson:"total_supply,omitempty"`
+}
+
+// TokenEvents iterates the events of contracts after offset by height; empty
+// contracts means every contract. The blocks are read until the row count
+// reaches limit, but a block is never cut in the middle. It returns the last
+// height read, or offset if nothing found.
+func TokenEvents(
+	st *cdigest.Database,
+	contracts []string,
+	offset base.Height,
+	limit int64,
+	callback func(TokenEvent) (bool, error),
+) (base.Height, error) {
+	heightFilter := bson.D{{Key: "$gt", Value: offset}}
+
+	switch upper, found, err := tokenEventUpperHeight(st, contracts, offset, limit); {
+	case err != nil:
+		return offset, err
+	case found:
+		heightFilter = append(heightFilter, bson.E{Key: "$lte", Value: upper})
+	}
+
+	var events []*TokenEvent
+	byFact := map[string]*TokenEvent{}
+	last := offset
+
+	if err := st.MongoClient().Find(
+		context.Background(),
+		DefaultColNameTokenEvent,
+		append(tokenEventContractsFilter(contracts), bson.E{Key: "height", Value: heightFilter}),
+		func(cursor *mongo.Cursor) (bool, error) {
+			var doc tokenEventDoc
+			if err := cursor.Decode(&doc); err != nil {
+				return false, err
+			}
+
+			if doc.Height > last {
+				last = doc.Height
+			}
+
+			for i := range doc.Operations {
+				k := doc.Contract + ":" + doc.Operations[i]
+
+				ev, found := byFact[k]
+				if !found {
+					ev = &TokenEvent{Contract: doc.Contract, Height: doc.Height, FactHash: doc.Operations[i]}
+					byFact[k] = ev
+					events = append(events, ev)
+				}
+
+				if err := ev.add(doc); err != nil {
+					return false, err
+				}
+			}
+
+			return true, nil
+		},
+		options.Find().SetSort(bson.D{{Key: "height", Value: 1}, {Key: "_id", Value: 1}}),
+	); err != nil {
+		return offset, err
+	}
+
+	for i := range events {
+		if err := loadTokenEventOperation(st, events[i]); err != nil {
+			return offset, err
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		switch {
+		case events[i].Height != events[j].Height:
+			return events[i].Height < events[j].Height
+		case events[i].Index != events[j].Index:
+			return events[i].Index < events[j].Index
+		default:
+			return events[i].Contract < events[j].Contract
+		}
+	})
+
+	splitTokenEventChanges(events)
+
+	for i := range events {
+		switch keep, err := callback(*events[i]); {
+		case err != nil:
+			return offset, err
+		case !keep:
+			return events[i].Height, nil
+		}
+	}
+
+	return last, nil
+}
+
+// splitTokenEventChanges turns the balance changes of the block into the
+// changes of each operation. The balance rows keep the change of the whole
+// block, so the balance before the block is walked through the operations by
+// their index with the amounts of their facts. The events should be sorted by
+// height and index; the events of unknown facts keep the change of the block.
+func splitTokenEventChanges(events []*TokenEvent) {
+	running := map[string]common.Big{}
+
+	for i := range events {
+		ev := events[i]
+		if ev.deltas == nil {
+			continue
+		}
+
+		for j := range ev.Changes {
+			c := ev.Changes[j]
+			k := fmt.Sprintf("%d:%s:%s", ev.Height, ev.Contract, c.Address)
+
+			amount, found := running[k]
+			if !found {
+				amount = c.Amount.Sub(c.Delta)
+			}
+
+			delta, found := ev.deltas[c.Address]
+			if !found {
+				delta = common.ZeroBig
+			}
+
+			amount = amount.Add(delta)
+			running[k] = amount
+
+			ev.Changes[j] = TokenBalanceChange{Address: c.Address, Amount: amount, Delta: delta}
+		}
+	}
+}
+
+// TokenEventTopHeight returns the highest height of the events of contracts.
+func TokenEventTopHeight(st *cdigest.Database, contracts []string) (base.Height, error) {
+	var doc tokenEventDoc
+
+	switch err := st.MongoClient().GetByFilter(
+		DefaultColNameTokenEvent,
+		tokenEventContractsFilter(contracts),
+		func(res *mongo.SingleResult) error {
+			return res.Decode(&doc)
+		},
+		options.FindOne().SetSort(bson.D{{Key: "height", Value: -1}}),
+	); {
+	case err == nil:
+		return doc.Height, nil
+	case errors.Is(err, mongo.ErrNoDocuments):
+		return base.NilHeight, nil
+	default:
+		return base.NilHeight, err
+	}
+}
+
+func tokenEventUpperHeight(
+	st *cdigest.Database, contracts []string, offset base.Height, limit int64,
+) (base.Height, bool, error) {
+	if limit < 1 {
+		return base.NilHeight, false, nil
+	}
+
+	var doc tokenEventDoc
+
+	switch err := st.MongoClient().GetByFilter(
+		DefaultColNameTokenEvent,
+		append(tokenEventContractsFilter(contracts), bson.E{Key: "height", Value: bson.D{{Key: "$gt", Value: offset}}}),
+		func(res *mongo.SingleResult) error {
+			return res.Decode(&doc)
+		},
+		options.FindOne().
+			SetSort(bson.D{{Key: "height", Value: 1}}).
+			SetSkip(limit-1).
+			SetProjection(bson.D{{Key: "height", Value: 1}}),
+	); {
+	case err == nil:
+		return doc.Height, true, nil
+	case errors.Is(err, mongo.ErrNoDocuments):
+		return base.NilHeight, false, nil
+	default:
+		return base.NilHeight, false, err
+	}
+}
+
+func tokenEventContractsFilter(contracts []string) bson.D {
+	switch len(contracts) {
+	case 0:
+		return bson.D{}
+	case 1:
+		return bson.D{{Key: "contract", Value: contracts[0]}}
+	default:
+		return bson.D{{Key: "contract", Value: bson.D{{Key: "$in", Value: contracts}}}}
+	}
+}
+
+func (ev *TokenEvent) add(doc tokenEventDoc) error {
+	if len(doc.TotalSupply) > 0 {
+		supply, err := common.NewBigFromString(doc.TotalSupply)
+		if err != nil {
+			return err
+		}
+		ev.TotalSupply = &supply
+
+		return nil
+	}
+
+	amount, err := common.NewBigFromString(doc.Amount)
+	if err != nil {
+		return err
+	}
+
+	delta, err := common.NewBigFromString(doc.Delta)
+	if err != nil {
+		return err
+	}
+
+	ev.Changes = append(ev.Changes, TokenBalanceChange{Address: doc.Address, Amount: amount, Delta: delta})
+
+	return nil
+}
+
+func loadTokenEventOperation(st *cdigest.Database, ev *TokenEvent) error {
+	va, found, err := st.Operation(valuehash.NewBytesFromString(ev.FactHash), true)
+	switch {
+	case err != nil:
+		return err
+	case !found:
+		ev.Type = "unknown"
+
+		return nil
+	}
+
+	fact := va.Operation().Fact()
+
+	ev.Index = va.Index()
+	ev.InState = va.InState()
+	ev.deltas = tokenFactDeltas(fact, ev.Contract)
+	ev.Type = "unknown"
+	if i, ok := fact.(hint.Hinter); ok {
+		ev.Type = i.Hint().Type().String()
+		if name, found := tokenEventTypes[i.Hint().Type()]; found {
+			ev.Type = name
+		}
+	}
+
+	if i, ok := fact.(ctypes.Addresses); ok {
+		as, err := i.Addresses()
+		if err != nil {
+			return err
+		}
+
+		ev.addresses = make([]string, len(as))
+		for j := range as {
+			ev.addresses[j] = as[j].String()
+		}
+	}
+
+	return nil
+}
+
+// tokenFactDeltas returns the balance changes of contract by fact, keyed by
+// address. It returns nil if fact is not known.
+func tokenFactDeltas(fact base.Fact, contract string) map[string]common.Big {
+	deltas := map[string]common.Big{}
+
+	add := func(c, a base.Address, amount common.Big) {
+		if c.String() != contract {
+			return
+		}
+
+		if d, found := deltas[a.String()]; found {
+			amount = d.Add(amount)
+		}
+
+		deltas[a.String()] = amount
+	}
+
+	switch t := fact.(type) {
+	case token.RegisterGenesisTokenFact:
+		for i := range t.Balances() {
+			add(t.Contract(), t.Balances()[i].Address(), t.Balances()[i].Amount())
+		}
+	case token.RegisterModelFact:
+		add(t.Contract(), t.Sender(), t.InitialSupply())
+	case token.MintFact:
+		add(t.Contract(), t.Receiver(), t.Amount())
+	case token.BurnFact:
+		add(t.Contract(), t.Target(), t.Amount().Neg())
+	case token.TransferFact:
+		for i := range t.Items() {
+			it := t.Items()[i]
+			add(it.Contract(), t.Sender(), it.Amount().Neg())
+			add(it.Contract(), it.Receiver(), it.Amount())
+		}
+	case token.TransferFromFact:
+		for i := range t.Items() {
+			it := t.Items()[i]
+			add(it.Contract(), it.Target(), it.Amount().Neg())
+			add(it.Contract(), it.Receiver(), it.Amount())
+		}
+	case token.ApproveFact:
+		// NOTE approve does not change balances
+	default:
+		return nil
+	}
+
+	return deltas
+}
@@ -0,0 +1,111 @@
+package digest
+
+import (
+	"testing"
+
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/mitum2/base"
+)
+
+func TestSplitTokenEventChanges(t *testing.T) {
+	blockChanges := func() []TokenBalanceChange {
+		return []TokenBalanceChange{
+			{Address: "a", Amount: common.NewBig(93), Delta: common.NewBig(-7)},
+			{Address: "b", Amount: common.NewBig(57), Delta: common.NewBig(7)},
+		}
+	}
+
+	events := []*TokenEvent{
+		{
+			Contract: "c", Height: 5, Index: 0, Changes: blockChanges(),
+			deltas: map[string]common.Big{"a": common.NewBig(-10), "b": common.NewBig(10)},
+		},
+		{
+			Contract: "c", Height: 5, Index: 1, Changes: blockChanges(),
+			deltas: map[string]common.Big{"a": common.NewBig(3), "b": common.NewBig(-3)},
+		},
+		{
+			// NOTE approve does not change the balances
+			Contract: "c", Height: 5, Index: 2, Changes: blockChanges(),
+			deltas: map[string]common.Big{},
+		},
+		{
+			// NOTE unknown fact keeps the change of block
+			Contract: "c", Height: 5, Index: 3, Changes: blockChanges(),
+		},
+		{
+			Contract: "d", Height: 5, Index: 4,
+			Changes: []TokenBalanceChange{{Address: "a", Amount: common.NewBig(5), Delta: common.NewBig(5)}},
+			deltas:  map[string]common.Big{"a": common.NewBig(5)},
+		},
+	}
+
+	splitTokenEventChanges(events)
+
+	expected := [][]TokenBalanceChange{
+		{
+			{Address: "a", Amount: common.NewBig(90), Delta: common.NewBig(-10)},
+			{Address: "b", Amount: common.NewBig(60), Delta: common.NewBig(10)},
+		},
+		{
+			{Address: "a", Amount: common.NewBig(93), Delta: common.NewBig(3)},
+			{Address: "b", Amount: common.NewBig(57), Delta: common.NewBig(-3)},
+		},
+		{
+			{Address: "a", Amount: common.NewBig(93), Delta: common.ZeroBig},
+			{Address: "b", Amount: common.NewBig(57), Delta: common.ZeroBig},
+		},
+		blockChanges(),
+		{
+			{Address: "a", Amount: common.NewBig(5), Delta: common.NewBig(5)},
+		},
+	}
+
+	for i := range events {
+		changes := events[i].Changes
+
+		if len(changes) != len(expected[i]) {
+			t.Fatalf("event %d: number of changes not matched; expected=%d changes=%d",
+				i, len(expected[i]), len(changes))
+		}
+
+		for j := range changes {
+			e, c := expected[i][j], changes[j]
+
+			if c.Address != e.Address || !c.Amount.Equal(e.Amount) || !c.Delta.Equal(e.Delta) {
+				t.Fatalf("event %d: change not matched; expected=%v changes=%v", i, e, c)
+			}
+		}
+	}
+}
+
+func TestTokenEventNotifier(t *testing.T) {
+	n := NewTokenEventNotifier()
+
+	a, stopa := n.Listen()
+	b, stopb := n.Listen()
+	defer stopb()
+
+	n.Notify(base.Height(3))
+	n.Notify(base.Height(4))
+
+	// NOTE the listener, which does not receive in time, gets the last height
+	for _, ch := range []<-chan base.Height{a, b} {
+		if height := <-ch; height != base.Height(4) {
+			t.Fatalf("last height not notified; height=%v", height)
+		}
+	}
+
+	stopa()
+	n.Notify(base.Height(5))
+
+	select {
+	case height := <-a:
+		t.Fatalf("notified after stopped; height=%v", height)
+	default:
+	}
+
+	if height := <-b; height != base.Height(5) {
+		t.Fatalf("height not notified; height=%v", height)
+	}
+}
@@ -0,0 +1,251 @@
+package digest
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/imfact-labs/currency-model/common"
+	cdigest "github.com/imfact-labs/currency-model/digest"
+	mongodbst "github.com/imfact-labs/currency-model/digest/mongodb"
+	"github.com/imfact-labs/currency-model/digest/util"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+var (
+	DefaultColNameTokenStats    = "digest_token_stats"
+	DefaultColNameTokenActivity = "digest_token_activity"
+	TokenActivityDayLayout      = "2006-01-02"
+)
+
+type TokenStats struct {
+	Height      base.Height          `json:"height"`
+	Holders     int64                `json:"holders"`
+	TotalSupply common.Big           `json:"total_supply"`
+	Top         TokenTopHolders      `json:"top"`
+	Gini        float64              `json:"gini"`
+	Transfers   []TokenDailyTransfer `json:"transfers_per_day"`
+}
+
+type TokenTopHolders struct {
+	Count int     `json:"count"`
+	Share float64 `json:"share"`
+}
+
+type TokenDailyTransfer struct {
+	Day   string `json:"day"`
+	Count int64  `json:"count"`
+}
+
+type tokenStatsDoc struct {
+	Height  base.Height `bson:"height"`
+	Holders int64       `bson:"holders"`
+	Total   string      `bson:"total"`
+	Top     []string    `bson:"top"`
+	Gini    float64     `bson:"gini"`
+}
+
+type tokenActivityDoc struct {
+	Day   string `bson:"day"`
+	Count int64  `bson:"count"`
+}
+
+// TokenStatistics returns the holder count, the share of the top holders and
+// the gini coefficient kept by TokenStatsWorker, and the number of transfer
+// operations of each of the recent days. top is limited by MaxTokenStatsTop.
+func TokenStatistics(st *cdigest.Database, contract string, top int, days int64) (*TokenStats, error) {
+	design, err := Token(st, contract, base.NilHeight)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := TokenStats{
+		Height:      base.NilHeight,
+		TotalSupply: design.Policy().TotalSupply(),
+	}
+
+	var doc tokenStatsDoc
+	switch err := st.MongoClient().GetByFilter(
+		DefaultColNameTokenStats,
+		util.NewBSONFilter("contract", contract).D(),
+		func(res *mongo.SingleResult) error {
+			return res.Decode(&doc)
+		},
+	); {
+	case err == nil:
+		stats.Height = doc.Height
+		stats.Holders = doc.Holders
+		stats.Gini = doc.Gini
+
+		share, err := topHoldersShare(doc.Top, doc.Total, top)
+		if err != nil {
+			return nil, err
+		}
+		stats.Top = share
+	case !errors.Is(err, mongo.ErrNoDocuments):
+		return nil, err
+	}
+
+	transfers, err := tokenDailyTransfers(st, contract, days)
+	if err != nil {
+		return nil, err
+	}
+	stats.Transfers = transfers
+
+	return &stats, nil
+}
+
+// RebuildTokenStats drops the token stats and the cursor of TokenStatsWorker,
+// so the worker computes them again from the first block.
+func RebuildTokenStats(ctx context.Context, st *mongodbst.Database) error {
+	for _, col := range []string{
+		DefaultColNameTokenStats,
+		DefaultColNameTokenActivity,
+		DefaultColNameTokenStatsCursor,
+	} {
+		if _, err := st.Client().Collection(col).DeleteMany(ctx, bson.D{}); err != nil {
+			return errors.Wrap(err, "rebuild token stats")
+		}
+	}
+
+	return nil
+}
+
+// MigrateTokenActivity drops the daily transfers of the old digest databases,
+// which keep the operation hashes instead of the count, and the cursor of
+// TokenStatsWorker, so the worker counts them again from the first block.
+func MigrateTokenActivity(ctx context.Context, client *mongodbst.Client) (bool, error) {
+	switch n, err := client.Count(ctx, DefaultColNameTokenActivity, bson.D{
+		{Key: "operations", Value: bson.D{{Key: "$exists", Value: true}}},
+	}); {
+	case err != nil:
+		return false, errors.Wrap(err, "migrate token activity")
+	case n < 1:
+		return false, nil
+	}
+
+	for _, col := range []string{DefaultColNameTokenActivity, DefaultColNameTokenStatsCursor} {
+		if _, err := client.Collection(col).DeleteMany(ctx, bson.D{}); err != nil {
+			return false, errors.Wrap(err, "migrate token activity")
+		}
+	}
+
+	return true, nil
+}
+
+// tokenDailyTransfers returns the transfers of each day of the recent days
+// until today in UTC, from the newest day; the days without transfers have
+// zero count.
+func tokenDailyTransfers(st *cdigest.Database, contract string, days int64) ([]TokenDailyTransfer, error) {
+	if days < 1 {
+		return nil, nil
+	}
+
+	today := time.Now().UTC()
+	since := today.AddDate(0, 0, -int(days-1)).Format(TokenActivityDayLayout)
+
+	counts := map[string]int64{}
+
+	if err := st.MongoClient().Find(
+		context.Background(),
+		DefaultColNameTokenActivity,
+		bson.D{
+			{Key: "contract", Value: contract},
+			{Key: "day", Value: bson.D{{Key: "$gte", Value: since}}},
+		},
+		func(cursor *mongo.Cursor) (bool, error) {
+			var doc tokenActivityDoc
+			if err := cursor.Decode(&doc); err != nil {
+				return false, err
+			}
+
+			counts[doc.Day] = doc.Count
+
+			return true, nil
+		},
+		options.Find().SetProjection(bson.D{
+			{Key: "day", Value: 1},
+			{Key: "count", Value: 1},
+		}),
+	); err != nil {
+		return nil, err
+	}
+
+	transfers := make([]TokenDailyTransfer, days)
+	for i := range transfers {
+		day := today.AddDate(0, 0, -i).Format(TokenActivityDayLayout)
+
+		transfers[i] = TokenDailyTransfer{Day: day, Count: counts[day]}
+	}
+
+	return transfers, nil
+}
+
+// topHoldersShare returns the share of the top n holders of total; top should
+// be the amounts of the holders in descending order.
+func topHoldersShare(top []string, total string, n int) (TokenTopHolders, error) {
+	if n > len(top) {
+		n = len(top)
+	}
+
+	t, ok := new(big.Int).SetString(total, 10)
+	if !ok && len(total) > 0 {
+		return TokenTopHolders{}, errors.Errorf("invalid total amount, %q", total)
+	}
+
+	if n < 1 || t == nil || t.Sign() < 1 {
+		return TokenTopHolders{Count: n}, nil
+	}
+
+	sum := new(big.Int)
+	for i := range top[:n] {
+		a, ok := new(big.Int).SetString(top[i], 10)
+		if !ok {
+			return TokenTopHolders{}, errors.Errorf("invalid top amount, %q", top[i])
+		}
+
+		sum.Add(sum, a)
+	}
+
+	share, _ := new(big.Rat).SetFrac(sum, t).Float64()
+
+	return TokenTopHolders{Count: n, Share: share}, nil
+}
+
+// giniCoefficient returns the gini coefficient of amounts; amounts should be
+// sorted in descending order. 0 means every holder has the same amount and
+// values close to 1 mean one holder owns almost all.
+func giniCoefficient(amounts []*big.Int) float64 {
+	n := int64(len(amounts))
+	if n < 2 {
+		return 0
+	}
+
+	total := new(big.Int)
+	weighted := new(big.Int)
+
+	// NOTE G = 2*sum(i*x_i)/(n*sum(x)) - (n+1)/n, x in ascending order, i from 1
+	for i := range amounts {
+		rank := big.NewInt(n - int64(i))
+		total.Add(total, amounts[i])
+		weighted.Add(weighted, new(big.Int).Mul(rank, amounts[i]))
+	}
+
+	if total.Sign() < 1 {
+		return 0
+	}
+
+	g := new(big.Rat).SetFrac(
+		new(big.Int).Mul(big.NewInt(2), weighted),
+		new(big.Int).Mul(big.NewInt(n), total),
+	)
+	g.Sub(g, big.NewRat(n+1, n))
+
+	f, _ := g.Float64()
+
+	return f
+}
@@ -91,3 +91,48 @@ func (doc TokenBalanceDoc) MarshalBSON() ([]byte, error) {
 
 	return bsonenc.Marshal(m)
 }
+
+type TokenSymbolDoc struct {
+	mongodbst.BaseDoc
+	st     base.State
+	symbol string
+	sv     state.SymbolStateValue
+}
+
+func NewTokenSymbolDoc(st base.State, enc encoder.Encoder) (*TokenSymbolDoc, error) {
+	sv, err := state.StateSymbolValue(st)
+	if err != nil {
+		return nil, err
+	}
+
+	stateKeys, err := cstate.ParseStateKey(st.Key(), state.TokenPrefix, 3)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := mongodbst.NewBaseDoc(nil, st, enc)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TokenSymbolDoc{
+		BaseDoc: b,
+		st:      st,
+		symbol:  stateKeys[1],
+		sv:      *sv,
+	}, nil
+}
+
+func (doc TokenSymbolDoc) MarshalBSON() ([]byte, error) {
+	m, err := doc.BaseDoc.M()
+	if err != nil {
+		return nil, err
+	}
+
+	m["symbol"] = doc.symbol
+	m["contract"] = doc.sv.Contract().String()
+	m["released"] = doc.sv.Released()
+	m["height"] = doc.st.Height()
+
+	return bsonenc.Marshal(m)
+}
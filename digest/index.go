@@ -26,6 +26,90 @@ var tokenBalanceIndexModels = []mongo.IndexModel{
 		Options: options.Index().
 			SetName(cdigest.IndexPrefix + "token_balance_contract_address_height"),
 	},
+	{
+		Keys: bson.D{
+			bson.E{Key: "height", Value: 1}},
+		Options: options.Index().
+			SetName(cdigest.IndexPrefix + "token_balance_height"),
+	},
+}
+
+var tokenLatestIndexModels = []mongo.IndexModel{
+	{
+		Keys: bson.D{
+			bson.E{Key: "contract", Value: 1}},
+		Options: options.Index().
+			SetName(cdigest.IndexPrefix + "token_latest_contract").
+			SetUnique(true),
+	},
+}
+
+var tokenSymbolIndexModels = []mongo.IndexModel{
+	{
+		Keys: bson.D{
+			bson.E{Key: "symbol", Value: 1}},
+		Options: options.Index().
+			SetName(cdigest.IndexPrefix + "token_symbol_symbol").
+			SetUnique(true),
+	},
+}
+
+var tokenBalanceLatestIndexModels = []mongo.IndexModel{
+	{
+		Keys: bson.D{
+			bson.E{Key: "contract", Value: 1},
+			bson.E{Key: "address", Value: 1}},
+		Options: options.Index().
+			SetName(cdigest.IndexPrefix + "token_balance_latest_contract_address").
+			SetUnique(true),
+	},
+}
+
+var tokenStatsIndexModels = []mongo.IndexModel{
+	{
+		Keys: bson.D{
+			bson.E{Key: "contract", Value: 1}},
+		Options: options.Index().
+			SetName(cdigest.IndexPrefix + "token_stats_contract").
+			SetUnique(true),
+	},
+}
+
+var tokenActivityIndexModels = []mongo.IndexModel{
+	{
+		Keys: bson.D{
+			bson.E{Key: "contract", Value: 1},
+			bson.E{Key: "day", Value: -1}},
+		Options: options.Index().
+			SetName(cdigest.IndexPrefix + "token_activity_contract_day").
+			SetUnique(true),
+	},
+}
+
+var tokenEventIndexModels = []mongo.IndexModel{
+	{
+		Keys: bson.D{
+			bson.E{Key: "contract", Value: 1},
+			bson.E{Key: "height", Value: 1}},
+		Options: options.Index().
+			SetName(cdigest.IndexPrefix + "token_event_contract_height"),
+	},
+	{
+		Keys: bson.D{
+			bson.E{Key: "height", Value: 1}},
+		Options: options.Index().
+			SetName(cdigest.IndexPrefix + "token_event_height"),
+	},
+}
+
+var tokenWebhookIndexModels = []mongo.IndexModel{
+	{
+		Keys: bson.D{
+			bson.E{Key: "url", Value: 1}},
+		Options: options.Index().
+			SetName(cdigest.IndexPrefix + "token_webhook_url").
+			SetUnique(true),
+	},
 }
 
 var DefaultIndexes = cdigest.DefaultIndexes
@@ -33,4 +117,11 @@ var DefaultIndexes = cdigest.DefaultIndexes
 func init() {
 	DefaultIndexes[DefaultColNameToken] = tokenServiceIndexModels
 	DefaultIndexes[DefaultColNameTokenBalance] = tokenBalanceIndexModels
+	DefaultIndexes[DefaultColNameTokenLatest] = tokenLatestIndexModels
+	DefaultIndexes[DefaultColNameTokenBalanceLatest] = tokenBalanceLatestIndexModels
+	DefaultIndexes[DefaultColNameTokenSymbol] = tokenSymbolIndexModels
+	DefaultIndexes[DefaultColNameTokenStats] = tokenStatsIndexModels
+	DefaultIndexes[DefaultColNameTokenActivity] = tokenActivityIndexModels
+	DefaultIndexes[DefaultColNameTokenEvent] = tokenEventIndexModels
+	DefaultIndexes[DefaultColNameTokenWebhook] = tokenWebhookIndexModels
 }
@@ -4,10 +4,17 @@ import (
 	"context"
 
 	cdigest "github.com/imfact-labs/currency-model/digest"
+	"github.com/imfact-labs/mitum2/base"
 	"github.com/imfact-labs/mitum2/isaac"
 	"github.com/imfact-labs/mitum2/launch"
 	"github.com/imfact-labs/mitum2/util"
 	"github.com/imfact-labs/mitum2/util/logging"
+	"github.com/pkg/errors"
+)
+
+var (
+	ContextValueTokenLatestModels  util.ContextKey = "token_latest_models"
+	ContextValueTokenEventNotifier util.ContextKey = "token_event_notifier"
 )
 
 func ProcessDigester(ctx context.Context) (context.Context, error) {
@@ -36,6 +43,13 @@ func ProcessDigester(ctx context.Context) (context.Context, error) {
 		return ctx, nil
 	}
 
+	switch cols, err := BackfillTokenLatest(ctx, st.MongoClient()); {
+	case err != nil:
+		return ctx, err
+	case len(cols) > 0:
+		log.Log().Info().Strs("collections", cols).Msg("latest token states backfilled")
+	}
+
 	var design launch.NodeDesign
 	if err := util.LoadFromContext(ctx,
 		launch.DesignContextKey, &design,
@@ -63,13 +77,127 @@ func ProcessDigester(ctx context.Context) (context.Context, error) {
 		sourceReaders = i
 	}
 
-	di := cdigest.NewDigester(st, root, sourceReaders, fromRemotes, design.NetworkID, vs.String(), nil)
+	var notifier *TokenEventNotifier
+	if err := util.LoadFromContext(ctx, ContextValueTokenEventNotifier, &notifier); err != nil {
+		return ctx, err
+	}
+
+	latest := NewTokenLatestModels()
+	errch := make(chan error)
+
+	di := cdigest.NewDigester(st, root, sourceReaders, fromRemotes, design.NetworkID, vs.String(), errch)
 	_ = di.SetLogging(log)
 
 	di.PrepareFunc = []cdigest.BlockSessionPrepareFunc{
 		cdigest.PrepareCurrencies, cdigest.PrepareAccounts, cdigest.PrepareDIDRegistry,
-		PrepareToken,
+		PrepareToken, PrepareTokenEvent, latest.PrepareTokenLatest, latest.PrepareTokenSymbol,
 	}
 
+	go followDigestedBlocks(ctx, log, st, errch, latest, notifier)
+
+	ctx = context.WithValue(ctx, ContextValueTokenLatestModels, latest)
+
 	return context.WithValue(ctx, cdigest.ContextValueDigester, di), nil
 }
+
+// PdigesterFollowUp digests the blocks, which are stored while the node is
+// stopped, and writes the latest token states of them; the token event
+// streams are notified.
+func PdigesterFollowUp(ctx context.Context) (context.Context, error) {
+	ctx, err := cdigest.PdigesterFollowUp(ctx)
+	if err != nil {
+		return ctx, err
+	}
+
+	return ctx, blocksDigested(ctx)
+}
+
+// PWhenNewBlockConfirmed wraps the function of
+// launch.WhenNewBlockConfirmedFuncContextKey, which digests the confirmed
+// blocks, to write the latest token states and to notify the token event
+// streams after the blocks are digested.
+func PWhenNewBlockConfirmed(ctx context.Context) (context.Context, error) {
+	var log *logging.Logging
+	var f func(base.Height)
+
+	if err := util.LoadFromContextOK(ctx,
+		launch.LoggingContextKey, &log,
+		launch.WhenNewBlockConfirmedFuncContextKey, &f,
+	); err != nil {
+		return ctx, err
+	}
+
+	return context.WithValue(ctx, launch.WhenNewBlockConfirmedFuncContextKey, func(height base.Height) {
+		f(height)
+
+		if err := blocksDigested(ctx); err != nil {
+			log.Log().Error().Err(err).Interface("height", height).Msg("write latest token states")
+		}
+	}), nil
+}
+
+func blocksDigested(ctx context.Context) error {
+	var latest *TokenLatestModels
+	var notifier *TokenEventNotifier
+
+	if err := util.LoadFromContext(ctx,
+		ContextValueTokenLatestModels, &latest,
+		ContextValueTokenEventNotifier, &notifier,
+	); err != nil {
+		return err
+	}
+
+	if latest == nil {
+		return nil
+	}
+
+	var st *cdigest.Database
+	if err := util.LoadFromContextOK(ctx, cdigest.ContextValueDigestDatabase, &st); err != nil {
+		return err
+	}
+
+	return tokenBlocksDigested(ctx, st, latest, notifier)
+}
+
+// tokenBlocksDigested writes the latest token states of the committed blocks
+// and notifies the last digested block to the token event streams.
+func tokenBlocksDigested(
+	ctx context.Context, st *cdigest.Database, latest *TokenLatestModels, notifier *TokenEventNotifier,
+) error {
+	if err := latest.Write(ctx, st); err != nil {
+		return err
+	}
+
+	if notifier != nil {
+		notifier.Notify(st.LastBlock())
+	}
+
+	return nil
+}
+
+// followDigestedBlocks calls tokenBlocksDigested whenever the digester reports
+// the digested block.
+func followDigestedBlocks(
+	ctx context.Context,
+	log *logging.Logging,
+	st *cdigest.Database,
+	errch <-chan error,
+	latest *TokenLatestModels,
+	notifier *TokenEventNotifier,
+) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case err := <-errch:
+			var de cdigest.DigestError
+			if errors.As(err, &de) && de.IsError() {
+				continue
+			}
+
+			if err := tokenBlocksDigested(ctx, st, latest, notifier); err != nil {
+				log.Log().Error().Err(err).Msg("write latest token states")
+			}
+		}
+	}
+}
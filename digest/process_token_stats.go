@@ -0,0 +1,68 @@
+package digest
+
+import (
+	"context"
+
+	cdigest "github.com/imfact-labs/currency-model/digest"
+	"github.com/imfact-labs/mitum2/launch"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/logging"
+	"github.com/imfact-labs/mitum2/util/ps"
+	"github.com/pkg/errors"
+)
+
+var (
+	PNameTokenStats                              = ps.Name("token_stats")
+	ContextValueTokenStatsWorker util.ContextKey = "token_stats_worker"
+)
+
+// ProcessStartTokenStats starts TokenStatsWorker when the digest database is
+// ready.
+func ProcessStartTokenStats(ctx context.Context) (context.Context, error) {
+	var log *logging.Logging
+	if err := util.LoadFromContextOK(ctx, launch.LoggingContextKey, &log); err != nil {
+		return ctx, err
+	}
+
+	var st *cdigest.Database
+	if err := util.LoadFromContext(ctx, cdigest.ContextValueDigestDatabase, &st); err != nil {
+		return ctx, err
+	}
+
+	if st == nil {
+		return ctx, nil
+	}
+
+	switch migrated, err := MigrateTokenActivity(ctx, st.MongoClient()); {
+	case err != nil:
+		return ctx, err
+	case migrated:
+		log.Log().Info().Msg("token activity of old digest database dropped; token stats will be counted again")
+	}
+
+	w := NewTokenStatsWorker(st, 0)
+	_ = w.SetLogging(log)
+
+	if err := w.Start(context.Background()); err != nil {
+		return ctx, err
+	}
+
+	return context.WithValue(ctx, ContextValueTokenStatsWorker, w), nil
+}
+
+func ProcessStopTokenStats(ctx context.Context) (context.Context, error) {
+	var w *TokenStatsWorker
+	if err := util.LoadFromContext(ctx, ContextValueTokenStatsWorker, &w); err != nil {
+		return ctx, err
+	}
+
+	if w == nil {
+		return ctx, nil
+	}
+
+	if err := w.Stop(); err != nil && !errors.Is(err, util.ErrDaemonAlreadyStopped) {
+		return ctx, err
+	}
+
+	return ctx, nil
+}
@@ -0,0 +1,74 @@
+package digest
+
+import (
+	"context"
+
+	cdigest "github.com/imfact-labs/currency-model/digest"
+	"github.com/imfact-labs/mitum2/launch"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/logging"
+	"github.com/imfact-labs/mitum2/util/ps"
+	"github.com/pkg/errors"
+)
+
+var (
+	PNameTokenWebhook                              = ps.Name("token_webhook")
+	ContextValueTokenWebhookDesign util.ContextKey = "token_webhook_design"
+	ContextValueTokenWebhook       util.ContextKey = "token_webhook"
+)
+
+// ProcessStartTokenWebhook starts TokenWebhook when the webhook design is given
+// and the digest database is ready.
+func ProcessStartTokenWebhook(ctx context.Context) (context.Context, error) {
+	var log *logging.Logging
+	var design *TokenWebhookDesign
+
+	if err := util.LoadFromContextOK(ctx, launch.LoggingContextKey, &log); err != nil {
+		return ctx, err
+	}
+
+	if err := util.LoadFromContext(ctx, ContextValueTokenWebhookDesign, &design); err != nil {
+		return ctx, err
+	}
+
+	if design == nil {
+		return ctx, nil
+	}
+
+	var st *cdigest.Database
+	if err := util.LoadFromContext(ctx, cdigest.ContextValueDigestDatabase, &st); err != nil {
+		return ctx, err
+	}
+
+	if st == nil {
+		log.Log().Warn().Msg("token webhook ignored; digest database is not ready")
+
+		return ctx, nil
+	}
+
+	wh := NewTokenWebhook(st, *design)
+	_ = wh.SetLogging(log)
+
+	if err := wh.Start(context.Background()); err != nil {
+		return ctx, err
+	}
+
+	return context.WithValue(ctx, ContextValueTokenWebhook, wh), nil
+}
+
+func ProcessStopTokenWebhook(ctx context.Context) (context.Context, error) {
+	var wh *TokenWebhook
+	if err := util.LoadFromContext(ctx, ContextValueTokenWebhook, &wh); err != nil {
+		return ctx, err
+	}
+
+	if wh == nil {
+		return ctx, nil
+	}
+
+	if err := wh.Stop(); err != nil && !errors.Is(err, util.ErrDaemonAlreadyStopped) {
+		return ctx, err
+	}
+
+	return ctx, nil
+}
@@ -0,0 +1,54 @@
+package digest
+
+import (
+	"sync"
+
+	"github.com/imfact-labs/mitum2/base"
+)
+
+// TokenEventNotifier passes the height of the digested block to the listeners
+// after the block is committed, so the token event streams read the new events
+// without polling the digest database.
+type TokenEventNotifier struct {
+	sync.Mutex
+	listeners map[chan base.Height]struct{}
+}
+
+func NewTokenEventNotifier() *TokenEventNotifier {
+	return &TokenEventNotifier{
+		listeners: map[chan base.Height]struct{}{},
+	}
+}
+
+// Listen returns the channel of the digested heights and the function to stop
+// listening. The channel keeps only the last height, so the slow listener
+// does not block the digester.
+func (n *TokenEventNotifier) Listen() (<-chan base.Height, func()) {
+	ch := make(chan base.Height, 1)
+
+	n.Lock()
+	defer n.Unlock()
+
+	n.listeners[ch] = struct{}{}
+
+	return ch, func() {
+		n.Lock()
+		defer n.Unlock()
+
+		delete(n.listeners, ch)
+	}
+}
+
+func (n *TokenEventNotifier) Notify(height base.Height) {
+	n.Lock()
+	defer n.Unlock()
+
+	for ch := range n.listeners {
+		select {
+		case <-ch:
+		default:
+		}
+
+		ch <- height
+	}
+}
@@ -0,0 +1,351 @@
+package digest
+
+import (
+	"context"
+	"math/big"
+	"sort"
+	"time"
+
+	cdigest "github.com/imfact-labs/currency-model/digest"
+	mongodbst "github.com/imfact-labs/currency-model/digest/mongodb"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/encoder"
+	"github.com/imfact-labs/mitum2/util/logging"
+	"github.com/imfact-labs/token-model/operation/token"
+	"github.com/imfact-labs/token-model/state"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+var (
+	DefaultColNameTokenStatsCursor = "digest_token_stats_cursor"
+	MaxTokenStatsTop               = 100
+)
+
+var (
+	defaultTokenStatsInterval             = time.Second * 3
+	tokenStatsHeightsLimit    base.Height = 100
+)
+
+// TokenStatsWorker keeps the token stats and the daily transfers up to the
+// digested blocks. The stats of a token are computed again from the latest
+// balances whenever its balances change, and the transfers are the counts of
+// the transfer and transfer-from operations by the confirmed day. The last
+// processed height is saved in the digest database after the blocks are
+// counted, so the blocks are not counted again; without it the worker starts
+// from the first block.
+type TokenStatsWorker struct {
+	*logging.Logging
+	*util.ContextDaemon
+	st       *cdigest.Database
+	interval time.Duration
+}
+
+func NewTokenStatsWorker(st *cdigest.Database, interval time.Duration) *TokenStatsWorker {
+	if interval <= 0 {
+		interval = defaultTokenStatsInterval
+	}
+
+	w := &TokenStatsWorker{
+		Logging: logging.NewLogging(func(c zerolog.Context) zerolog.Context {
+			return c.Str("module", "token-stats")
+		}),
+		st:       st,
+		interval: interval,
+	}
+
+	w.ContextDaemon = util.NewContextDaemon(w.start)
+
+	return w
+}
+
+func (w *TokenStatsWorker) start(ctx context.Context) error {
+	for {
+		switch more, err := w.digestNext(ctx); {
+		case ctx.Err() != nil:
+			return nil
+		case err != nil:
+			w.Log().Error().Err(err).Msg("failed to update token stats")
+		case more:
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(w.interval):
+		}
+	}
+}
+
+// digestNext updates the stats by the blocks after the cursor and returns true
+// if there may be more blocks to process.
+func (w *TokenStatsWorker) digestNext(ctx context.Context) (bool, error) {
+	from, err := loadTokenStatsCursor(ctx, w.st.MongoClient())
+	if err != nil {
+		return false, err
+	}
+
+	last := w.st.LastBlock()
+	if last <= from {
+		return false, nil
+	}
+
+	to := from + tokenStatsHeightsLimit
+	if to > last {
+		to = last
+	}
+
+	contracts, err := tokenBalanceContracts(ctx, w.st.MongoClient(), from, to)
+	if err != nil {
+		return false, err
+	}
+
+	for i := range contracts {
+		if err := updateTokenStats(ctx, w.st.MongoClient(), w.st.Encoders(), contracts[i]); err != nil {
+			return false, err
+		}
+	}
+
+	if err := updateTokenActivity(ctx, w.st.MongoClient(), w.st.Encoders(), from, to); err != nil {
+		return false, err
+	}
+
+	if err := saveTokenStatsCursor(ctx, w.st.MongoClient(), to); err != nil {
+		return false, err
+	}
+
+	w.Log().Debug().Interface("from", from+1).Interface("to", to).Int("contracts", len(contracts)).
+		Msg("token stats updated")
+
+	return to < last, nil
+}
+
+type tokenStatsCursor struct {
+	Height base.Height `bson:"height"`
+}
+
+func loadTokenStatsCursor(ctx context.Context, client *mongodbst.Client) (base.Height, error) {
+	var cursor tokenStatsCursor
+
+	switch err := client.GetByFilter(
+		DefaultColNameTokenStatsCursor,
+		bson.D{},
+		func(res *mongo.SingleResult) error {
+			return res.Decode(&cursor)
+		},
+	); {
+	case err == nil:
+		return cursor.Height, nil
+	case errors.Is(err, mongo.ErrNoDocuments):
+		return base.NilHeight, nil
+	default:
+		return base.NilHeight, err
+	}
+}
+
+func saveTokenStatsCursor(ctx context.Context, client *mongodbst.Client, height base.Height) error {
+	return client.Bulk(ctx, DefaultColNameTokenStatsCursor, []mongo.WriteModel{
+		mongo.NewReplaceOneModel().
+			SetFilter(bson.D{}).
+			SetReplacement(bson.D{{Key: "height", Value: height}}).
+			SetUpsert(true),
+	}, false)
+}
+
+func tokenHeightRange(from, to base.Height) bson.D {
+	return bson.D{{Key: "height", Value: bson.D{
+		{Key: "$gt", Value: from},
+		{Key: "$lte", Value: to},
+	}}}
+}
+
+// tokenBalanceContracts returns the contracts whose balances changed in the
+// blocks of (from, to].
+func tokenBalanceContracts(ctx context.Context, client *mongodbst.Client, from, to base.Height) ([]string, error) {
+	var contracts []string
+
+	if err := client.Collection(DefaultColNameTokenBalance).
+		Distinct(ctx, "contract", tokenHeightRange(from, to)).
+		Decode(&contracts); err != nil {
+		return nil, errors.Wrap(err, "token balance contracts")
+	}
+
+	return contracts, nil
+}
+
+// updateTokenStats computes the stats of contract from the latest balances and
+// replaces the stored one.
+func updateTokenStats(
+	ctx context.Context, client *mongodbst.Client, encs *encoder.Encoders, contract string,
+) error {
+	var amounts []*big.Int
+	height := base.NilHeight
+
+	if err := client.Find(
+		ctx,
+		DefaultColNameTokenBalanceLatest,
+		bson.D{{Key: "contract", Value: contract}},
+		func(cursor *mongo.Cursor) (bool, error) {
+			sta, err := cdigest.LoadState(cursor.Decode, encs)
+			if err != nil {
+				return false, err
+			}
+
+			amount, err := state.StateTokenBalanceValue(sta)
+			if err != nil {
+				return false, err
+			}
+
+			if amount.OverZero() {
+				amounts = append(amounts, amount.Int)
+			}
+
+			if sta.Height() > height {
+				height = sta.Height()
+			}
+
+			return true, nil
+		},
+	); err != nil {
+		return errors.Wrap(err, "token stats")
+	}
+
+	sort.Slice(amounts, func(i, j int) bool {
+		return amounts[i].Cmp(amounts[j]) > 0
+	})
+
+	total := new(big.Int)
+	for i := range amounts {
+		total.Add(total, amounts[i])
+	}
+
+	top := make(bson.A, 0, MaxTokenStatsTop)
+	for i := range amounts {
+		if i >= MaxTokenStatsTop {
+			break
+		}
+
+		top = append(top, amounts[i].String())
+	}
+
+	return client.Bulk(ctx, DefaultColNameTokenStats, []mongo.WriteModel{
+		mongo.NewReplaceOneModel().
+			SetFilter(bson.D{{Key: "contract", Value: contract}}).
+			SetReplacement(bson.D{
+				{Key: "contract", Value: contract},
+				{Key: "height", Value: height},
+				{Key: "holders", Value: int64(len(amounts))},
+				{Key: "total", Value: total.String()},
+				{Key: "top", Value: top},
+				{Key: "gini", Value: giniCoefficient(amounts)},
+			}).
+			SetUpsert(true),
+	}, false)
+}
+
+// updateTokenActivity counts the transfer and transfer-from operations of the
+// blocks of (from, to] in the daily transfers of their contracts. The blocks
+// are counted once, because the cursor is saved after the counts are updated.
+func updateTokenActivity(
+	ctx context.Context, client *mongodbst.Client, encs *encoder.Encoders, from, to base.Height,
+) error {
+	type dayKey struct {
+		contract string
+		day      string
+	}
+
+	counts := map[dayKey]int64{}
+	heights := map[dayKey]base.Height{}
+	var keys []dayKey
+
+	if err := client.Find(
+		ctx,
+		cdigest.DefaultColNameOperation,
+		tokenHeightRange(from, to),
+		func(cursor *mongo.Cursor) (bool, error) {
+			va, err := cdigest.LoadOperation(cursor.Decode, encs)
+			if err != nil {
+				return false, err
+			}
+
+			if !va.InState() {
+				return true, nil
+			}
+
+			fact := va.Operation().Fact()
+			day := va.ConfirmedAt().UTC().Format(TokenActivityDayLayout)
+
+			for _, contract := range transferContracts(fact) {
+				k := dayKey{contract: contract, day: day}
+				if _, found := counts[k]; !found {
+					keys = append(keys, k)
+				}
+
+				counts[k]++
+				if va.Height() > heights[k] {
+					heights[k] = va.Height()
+				}
+			}
+
+			return true, nil
+		},
+	); err != nil {
+		return errors.Wrap(err, "token activity")
+	}
+
+	if len(keys) < 1 {
+		return nil
+	}
+
+	models := make([]mongo.WriteModel, len(keys))
+	for i := range keys {
+		k := keys[i]
+
+		models[i] = mongo.NewUpdateOneModel().
+			SetFilter(bson.D{
+				{Key: "contract", Value: k.contract},
+				{Key: "day", Value: k.day},
+			}).
+			SetUpdate(bson.D{
+				{Key: "$inc", Value: bson.D{{Key: "count", Value: counts[k]}}},
+				{Key: "$max", Value: bson.D{{Key: "height", Value: heights[k]}}},
+			}).
+			SetUpsert(true)
+	}
+
+	return client.Bulk(ctx, DefaultColNameTokenActivity, models, false)
+}
+
+// transferContracts returns the contracts of the items of the transfer and
+// transfer-from facts; other facts returns nothing.
+func transferContracts(fact base.Fact) []string {
+	var contracts []string
+
+	add := func(contract base.Address) {
+		s := contract.String()
+		for i := range contracts {
+			if contracts[i] == s {
+				return
+			}
+		}
+
+		contracts = append(contracts, s)
+	}
+
+	switch t := fact.(type) {
+	case token.TransferFact:
+		for i := range t.Items() {
+			add(t.Items()[i].Contract())
+		}
+	case token.TransferFromFact:
+		for i := range t.Items() {
+			add(t.Items()[i].Contract())
+		}
+	}
+
+	return contracts
+}
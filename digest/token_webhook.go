@@ -0,0 +1,349 @@
+package digest
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	cdigest "github.com/imfact-labs/currency-model/digest"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/logging"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+var (
+	DefaultColNameTokenWebhook  = "digest_token_webhook"
+	TokenWebhookSignatureHeader = "X-Token-Signature"
+	TokenWebhookTimestampHeader = "X-Token-Timestamp"
+	TokenWebhookDeliveryHeader  = "X-Token-Delivery"
+)
+
+var (
+	defaultTokenWebhookInterval         = time.Second * 3
+	defaultTokenWebhookTimeout          = time.Second * 10
+	defaultTokenWebhookMaxBackoff       = time.Minute * 5
+	tokenWebhookRowsLimit         int64 = 500
+)
+
+// TokenWebhookDesign is the design of token webhooks. Every url receives the
+// events of contracts touching accounts; empty contracts or accounts means
+// every one.
+type TokenWebhookDesign struct {
+	URLs       []string      `yaml:"urls"`
+	Secret     string        `yaml:"secret"`
+	Contracts  []string      `yaml:"contracts"`
+	Accounts   []string      `yaml:"accounts"`
+	Interval   time.Duration `yaml:"interval"`
+	Timeout    time.Duration `yaml:"timeout"`
+	MaxBackoff time.Duration `yaml:"max_backoff"`
+}
+
+func (d *TokenWebhookDesign) IsValid([]byte) error {
+	e := util.ErrInvalid.Errorf("invalid TokenWebhookDesign")
+
+	if len(d.URLs) < 1 {
+		return e.Wrap(errors.Errorf("empty urls"))
+	}
+
+	for i := range d.URLs {
+		switch u, err := url.Parse(d.URLs[i]); {
+		case err != nil:
+			return e.Wrap(err)
+		case u.Scheme != "http" && u.Scheme != "https":
+			return e.Wrap(errors.Errorf("unsupported url scheme, %q", d.URLs[i]))
+		}
+	}
+
+	if util.IsDuplicatedSlice(d.URLs, func(i string) (bool, string) { return true, i }) {
+		return e.Wrap(errors.Errorf("duplicated url"))
+	}
+
+	if len(d.Secret) < 1 {
+		return e.Wrap(errors.Errorf("empty secret"))
+	}
+
+	if d.Interval <= 0 {
+		d.Interval = defaultTokenWebhookInterval
+	}
+
+	if d.Timeout <= 0 {
+		d.Timeout = defaultTokenWebhookTimeout
+	}
+
+	if d.MaxBackoff <= 0 {
+		d.MaxBackoff = defaultTokenWebhookMaxBackoff
+	}
+
+	return nil
+}
+
+type TokenWebhookPayload struct {
+	Delivery string     `json:"delivery"`
+	Event    TokenEvent `json:"event"`
+}
+
+// TokenWebhook posts the digested token events to the urls of design. Each url
+// has its own cursor in the digest database, so a restart continues from the
+// last delivered event. A failed delivery is retried with backoff until it
+// succeeds; an event can be posted again only when the node stops between the
+// delivery and saving the cursor, and the delivery header keeps it same.
+type TokenWebhook struct {
+	*logging.Logging
+	*util.ContextDaemon
+	st     *cdigest.Database
+	design TokenWebhookDesign
+	client *http.Client
+}
+
+func NewTokenWebhook(st *cdigest.Database, design TokenWebhookDesign) *TokenWebhook {
+	wh := &TokenWebhook{
+		Logging: logging.NewLogging(func(c zerolog.Context) zerolog.Context {
+			return c.Str("module", "token-webhook")
+		}),
+		st:     st,
+		design: design,
+		client: &http.Client{Timeout: design.Timeout},
+	}
+
+	wh.ContextDaemon = util.NewContextDaemon(wh.start)
+
+	return wh
+}
+
+func (wh *TokenWebhook) start(ctx context.Context) error {
+	var wg sync.WaitGroup
+
+	for i := range wh.design.URLs {
+		wg.Add(1)
+
+		go func(u string) {
+			defer wg.Done()
+
+			wh.run(ctx, u)
+		}(wh.design.URLs[i])
+	}
+
+	wg.Wait()
+
+	return nil
+}
+
+func (wh *TokenWebhook) run(ctx context.Context, u string) {
+	for {
+		switch more, err := wh.deliverNext(ctx, u); {
+		case ctx.Err() != nil:
+			return
+		case err != nil:
+			wh.Log().Error().Err(err).Str("url", u).Msg("failed to deliver token events")
+		case more:
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wh.design.Interval):
+		}
+	}
+}
+
+// deliverNext delivers the events after the cursor of u and returns true if
+// there may be more events to deliver.
+func (wh *TokenWebhook) deliverNext(ctx context.Context, u string) (bool, error) {
+	cursor, err := wh.loadCursor(ctx, u)
+	if err != nil {
+		return false, err
+	}
+
+	last, err := TokenEvents(wh.st, wh.design.Contracts, cursor.Height-1, tokenWebhookRowsLimit,
+		func(ev TokenEvent) (bool, error) {
+			if !wh.watches(ev) {
+				return true, nil
+			}
+
+			if cursor.delivered(ev) {
+				return true, nil
+			}
+
+			if err := wh.deliver(ctx, u, ev); err != nil {
+				return false, err
+			}
+
+			cursor = tokenWebhookCursor{Height: ev.Height, Index: ev.Index, Contract: ev.Contract}
+
+			return true, wh.saveCursor(ctx, u, cursor)
+		},
+	)
+
+	switch {
+	case err != nil:
+		return false, err
+	case last < cursor.Height:
+		return false, nil
+	default:
+		return true, wh.saveCursor(ctx, u, tokenWebhookCursor{Height: last + 1})
+	}
+}
+
+func (wh *TokenWebhook) watches(ev TokenEvent) bool {
+	if len(wh.design.Accounts) < 1 {
+		return true
+	}
+
+	for i := range wh.design.Accounts {
+		if ev.Touches(wh.design.Accounts[i]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (wh *TokenWebhook) deliver(ctx context.Context, u string, ev TokenEvent) error {
+	payload := TokenWebhookPayload{
+		Delivery: ev.Contract + ":" + ev.FactHash,
+		Event:    ev,
+	}
+
+	body, err := util.MarshalJSON(payload)
+	if err != nil {
+		return err
+	}
+
+	backoff := time.Second
+
+	for {
+		err := wh.post(ctx, u, payload.Delivery, body)
+		if err == nil {
+			return nil
+		}
+
+		wh.Log().Warn().Err(err).
+			Str("url", u).Str("delivery", payload.Delivery).Dur("backoff", backoff).
+			Msg("failed to post token event; will retry")
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+
+		if backoff *= 2; backoff > wh.design.MaxBackoff {
+			backoff = wh.design.MaxBackoff
+		}
+	}
+}
+
+func (wh *TokenWebhook) post(ctx context.Context, u, delivery string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(TokenWebhookDeliveryHeader, delivery)
+	req.Header.Set(TokenWebhookTimestampHeader, timestamp)
+	req.Header.Set(TokenWebhookSignatureHeader, SignTokenWebhookPayload(wh.design.Secret, timestamp, body))
+
+	res, err := wh.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_, _ = io.Copy(io.Discard, res.Body)
+		_ = res.Body.Close()
+	}()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return errors.Errorf("unexpected status, %d", res.StatusCode)
+	}
+
+	return nil
+}
+
+// SignTokenWebhookPayload returns the signature header value of body, the
+// hex-encoded HMAC-SHA256 of "<timestamp>.<body>" by secret.
+func SignTokenWebhookPayload(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = fmt.Fprintf(mac, "%s.", timestamp)
+	_, _ = mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// tokenWebhookCursor points the last delivered event; the events up to Height,
+// Index and Contract in the order of TokenEvents are already delivered. Empty
+// Contract means no event of Index is delivered yet.
+type tokenWebhookCursor struct {
+	Height   base.Height `bson:"height"`
+	Index    uint64      `bson:"index"`
+	Contract string      `bson:"contract"`
+}
+
+func (c tokenWebhookCursor) delivered(ev TokenEvent) bool {
+	switch {
+	case ev.Height != c.Height:
+		return ev.Height < c.Height
+	case ev.Index != c.Index:
+		return ev.Index < c.Index
+	default:
+		return ev.Contract <= c.Contract
+	}
+}
+
+func (wh *TokenWebhook) loadCursor(ctx context.Context, u string) (tokenWebhookCursor, error) {
+	var cursor tokenWebhookCursor
+
+	switch err := wh.st.MongoClient().GetByFilter(
+		DefaultColNameTokenWebhook,
+		bson.D{{Key: "url", Value: u}},
+		func(res *mongo.SingleResult) error {
+			return res.Decode(&cursor)
+		},
+	); {
+	case err == nil:
+		return cursor, nil
+	case !errors.Is(err, mongo.ErrNoDocuments):
+		return cursor, err
+	}
+
+	// NOTE new url starts from the next block, not from the first events
+	top, err := TokenEventTopHeight(wh.st, wh.design.Contracts)
+	if err != nil {
+		return cursor, err
+	}
+
+	cursor = tokenWebhookCursor{Height: top + 1}
+
+	return cursor, wh.saveCursor(ctx, u, cursor)
+}
+
+func (wh *TokenWebhook) saveCursor(ctx context.Context, u string, cursor tokenWebhookCursor) error {
+	return wh.st.MongoClient().Bulk(ctx, DefaultColNameTokenWebhook, []mongo.WriteModel{
+		mongo.NewReplaceOneModel().
+			SetFilter(bson.D{{Key: "url", Value: u}}).
+			SetReplacement(bson.D{
+				{Key: "url", Value: u},
+				{Key: "height", Value: cursor.Height},
+				{Key: "index", Value: cursor.Index},
+				{Key: "contract", Value: cursor.Contract},
+			}).
+			SetUpsert(true),
+	}, false)
+}
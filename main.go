@@ -26,9 +26,9 @@ var (
 //revive:disable:nested-structs
 var CLI struct { //nolint:govet //...
 	launch.BaseFlags
-	Init      ccmds.INITCommand `cmd:"" help:"init node"`
-	Run       cmds.RunCommand   `cmd:"" help:"run node"`
-	Storage   cmds.Storage      `cmd:""`
+	Init      cmds.INITCommand `cmd:"" help:"init node"`
+	Run       cmds.RunCommand  `cmd:"" help:"run node"`
+	Storage   cmds.Storage     `cmd:""`
 	Operation struct {
 		Currency ccmds.CurrencyCommand `cmd:"" help:"currency operation"`
 		Suffrage ccmds.SuffrageCommand `cmd:"" help:"suffrage operation"`
@@ -37,6 +37,11 @@ var CLI struct { //nolint:govet //...
 	Network struct {
 		Client cmds.NetworkClientCommand `cmd:"" help:"network client"`
 	} `cmd:"" help:"network"`
+	Token struct {
+		Shell   cmds.TokenShellCommand   `cmd:"" help:"interactive token shell"`
+		Vectors cmds.TokenVectorsCommand `cmd:"" help:"encoding vectors of token facts and state values"`
+		Bench   cmds.TokenBenchCommand   `cmd:"" help:"benchmark token operation processors and balance merger"`
+	} `cmd:"" help:"token"`
 	Key struct {
 		New     ccmds.KeyNewCommand      `cmd:"" help:"generate new key"`
 		Address ccmds.KeyAddressCommand  `cmd:"" help:"generate address from key"`
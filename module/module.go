@@ -40,6 +40,9 @@ func (Module) Register(reg *modulekit.Registry) error {
 		ID,
 		modulekit.APIRoute{Path: modapi.HandlerPathToken, Methods: []string{"GET"}},
 		modulekit.APIRoute{Path: modapi.HandlerPathTokenBalance, Methods: []string{"GET"}},
+		modulekit.APIRoute{Path: modapi.HandlerPathTokenSupplyHistory, Methods: []string{"GET"}},
+		modulekit.APIRoute{Path: modapi.HandlerPathTokenStats, Methods: []string{"GET"}},
+		modulekit.APIRoute{Path: modapi.HandlerPathTokenEvents, Methods: []string{"GET"}},
 	); err != nil {
 		return err
 	}
@@ -6,4 +6,5 @@ import (
 
 const (
 	DuplicationTypeTokenSender types.DuplicationKeyType = "token-sender"
+	DuplicationTypeTokenSymbol types.DuplicationKeyType = "token-symbol"
 )
@@ -77,7 +77,7 @@ func (fact ApproveFact) IsValid(b []byte) error {
 				common.ErrDupVal.Wrap(errors.Errorf("contract account %v", item.contract)))
 		}
 
-		founds[item.Contract().String()] = struct{}{}
+		founds[item.contract.String()+"-"+item.approved.String()] = struct{}{}
 	}
 
 	if err := common.IsValidOperationFact(fact, b); err != nil {
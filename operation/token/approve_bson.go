@@ -57,13 +57,7 @@ func (fact *ApproveFact) DecodeBSON(b []byte, enc *bsonenc.Encoder) error {
 }
 
 func (op Approve) MarshalBSON() ([]byte, error) {
-	return bsonenc.Marshal(
-		bson.M{
-			"_hint": op.Hint().String(),
-			"hash":  op.Hash().String(),
-			"fact":  op.Fact(),
-			"signs": op.Signs(),
-		})
+	return marshalOperationBSON(op)
 }
 
 func (op *Approve) DecodeBSON(b []byte, enc *bsonenc.Encoder) error {
@@ -140,9 +140,12 @@ func (opp *ApproveItemProcessor) Close() {
 
 type ApproveProcessor struct {
 	*base.BaseOperationProcessor
+	canonical bool
 }
 
-func NewApproveProcessor() ctypes.GetNewProcessor {
+// NewApproveProcessor returns the processor, which writes the canonical policy
+// from the canonical approve list height.
+func NewApproveProcessor(canonicalHeight base.Height) ctypes.GetNewProcessor {
 	return func(
 		height base.Height,
 		getStateFunc base.GetStateFunc,
@@ -164,6 +167,7 @@ func NewApproveProcessor() ctypes.GetNewProcessor {
 		}
 
 		opp.BaseOperationProcessor = b
+		opp.canonical = isCanonicalApproveListHeight(canonicalHeight, height)
 
 		return opp, nil
 	}
@@ -248,10 +252,7 @@ func (opp *ApproveProcessor) Process( // nolint:dupl
 
 	for ca, de := range designs {
 		g := state.NewStateKeyGenerator(ca)
-		stateMergeValues = append(stateMergeValues, cstate.NewStateMergeValue(
-			g.Design(),
-			state.NewDesignStateValue(de),
-		))
+		stateMergeValues = append(stateMergeValues, state.NewDesignStateMergeValue(g.Design(), designOfHeight(opp.canonical, de)))
 	}
 
 	return stateMergeValues, nil, nil
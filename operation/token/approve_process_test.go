@@ -0,0 +1,153 @@
+package token
+
+import (
+	"testing"
+
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/currency-model/operation/test"
+	"github.com/imfact-labs/token-model/types"
+)
+
+func TestApproveProcess(t *testing.T) {
+	cases := []struct {
+		name     string
+		design   bool
+		balance  bool
+		approved int64
+		amount   int64
+		expected int64
+		spender  func(testTransferAccounts, *test.TestProcessor) test.Account
+		err      bool
+	}{
+		{name: "approve", design: true, balance: true, amount: 10, expected: 10},
+		{name: "add to approved", design: true, balance: true, approved: 30, amount: 10, expected: 40},
+		{name: "remove approved", design: true, balance: true, approved: 30, amount: 0, expected: 0},
+		{name: "remove not approved", design: true, balance: true, amount: 0, err: true},
+		{name: "design missing", design: false, balance: true, amount: 10, err: true},
+		{name: "token balance missing", design: true, balance: false, amount: 10, err: true},
+		{
+			name: "approved is contract account", design: true, balance: true, amount: 10, err: true,
+			spender: func(a testTransferAccounts, tp *test.TestProcessor) test.Account {
+				accounts := make([]test.Account, 1)
+				tp.SetContractAccount(a.sender.Address(), tp.NewPrivateKey("other-contract"), 0,
+					a.currency, accounts, true)
+
+				return accounts[0]
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			tp := &test.TestProcessor{}
+			tp.Setup(test.NewMockStateGetter())
+
+			a := newTestTransferAccounts(tp)
+
+			spender := a.receiver
+			if c.spender != nil {
+				spender = c.spender(a, tp)
+			}
+
+			p := NewTestApproveProcessor(tp)
+			items := make([]ApproveItem, 1)
+
+			if c.design {
+				var boxes []types.ApproveBox
+				if c.approved > 0 {
+					boxes = []types.ApproveBox{
+						types.NewApproveBox(a.sender.Address(), []types.ApproveInfo{
+							types.NewApproveInfo(spender.Address(), common.NewBig(c.approved)),
+						}),
+					}
+				}
+
+				p.SetDesign(a.contract.Address(), "ABC", "abc", 0, 100, boxes, true)
+			}
+
+			if c.balance {
+				p.SetTokenBalance(a.contract.Address(), a.sender.Address(), 100, true)
+			}
+
+			p.MakeItem(a.contract.Address(), spender, c.amount, items).
+				MakeOperation(a.sender.Address(), a.sender.Priv(), items, a.currency).
+				Create().
+				IsValid()
+
+			if err := p.Error(); err != nil {
+				t.Fatalf("invalid operation: %v", err)
+			}
+
+			p.RunPreProcess()
+
+			switch err := p.Error(); {
+			case c.err && err == nil:
+				t.Fatal("expected preprocess error")
+			case c.err:
+				return
+			case err != nil:
+				t.Fatalf("preprocess: %v", err)
+			}
+
+			p.RunProcess()
+
+			if err := p.Error(); err != nil {
+				t.Fatalf("process: %v", err)
+			}
+
+			checkTestAllowance(t, tp, a.contract.Address(), a.sender.Address(), spender.Address(), c.expected)
+		})
+	}
+}
+
+func TestApproveFactDuplicateItems(t *testing.T) {
+	tp := &test.TestProcessor{}
+	tp.Setup(test.NewMockStateGetter())
+
+	a := newTestTransferAccounts(tp)
+
+	others := make([]test.Account, 1)
+	tp.SetAccount(tp.NewPrivateKey("other"), 1000, a.currency, others, true)
+
+	cases := []struct {
+		name  string
+		items []ApproveItem
+		err   bool
+	}{
+		{
+			name: "single item",
+			items: []ApproveItem{
+				NewTestApproveItem(a.contract.Address(), a.receiver.Address(), 10),
+			},
+		},
+		{
+			name: "approved accounts in same contract",
+			items: []ApproveItem{
+				NewTestApproveItem(a.contract.Address(), a.receiver.Address(), 10),
+				NewTestApproveItem(a.contract.Address(), others[0].Address(), 20),
+			},
+		},
+		{
+			name: "duplicate items",
+			items: []ApproveItem{
+				NewTestApproveItem(a.contract.Address(), a.receiver.Address(), 10),
+				NewTestApproveItem(a.contract.Address(), a.receiver.Address(), 20),
+			},
+			err: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			p := NewTestApproveProcessor(tp)
+			p.MakeOperation(a.sender.Address(), a.sender.Priv(), c.items, a.currency).IsValid()
+
+			switch err := p.Error(); {
+			case c.err && err == nil:
+				t.Fatal("expected invalid operation")
+			case !c.err && err != nil:
+				t.Fatalf("invalid operation: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package token
+
+import (
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/currency-model/operation/extras"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/hint"
+	"github.com/imfact-labs/mitum2/util/valuehash"
+	"github.com/imfact-labs/token-model/types"
+)
+
+var (
+	BackfillSymbolFactHint = hint.MustNewHint("mitum-token-backfill-symbol-operation-fact-v0.0.1")
+	BackfillSymbolHint     = hint.MustNewHint("mitum-token-backfill-symbol-operation-v0.0.1")
+)
+
+// BackfillSymbolFact adds the symbol registry entry of the design registered
+// before the symbol registry is kept; any account can backfill the entry, and
+// the symbol should be same with the symbol of design.
+type BackfillSymbolFact struct {
+	TokenFact
+	symbol types.TokenSymbol
+}
+
+func NewBackfillSymbolFact(
+	token []byte,
+	sender, contract base.Address,
+	currency ctypes.CurrencyID,
+	symbol types.TokenSymbol,
+) BackfillSymbolFact {
+	fact := BackfillSymbolFact{
+		TokenFact: NewTokenFact(
+			base.NewBaseFact(BackfillSymbolFactHint, token), sender, contract, currency,
+		),
+		symbol: symbol,
+	}
+	fact.SetHash(fact.GenerateHash())
+	return fact
+}
+
+func (fact BackfillSymbolFact) IsValid(b []byte) error {
+	if err := util.CheckIsValiders(nil, false, fact.TokenFact, fact.symbol); err != nil {
+		return common.ErrFactInvalid.Wrap(err)
+	}
+
+	if err := common.IsValidOperationFact(fact, b); err != nil {
+		return common.ErrFactInvalid.Wrap(err)
+	}
+	return nil
+}
+
+func (fact BackfillSymbolFact) GenerateHash() util.Hash {
+	return valuehash.NewSHA256(fact.Bytes())
+}
+
+func (fact BackfillSymbolFact) Bytes() []byte {
+	return util.ConcatBytesSlice(
+		fact.TokenFact.Bytes(),
+		fact.symbol.Bytes(),
+	)
+}
+
+func (fact BackfillSymbolFact) Symbol() types.TokenSymbol {
+	return fact.symbol
+}
+
+func (fact BackfillSymbolFact) ActiveContract() []base.Address {
+	return []base.Address{fact.contract}
+}
+
+type BackfillSymbol struct {
+	extras.ExtendedOperation
+}
+
+func (op BackfillSymbol) DupKey() (map[ctypes.DuplicationKeyType][]string, error) {
+	r := make(map[ctypes.DuplicationKeyType][]string)
+
+	if err := extras.AddOperationFeePayerDupKeys(r, op); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func NewBackfillSymbol(fact BackfillSymbolFact) BackfillSymbol {
+	return BackfillSymbol{
+		ExtendedOperation: extras.NewExtendedOperation(BackfillSymbolHint, fact),
+	}
+}
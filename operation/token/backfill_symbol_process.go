@@ -0,0 +1,167 @@
+package token
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/imfact-labs/currency-model/common"
+	cstate "github.com/imfact-labs/currency-model/state"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/token-model/state"
+	"github.com/imfact-labs/token-model/utils"
+	"github.com/pkg/errors"
+)
+
+var backfillSymbolProcessorPool = sync.Pool{
+	New: func() interface{} {
+		return new(BackfillSymbolProcessor)
+	},
+}
+
+func (BackfillSymbol) Process(
+	_ context.Context, _ base.GetStateFunc,
+) ([]base.StateMergeValue, base.OperationProcessReasonError, error) {
+	return nil, nil, nil
+}
+
+type BackfillSymbolProcessor struct {
+	*base.BaseOperationProcessor
+	symbolRegistry bool
+}
+
+// NewBackfillSymbolProcessor returns the processor; the entries are backfilled
+// from the registry height.
+func NewBackfillSymbolProcessor(registryHeight base.Height) ctypes.GetNewProcessor {
+	return func(
+		height base.Height,
+		getStateFunc base.GetStateFunc,
+		newPreProcessConstraintFunc base.NewOperationProcessorProcessFunc,
+		newProcessConstraintFunc base.NewOperationProcessorProcessFunc,
+	) (base.OperationProcessor, error) {
+		t := BackfillSymbolProcessor{}
+		e := util.StringError("%s", utils.ErrStringCreate(fmt.Sprintf("new %T", t)))
+
+		nopp := backfillSymbolProcessorPool.Get()
+		opp, ok := nopp.(*BackfillSymbolProcessor)
+		if !ok {
+			return nil, e.Wrap(errors.New(utils.ErrStringTypeCast(&t, nopp)))
+		}
+
+		b, err := base.NewBaseOperationProcessor(
+			height, getStateFunc, newPreProcessConstraintFunc, newProcessConstraintFunc)
+		if err != nil {
+			return nil, e.Wrap(err)
+		}
+
+		opp.BaseOperationProcessor = b
+		opp.symbolRegistry = isSymbolRegistryHeight(registryHeight, height)
+
+		return opp, nil
+	}
+}
+
+func (opp *BackfillSymbolProcessor) PreProcess(
+	ctx context.Context, op base.Operation, getStateFunc base.GetStateFunc,
+) (context.Context, base.OperationProcessReasonError, error) {
+	fact, ok := op.Fact().(BackfillSymbolFact)
+	if !ok {
+		return ctx, base.NewBaseOperationProcessReasonError(
+			"%s",
+			common.ErrMPreProcess.
+				Wrap(common.ErrMTypeMismatch).
+				Errorf("expected %T, not %T", BackfillSymbolFact{}, op.Fact())), nil
+	}
+
+	if err := fact.IsValid(nil); err != nil {
+		return ctx, base.NewBaseOperationProcessReasonError(
+			"%s",
+			common.ErrMPreProcess.
+				Errorf("%v", err)), nil
+	}
+
+	if !opp.symbolRegistry {
+		return ctx, base.NewBaseOperationProcessReasonError(
+			"%s",
+			common.ErrMPreProcess.
+				Errorf("token symbol registry not kept at height %v", opp.Height())), nil
+	}
+
+	g := state.NewStateKeyGenerator(fact.Contract().String())
+
+	st, err := cstate.ExistsState(g.Design(), "design", getStateFunc)
+	if err != nil {
+		return ctx, base.NewBaseOperationProcessReasonError(
+			"%s",
+			common.ErrMPreProcess.
+				Wrap(common.ErrMServiceNF).Errorf("token service state for contract account %v",
+				fact.Contract(),
+			)), nil
+	}
+
+	switch design, err := state.StateDesignValue(st); {
+	case err != nil:
+		return ctx, base.NewBaseOperationProcessReasonError(
+			"%s",
+			common.ErrMPreProcess.
+				Wrap(common.ErrMServiceNF).Errorf("token service state value for contract account %v",
+				fact.Contract(),
+			)), nil
+	case design.Symbol() != fact.Symbol():
+		return ctx, base.NewBaseOperationProcessReasonError(
+			"%s",
+			common.ErrMPreProcess.
+				Wrap(common.ErrMValueInvalid).Errorf("token symbol %v not same with design of contract account %v, %v",
+				fact.Symbol(), fact.Contract(), design.Symbol())), nil
+	}
+
+	// NOTE the released entry is not backfilled; the symbol was registered by
+	// the registry, so the registry already decided the holder.
+	switch _, found, err := getStateFunc(state.StateKeySymbol(fact.Symbol().String())); {
+	case err != nil:
+		return ctx, base.NewBaseOperationProcessReasonError(
+			"%s",
+			common.ErrMPreProcess.
+				Wrap(common.ErrMStateValInvalid).Errorf("token symbol %v: %v", fact.Symbol(), err)), nil
+	case found:
+		return ctx, base.NewBaseOperationProcessReasonError(
+			"%s",
+			common.ErrMPreProcess.
+				Wrap(common.ErrMStateE).Errorf("token symbol %v already in symbol registry", fact.Symbol())), nil
+	}
+
+	if err := state.ReserveSymbol(ctx, fact.Symbol().String()); err != nil {
+		return ctx, base.NewBaseOperationProcessReasonError(
+			"%s",
+			common.ErrMPreProcess.
+				Wrap(common.ErrMStateE).Errorf("%v", err)), nil
+	}
+
+	return ctx, nil, nil
+}
+
+func (opp *BackfillSymbolProcessor) Process(
+	_ context.Context, op base.Operation, _ base.GetStateFunc) (
+	[]base.StateMergeValue, base.OperationProcessReasonError, error,
+) {
+	e := util.StringError("%s", ErrStringProcess(*opp))
+
+	fact, ok := op.Fact().(BackfillSymbolFact)
+	if !ok {
+		return nil, nil, e.Wrap(errors.New(utils.ErrStringTypeCast(BackfillSymbolFact{}, op.Fact())))
+	}
+
+	return []base.StateMergeValue{
+		cstate.NewStateMergeValue(
+			state.StateKeySymbol(fact.Symbol().String()),
+			state.NewSymbolStateValue(fact.Contract(), false),
+		),
+	}, nil, nil
+}
+
+func (opp *BackfillSymbolProcessor) Close() error {
+	backfillSymbolProcessorPool.Put(opp)
+	return nil
+}
@@ -4,11 +4,46 @@ import (
 	"go.mongodb.org/mongo-driver/v2/bson"
 
 	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/currency-model/operation/extras"
 	"github.com/imfact-labs/currency-model/utils/bsonenc"
+	"github.com/imfact-labs/mitum2/base"
 	"github.com/imfact-labs/mitum2/util/hint"
 	"github.com/imfact-labs/mitum2/util/valuehash"
 )
 
+// marshalOperationBSON marshals the operation to be decoded by
+// common.BaseOperation and extras.BaseOperationExtensions; the signs are
+// marshaled with the signature bytes like the digest operation value.
+func marshalOperationBSON(op base.Operation) ([]byte, error) {
+	var signs bson.A
+
+	for i := range op.Signs() {
+		signs = append(signs, bson.M{
+			"signer":    op.Signs()[i].Signer().String(),
+			"signature": op.Signs()[i].Signature(),
+			"signed_at": op.Signs()[i].SignedAt(),
+		})
+	}
+
+	m := bson.M{
+		"_hint": op.Hint().String(),
+		"hash":  op.Hash().String(),
+		"fact":  op.Fact(),
+		"signs": signs,
+	}
+
+	if eo, ok := op.(extras.OperationExtensions); ok {
+		extension := bson.M{}
+		for k, v := range eo.Extensions() {
+			extension[k] = v
+		}
+
+		m["extension"] = extension
+	}
+
+	return bsonenc.Marshal(m)
+}
+
 func (fact TokenFact) marshalMap() map[string]interface{} {
 	return map[string]interface{}{
 		"_hint":    fact.Hint().String(),
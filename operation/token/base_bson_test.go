@@ -0,0 +1,147 @@
+package token
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/imfact-labs/currency-model/common"
+	cdigest "github.com/imfact-labs/currency-model/digest"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/currency-model/utils/bsonenc"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/token-model/types"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// TestOperationMarshalBSON checks the bson of token operations is the
+// document stored by the digest, with the extension, and the signs have the
+// signer to be decoded by common.BaseOperation.
+func TestOperationMarshalBSON(t *testing.T) {
+	priv := base.NewMPrivatekey()
+	networkID := base.NetworkID("bson-test")
+
+	sender := ctypes.NewAddress("0xCE8133c8498F5034296947645dd9E83c9c5288E9")
+	contract := ctypes.NewAddress("0x6DC072838c96e06B4bbc763A8D0687Dc4a22f95E")
+	receiver := ctypes.NewAddress("0x936E736056C2B51702b505B72433C8A305ad2675")
+	cid := ctypes.CurrencyID("MCC")
+	symbol := types.TokenSymbol("BSN")
+	ft := []byte("bson-test")
+
+	ops := []base.Operation{
+		signPropertyOp(networkID, NewRegisterModel(NewRegisterModelFact(
+			ft, sender, contract, cid, symbol, "bson token", common.NewBig(0), common.NewBig(100),
+		)), priv),
+		signPropertyOp(networkID, NewMint(NewMintFact(
+			ft, sender, contract, cid, receiver, common.NewBig(10),
+		)), priv),
+		signPropertyOp(networkID, NewBurn(NewBurnFact(
+			ft, sender, contract, cid, sender, common.NewBig(10),
+		)), priv),
+		signPropertyOp(networkID, NewApprove(NewApproveFact(ft, sender, []ApproveItem{
+			NewApproveItem(contract, receiver, common.NewBig(10)),
+		}, cid)), priv),
+		signPropertyOp(networkID, NewTransfer(NewTransferFact(ft, sender, []TransferItem{
+			NewTransferItem(contract, receiver, common.NewBig(10)),
+		}, cid)), priv),
+		signPropertyOp(networkID, NewTransferFrom(NewTransferFromFact(ft, receiver, []TransferFromItem{
+			NewTransferFromItem(contract, receiver, sender, common.NewBig(10)),
+		}, cid)), priv),
+		signPropertyOp(networkID, NewReleaseSymbol(NewReleaseSymbolFact(
+			ft, sender, contract, cid, symbol,
+		)), priv),
+		signPropertyOp(networkID, NewBackfillSymbol(NewBackfillSymbolFact(
+			ft, sender, contract, cid, symbol,
+		)), priv),
+	}
+
+	for i := range ops {
+		op := ops[i]
+
+		t.Run(op.Hint().String(), func(t *testing.T) {
+			b, err := bsonenc.Marshal(op)
+			if err != nil {
+				t.Fatalf("marshal: %v", err)
+			}
+
+			var m bson.M
+			if err := bson.Unmarshal(b, &m); err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+
+			// NOTE the digest omits the empty extension.
+			if _, found := m["extension"]; !found {
+				t.Fatal("extension not found")
+			}
+
+			delete(m, "extension")
+
+			var ubo common.BaseOperationBSONUnmarshaler
+			if err := bson.Unmarshal(b, &ubo); err != nil {
+				t.Fatalf("unmarshal operation: %v", err)
+			}
+
+			if len(ubo.Signs) != 1 {
+				t.Fatalf("expected 1 sign, got %d", len(ubo.Signs))
+			}
+
+			var us common.BaseSignBSONUnmarshaler
+			if err := bson.Unmarshal(ubo.Signs[0], &us); err != nil {
+				t.Fatalf("unmarshal sign: %v", err)
+			}
+
+			if us.Signer != priv.Publickey().String() {
+				t.Fatalf("signer, expected %v, got %q", priv.Publickey(), us.Signer)
+			}
+
+			vb, err := bsonenc.Marshal(cdigest.NewOperationValue(op, base.Height(1), time.Now(), true, "", 0, nil))
+			if err != nil {
+				t.Fatalf("marshal operation value: %v", err)
+			}
+
+			var u struct {
+				OP bson.M `bson:"op"`
+			}
+
+			if err := bson.Unmarshal(vb, &u); err != nil {
+				t.Fatalf("unmarshal operation value: %v", err)
+			}
+
+			delete(u.OP, "extension")
+
+			if !reflect.DeepEqual(normalizeTestBSON(m), normalizeTestBSON(u.OP)) {
+				t.Fatalf("not same with digest operation; operation=%v digest=%v", m, u.OP)
+			}
+		})
+	}
+}
+
+// normalizeTestBSON converts the documents to maps; the keys of bson
+// marshaled from map are not ordered.
+func normalizeTestBSON(i interface{}) interface{} {
+	switch t := i.(type) {
+	case bson.M:
+		m := map[string]interface{}{}
+		for k, v := range t {
+			m[k] = normalizeTestBSON(v)
+		}
+
+		return m
+	case bson.D:
+		m := map[string]interface{}{}
+		for j := range t {
+			m[t[j].Key] = normalizeTestBSON(t[j].Value)
+		}
+
+		return m
+	case bson.A:
+		a := make([]interface{}, len(t))
+		for j := range t {
+			a[j] = normalizeTestBSON(t[j])
+		}
+
+		return a
+	default:
+		return i
+	}
+}
@@ -38,6 +38,10 @@ func (fact *BurnFact) DecodeBSON(b []byte, enc *bsonenc.Encoder) error {
 	return nil
 }
 
+func (op Burn) MarshalBSON() ([]byte, error) {
+	return marshalOperationBSON(op)
+}
+
 func (op *Burn) DecodeBSON(b []byte, enc *bsonenc.Encoder) error {
 	var ubo common.BaseOperation
 	if err := ubo.DecodeBSON(b, enc); err != nil {
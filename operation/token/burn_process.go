@@ -30,9 +30,12 @@ func (Burn) Process(
 
 type BurnProcessor struct {
 	*base.BaseOperationProcessor
+	canonical bool
 }
 
-func NewBurnProcessor() ctypes.GetNewProcessor {
+// NewBurnProcessor returns the processor, which writes the canonical policy
+// from the canonical approve list height.
+func NewBurnProcessor(canonicalHeight base.Height) ctypes.GetNewProcessor {
 	return func(
 		height base.Height,
 		getStateFunc base.GetStateFunc,
@@ -55,6 +58,7 @@ func NewBurnProcessor() ctypes.GetNewProcessor {
 		}
 
 		opp.BaseOperationProcessor = b
+		opp.canonical = isCanonicalApproveListHeight(canonicalHeight, height)
 
 		return opp, nil
 	}
@@ -112,6 +116,13 @@ func (opp *BurnProcessor) PreProcess(
 					fact.Target(), fact.Contract(), tb, fact.Amount())), nil
 	}
 
+	if err := state.ReserveTokenBalances(ctx, map[string]common.Big{
+		g.TokenBalance(fact.Target().String()): fact.Amount(),
+	}, getStateFunc); err != nil {
+		return ctx, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.Wrap(common.ErrMValueInvalid).Errorf("%v", err)), nil
+	}
+
 	return ctx, nil, nil
 }
 
@@ -128,7 +139,8 @@ func (opp *BurnProcessor) Process(
 	st, _ := cstate.ExistsState(g.Design(), "design", getStateFunc)
 	design, _ := state.StateDesignValue(st)
 
-	policy := types.NewPolicy(
+	policy := newPolicy(
+		opp.canonical,
 		design.Policy().TotalSupply().Sub(fact.Amount()),
 		design.Policy().ApproveList(),
 	)
@@ -141,10 +153,7 @@ func (opp *BurnProcessor) Process(
 		return nil, ErrInvalid(de, err), nil
 	}
 
-	sts = append(sts, cstate.NewStateMergeValue(
-		g.Design(),
-		state.NewDesignStateValue(de),
-	))
+	sts = append(sts, state.NewDesignStateMergeValue(g.Design(), de))
 
 	st, err := cstate.ExistsState(g.TokenBalance(fact.Target().String()), "token balance", getStateFunc)
 	if err != nil {
@@ -0,0 +1,43 @@
+package token
+
+import (
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/token-model/types"
+)
+
+// CanonicalApproveListHeightContextKey is the canonical approve list height
+// for RegisterGenesisToken, which is processed without the processor.
+var CanonicalApproveListHeightContextKey = util.ContextKey("token-canonical-approve-list-height")
+
+// isCanonicalApproveListHeight tells whether the design is written with the
+// canonical policy at the height. base.NilHeight keeps writing the legacy
+// policy, so the existing networks process the blocks in the same way until
+// every node agrees on the height.
+func isCanonicalApproveListHeight(canonicalHeight, height base.Height) bool {
+	return canonicalHeight > base.NilHeight && height >= canonicalHeight
+}
+
+// newPolicy returns the policy written at the height; from the canonical
+// approve list height, the policy is migrated by Canonical, and before it,
+// the policy is legacy and keeps the order of approve list, like the older
+// nodes write.
+func newPolicy(canonical bool, totalSupply common.Big, approveList []types.ApproveBox) types.Policy {
+	policy := types.NewLegacyPolicy(totalSupply, approveList)
+	if canonical {
+		return policy.Canonical()
+	}
+
+	return policy.Legacy()
+}
+
+// designOfHeight returns the design with the policy written at the height.
+func designOfHeight(canonical bool, design types.Design) types.Design {
+	return types.NewDesign(
+		design.Symbol(),
+		design.Name(),
+		design.Decimal(),
+		newPolicy(canonical, design.Policy().TotalSupply(), design.Policy().ApproveList()),
+	)
+}
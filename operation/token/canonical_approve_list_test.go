@@ -0,0 +1,99 @@
+package token
+
+import (
+	"context"
+	"testing"
+
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/currency-model/operation/test"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/token-model/state"
+	"github.com/imfact-labs/token-model/types"
+)
+
+func TestApproveCanonicalApproveListHeight(t *testing.T) {
+	cases := []struct {
+		name            string
+		canonicalHeight base.Height
+		canonical       bool
+	}{
+		{name: "legacy kept", canonicalHeight: base.NilHeight},
+		{name: "below canonical height", canonicalHeight: base.Height(2)},
+		{name: "canonical height", canonicalHeight: base.Height(1), canonical: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			tp := &test.TestProcessor{}
+			tp.Setup(test.NewMockStateGetter())
+
+			a := newTestTransferAccounts(tp)
+
+			// NOTE the existing legacy policy is stored by the older nodes.
+			NewTestDesignState(tp, a.contract.Address(), types.NewDesign(
+				types.TokenSymbol("ABC"), "abc", common.NewBig(0),
+				types.NewLegacyPolicy(common.NewBig(100), []types.ApproveBox{
+					types.NewLegacyApproveBox(a.receiver.Address(), []types.ApproveInfo{
+						types.NewApproveInfo(a.sender.Address(), common.NewBig(5)),
+					}),
+				}),
+			), true)
+			NewTestTokenBalanceState(tp, a.contract.Address(), a.sender.Address(), 100, true)
+
+			op := NewApprove(NewApproveFact([]byte("token"), a.sender.Address(), []ApproveItem{
+				NewApproveItem(a.contract.Address(), a.receiver.Address(), common.NewBig(10)),
+			}, a.currency))
+			_ = op.Sign(a.sender.Priv(), tp.NetworkID)
+
+			stvs, err := runTestSymbolProcessor(t, context.Background(), tp,
+				NewApproveProcessor(c.canonicalHeight), op)
+			if err != nil {
+				t.Fatalf("approve: %v", err)
+			}
+
+			var design *types.Design
+
+			for i := range stvs {
+				if v, ok := stvs[i].Value().(state.DesignStateValue); ok {
+					d := v.Design()
+					design = &d
+				}
+			}
+
+			if design == nil {
+				t.Fatal("design not written")
+			}
+
+			policy := design.Policy()
+
+			if err := policy.IsValid(nil); err != nil {
+				t.Fatalf("invalid policy: %v", err)
+			}
+
+			if c.canonical {
+				if policy.IsLegacy() {
+					t.Fatal("legacy policy not migrated at canonical height")
+				}
+
+				return
+			}
+
+			if !policy.IsLegacy() {
+				t.Fatal("legacy policy migrated below canonical height")
+			}
+
+			// NOTE the approve box of sender is appended to the legacy
+			// approve list.
+			l := policy.ApproveList()
+			if len(l) != 2 || !l[0].Account().Equal(a.receiver.Address()) || !l[1].Account().Equal(a.sender.Address()) {
+				t.Fatalf("order of legacy approve list not kept, %v", l)
+			}
+
+			for i := range l {
+				if !l[i].IsLegacy() {
+					t.Fatalf("canonical approve box in legacy policy, %v", l[i].Account())
+				}
+			}
+		})
+	}
+}
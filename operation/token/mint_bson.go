@@ -38,6 +38,10 @@ func (fact *MintFact) DecodeBSON(b []byte, enc *bsonenc.Encoder) error {
 	return nil
 }
 
+func (op Mint) MarshalBSON() ([]byte, error) {
+	return marshalOperationBSON(op)
+}
+
 func (op *Mint) DecodeBSON(b []byte, enc *bsonenc.Encoder) error {
 	var ubo common.BaseOperation
 	if err := ubo.DecodeBSON(b, enc); err != nil {
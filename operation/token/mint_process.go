@@ -30,9 +30,12 @@ func (Mint) Process(
 
 type MintProcessor struct {
 	*base.BaseOperationProcessor
+	canonical bool
 }
 
-func NewMintProcessor() ctypes.GetNewProcessor {
+// NewMintProcessor returns the processor, which writes the canonical policy
+// from the canonical approve list height.
+func NewMintProcessor(canonicalHeight base.Height) ctypes.GetNewProcessor {
 	return func(
 		height base.Height,
 		getStateFunc base.GetStateFunc,
@@ -55,6 +58,7 @@ func NewMintProcessor() ctypes.GetNewProcessor {
 		}
 
 		opp.BaseOperationProcessor = b
+		opp.canonical = isCanonicalApproveListHeight(canonicalHeight, height)
 
 		return opp, nil
 	}
@@ -116,7 +120,8 @@ func (opp *MintProcessor) Process(
 	st, _ := cstate.ExistsState(g.Design(), "design", getStateFunc)
 	design, _ := state.StateDesignValue(st)
 
-	policy := types.NewPolicy(
+	policy := newPolicy(
+		opp.canonical,
 		design.Policy().TotalSupply().Add(fact.Amount()),
 		design.Policy().ApproveList(),
 	)
@@ -129,10 +134,7 @@ func (opp *MintProcessor) Process(
 		return nil, ErrInvalid(de, err), nil
 	}
 
-	sts = append(sts, cstate.NewStateMergeValue(
-		g.Design(),
-		state.NewDesignStateValue(de),
-	))
+	sts = append(sts, state.NewDesignStateMergeValue(g.Design(), de))
 
 	smv, err := cstate.CreateNotExistAccount(fact.Receiver(), getStateFunc)
 	if err != nil {
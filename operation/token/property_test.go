@@ -0,0 +1,564 @@
+package token
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/currency-model/operation/extras"
+	"github.com/imfact-labs/currency-model/operation/test"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/token-model/state"
+	"github.com/imfact-labs/token-model/types"
+)
+
+const (
+	propertyAccounts  = 4
+	propertyContracts = 2
+	propertyBlocks    = 40
+)
+
+// propertyWorld runs the token operations by blocks like the proposal
+// processor; the operations of block see the states of the last block and
+// their values are merged by the mergers of state at the end of block. The
+// model keeps the expected balances, total supplies and allowances of the
+// processed operations.
+type propertyWorld struct {
+	tp        *test.TestProcessor
+	r         *rand.Rand
+	accounts  []test.Account
+	contracts []base.Address
+	owners    []int
+	height    base.Height
+	ops       int
+	supplies  map[string]int64
+	balances  map[string]map[string]int64
+	approved  map[string]map[string]map[string]int64
+}
+
+func newPropertyWorld(seed int64) *propertyWorld {
+	tp := &test.TestProcessor{}
+	tp.Setup(test.NewMockStateGetter())
+
+	w := &propertyWorld{
+		tp:        tp,
+		r:         rand.New(rand.NewSource(seed)), //nolint:gosec //...
+		accounts:  make([]test.Account, propertyAccounts),
+		contracts: make([]base.Address, propertyContracts),
+		owners:    make([]int, propertyContracts),
+		height:    base.Height(1),
+		supplies:  map[string]int64{},
+		balances:  map[string]map[string]int64{},
+		approved:  map[string]map[string]map[string]int64{},
+	}
+
+	for i := range w.accounts {
+		accounts := make([]test.Account, 1)
+		tp.SetAccount(tp.NewPrivateKey(fmt.Sprintf("property-account-%d", i)), 1000, tp.GenesisCurrency, accounts, true)
+		w.accounts[i] = accounts[0]
+	}
+
+	for i := range w.contracts {
+		w.owners[i] = i % propertyAccounts
+		w.contracts[i], _ = tp.NewTestContractAccountState(
+			w.accounts[w.owners[i]].Address(), tp.NewPrivateKey(fmt.Sprintf("property-contract-%d", i)), true)
+	}
+
+	return w
+}
+
+// propertyOp is the generated operation with the model change applied when
+// the operation is processed.
+type propertyOp struct {
+	op     base.Operation
+	newOpp ctypes.GetNewProcessor
+	// grant is the contract and owner of the allowances increased by approve.
+	grant string
+	apply func()
+}
+
+func (w *propertyWorld) runBlock(t *testing.T) {
+	t.Helper()
+
+	w.height++
+
+	var ops []propertyOp
+
+	dupKeys := map[string]bool{}
+
+	for n := w.r.Intn(5) + 1; len(ops) < n; {
+		o := w.newOp()
+
+		// NOTE like the duplication check of proposal, the operations with
+		// same duplication key are not in the same block.
+		keys := propertyDupKeys(t, o.op)
+		if hasPropertyDupKey(dupKeys, keys) {
+			continue
+		}
+
+		for i := range keys {
+			dupKeys[keys[i]] = true
+		}
+
+		ops = append(ops, o)
+	}
+
+	ctx := context.WithValue(context.Background(), state.TokenBalanceReservationContextKey,
+		state.NewTokenBalanceReservation())
+
+	var values []base.StateMergeValue
+	var facts []base.Operation
+
+	last := w.stateAllowances(t)
+	grants := map[string]bool{}
+
+	for i := range ops {
+		if err := ops[i].op.IsValid(w.tp.NetworkID); err != nil {
+			continue
+		}
+
+		opp, err := ops[i].newOpp(w.height, w.tp.GetStateFunc, nil, nil)
+		if err != nil {
+			t.Fatalf("new processor: %v", err)
+		}
+
+		nctx, reason, err := opp.PreProcess(ctx, ops[i].op, w.tp.GetStateFunc)
+		switch {
+		case err != nil:
+			t.Fatalf("preprocess %T: %v", ops[i].op, err)
+		case reason != nil:
+			continue
+		}
+
+		ctx = nctx
+
+		stvs, reason, err := opp.Process(ctx, ops[i].op, w.tp.GetStateFunc)
+		switch {
+		case err != nil:
+			t.Fatalf("process %T: %v", ops[i].op, err)
+		case reason != nil:
+			continue
+		}
+
+		for range stvs {
+			facts = append(facts, ops[i].op)
+		}
+
+		values = append(values, stvs...)
+		ops[i].apply()
+		w.ops++
+
+		if len(ops[i].grant) > 0 {
+			grants[ops[i].grant] = true
+		}
+	}
+
+	w.merge(t, values, facts)
+	w.check(t)
+	w.checkAllowanceMonotonic(t, last, grants)
+}
+
+func propertyAllowanceKey(contract, owner string) string {
+	return contract + "/" + owner
+}
+
+// stateAllowances returns the allowances of the design states by contract,
+// owner and spender.
+func (w *propertyWorld) stateAllowances(t *testing.T) map[string]map[string]common.Big {
+	t.Helper()
+
+	allowances := map[string]map[string]common.Big{}
+
+	for _, contract := range w.contracts {
+		c := contract.String()
+
+		st, found, _ := w.tp.GetStateFunc(state.StateKeyDesign(c))
+		if !found {
+			continue
+		}
+
+		design, err := state.StateDesignValue(st)
+		if err != nil {
+			t.Fatalf("height=%d: design of %v: %v", w.height, c, err)
+		}
+
+		for _, box := range design.Policy().ApproveList() {
+			key := propertyAllowanceKey(c, box.Account().String())
+			allowances[key] = map[string]common.Big{}
+
+			for _, info := range box.Approved() {
+				allowances[key][info.Account().String()] = info.Amount()
+			}
+		}
+	}
+
+	return allowances
+}
+
+// checkAllowanceMonotonic checks the allowances are not increased or newly
+// added without approve of the owner; transfer-from only decreases them.
+func (w *propertyWorld) checkAllowanceMonotonic(
+	t *testing.T, last map[string]map[string]common.Big, grants map[string]bool,
+) {
+	t.Helper()
+
+	for key, spenders := range w.stateAllowances(t) {
+		if grants[key] {
+			continue
+		}
+
+		for spender, amount := range spenders {
+			prev, found := last[key][spender]
+
+			switch {
+			case !found:
+				t.Fatalf("height=%d: allowance of %v in %v added without approve", w.height, spender, key)
+			case amount.Compare(prev) > 0:
+				t.Fatalf("height=%d: allowance of %v in %v increased without approve, %v -> %v",
+					w.height, spender, key, prev, amount)
+			}
+		}
+	}
+}
+
+// merge merges the values by key with the merger of the first value, like
+// the states merger of block writer. The states merger merges the values
+// concurrently, so the values are merged in random order.
+func (w *propertyWorld) merge(t *testing.T, values []base.StateMergeValue, ops []base.Operation) {
+	t.Helper()
+
+	var keys []string
+
+	mergers := map[string]base.StateValueMerger{}
+
+	for _, i := range w.r.Perm(len(values)) {
+		key := values[i].Key()
+
+		m, found := mergers[key]
+		if !found {
+			st, _, _ := w.tp.GetStateFunc(key)
+			m = values[i].Merger(w.height, st)
+			mergers[key] = m
+
+			keys = append(keys, key)
+		}
+
+		if err := m.Merge(values[i].Value(), ops[i].Fact().Hash()); err != nil {
+			t.Fatalf("merge %v: %v", key, err)
+		}
+	}
+
+	for i := range keys {
+		st, err := mergers[keys[i]].CloseValue()
+		if err != nil {
+			t.Fatalf("close %v: %v", keys[i], err)
+		}
+
+		w.tp.SetState(st, true)
+	}
+}
+
+func (w *propertyWorld) check(t *testing.T) {
+	t.Helper()
+
+	for _, contract := range w.contracts {
+		c := contract.String()
+
+		st, found, _ := w.tp.GetStateFunc(state.StateKeyDesign(c))
+		if !found {
+			if _, registered := w.supplies[c]; registered {
+				t.Fatalf("height=%d: design of %v not found", w.height, c)
+			}
+
+			continue
+		}
+
+		design, err := state.StateDesignValue(st)
+		if err != nil {
+			t.Fatalf("height=%d: design of %v: %v", w.height, c, err)
+		}
+
+		sum := common.ZeroBig
+
+		for _, a := range w.accounts {
+			h := a.Address().String()
+			amount := common.ZeroBig
+
+			if st, found, _ := w.tp.GetStateFunc(state.StateKeyTokenBalance(c, h)); found {
+				if amount, err = state.StateTokenBalanceValue(st); err != nil {
+					t.Fatalf("height=%d: token balance of %v: %v", w.height, h, err)
+				}
+			}
+
+			if expected := common.NewBig(w.balances[c][h]); !amount.Equal(expected) {
+				t.Fatalf("height=%d: token balance of %v in %v, expected %v, got %v",
+					w.height, h, c, expected, amount)
+			}
+
+			sum = sum.Add(amount)
+		}
+
+		if ts := design.Policy().TotalSupply(); !ts.Equal(sum) {
+			t.Fatalf("height=%d: supply not conserved in %v; total supply=%v balances=%v", w.height, c, ts, sum)
+		} else if expected := common.NewBig(w.supplies[c]); !ts.Equal(expected) {
+			t.Fatalf("height=%d: total supply of %v, expected %v, got %v", w.height, c, expected, ts)
+		}
+
+		w.checkAllowances(t, c, *design)
+	}
+}
+
+func (w *propertyWorld) checkAllowances(t *testing.T, contract string, design types.Design) {
+	t.Helper()
+
+	found := 0
+
+	for _, box := range design.Policy().ApproveList() {
+		for _, info := range box.Approved() {
+			owner, spender := box.Account().String(), info.Account().String()
+
+			if expected := common.NewBig(w.approved[contract][owner][spender]); !info.Amount().Equal(expected) {
+				t.Fatalf("height=%d: allowance of %v by %v in %v, expected %v, got %v",
+					w.height, spender, owner, contract, expected, info.Amount())
+			}
+
+			found++
+		}
+	}
+
+	expected := 0
+
+	for _, spenders := range w.approved[contract] {
+		expected += len(spenders)
+	}
+
+	if found != expected {
+		t.Fatalf("height=%d: allowances in %v, expected %d, got %d", w.height, contract, expected, found)
+	}
+}
+
+func (w *propertyWorld) newOp() propertyOp {
+	ci := w.r.Intn(len(w.contracts))
+	contract := w.contracts[ci]
+	c := contract.String()
+	owner := w.accounts[w.owners[ci]]
+	sender := w.accounts[w.r.Intn(len(w.accounts))]
+	other := w.accounts[w.r.Intn(len(w.accounts))]
+	amount := int64(w.r.Intn(60))
+	token := []byte(fmt.Sprintf("property-%d-%d", w.height, w.r.Int63()))
+	cid := w.tp.GenesisCurrency
+
+	switch w.r.Intn(6) {
+	case 0:
+		return propertyOp{
+			op: signPropertyOp(w.tp.NetworkID, NewRegisterModel(NewRegisterModelFact(token, owner.Address(), contract, cid,
+				types.TokenSymbol(fmt.Sprintf("PRP%c", 'A'+ci)), "property", common.NewBig(0), common.NewBig(amount),
+			)), owner.Priv()),
+			newOpp: NewRegisterModelProcessor(base.GenesisHeight, base.GenesisHeight),
+			apply: func() {
+				w.supplies[c] = amount
+				w.addBalance(c, owner.Address().String(), amount)
+			},
+		}
+	case 1:
+		return propertyOp{
+			op: signPropertyOp(w.tp.NetworkID, NewMint(NewMintFact(token, owner.Address(), contract, cid, other.Address(),
+				common.NewBig(amount))), owner.Priv()),
+			newOpp: NewMintProcessor(base.GenesisHeight),
+			apply: func() {
+				w.supplies[c] += amount
+				w.addBalance(c, other.Address().String(), amount)
+			},
+		}
+	case 2:
+		return propertyOp{
+			op: signPropertyOp(w.tp.NetworkID, NewBurn(NewBurnFact(token, sender.Address(), contract, cid, sender.Address(),
+				common.NewBig(amount))), sender.Priv()),
+			newOpp: NewBurnProcessor(base.GenesisHeight),
+			apply: func() {
+				w.supplies[c] -= amount
+				w.addBalance(c, sender.Address().String(), -amount)
+			},
+		}
+	case 3:
+		return propertyOp{
+			op: signPropertyOp(w.tp.NetworkID, NewApprove(NewApproveFact(token, sender.Address(), []ApproveItem{
+				NewApproveItem(contract, other.Address(), common.NewBig(amount)),
+			}, cid)), sender.Priv()),
+			newOpp: NewApproveProcessor(base.GenesisHeight),
+			grant:  propertyAllowanceKey(c, sender.Address().String()),
+			apply: func() {
+				w.approve(c, sender.Address().String(), other.Address().String(), amount)
+			},
+		}
+	case 4:
+		return propertyOp{
+			op: signPropertyOp(w.tp.NetworkID, NewTransfer(NewTransferFact(token, sender.Address(), []TransferItem{
+				NewTransferItem(contract, other.Address(), common.NewBig(amount)),
+			}, cid)), sender.Priv()),
+			newOpp: NewTransferProcessor(),
+			apply: func() {
+				w.addBalance(c, sender.Address().String(), -amount)
+				w.addBalance(c, other.Address().String(), amount)
+			},
+		}
+	default:
+		target := w.accounts[w.r.Intn(len(w.accounts))]
+
+		return propertyOp{
+			op: signPropertyOp(w.tp.NetworkID, NewTransferFrom(NewTransferFromFact(token, sender.Address(), []TransferFromItem{
+				NewTransferFromItem(contract, other.Address(), target.Address(), common.NewBig(amount)),
+			}, cid)), sender.Priv()),
+			newOpp: NewTransferFromProcessor(base.GenesisHeight),
+			apply: func() {
+				w.approve(c, target.Address().String(), sender.Address().String(), -amount)
+				w.addBalance(c, target.Address().String(), -amount)
+				w.addBalance(c, other.Address().String(), amount)
+			},
+		}
+	}
+}
+
+func propertyDupKeys(t *testing.T, op base.Operation) []string {
+	t.Helper()
+
+	var keys []string
+
+	add := func(k extras.DeDupeKeyer) {
+		m, err := k.DupKey()
+		if err != nil {
+			t.Fatalf("dup key of %T: %v", op, err)
+		}
+
+		for kt, v := range m {
+			for i := range v {
+				keys = append(keys, fmt.Sprintf("%v:%s", kt, v[i]))
+			}
+		}
+	}
+
+	if k, ok := op.Fact().(extras.DeDupeKeyer); ok {
+		add(k)
+	}
+
+	if k, ok := op.(extras.DeDupeKeyer); ok {
+		add(k)
+	}
+
+	return keys
+}
+
+func hasPropertyDupKey(used map[string]bool, keys []string) bool {
+	for i := range keys {
+		if used[keys[i]] {
+			return true
+		}
+	}
+
+	return false
+}
+
+func signPropertyOp[T any, PT interface {
+	*T
+	Sign(base.Privatekey, base.NetworkID) error
+}](networkID base.NetworkID, op T, priv base.Privatekey) base.Operation {
+	_ = PT(&op).Sign(priv, networkID)
+
+	return any(op).(base.Operation) //nolint:forcetypeassert //...
+}
+
+func (w *propertyWorld) addBalance(contract, holder string, amount int64) {
+	if _, found := w.balances[contract]; !found {
+		w.balances[contract] = map[string]int64{}
+	}
+
+	w.balances[contract][holder] += amount
+}
+
+// approve adds amount to the allowance; zero approve amount removes the
+// allowance and the allowance used up by transfer-from is removed. Like the
+// design merger, the allowance removed and used in the same block is removed
+// without becoming negative.
+func (w *propertyWorld) approve(contract, owner, spender string, amount int64) {
+	if _, found := w.approved[contract]; !found {
+		w.approved[contract] = map[string]map[string]int64{}
+	}
+
+	if _, found := w.approved[contract][owner]; !found {
+		w.approved[contract][owner] = map[string]int64{}
+	}
+
+	spenders := w.approved[contract][owner]
+
+	if amount == 0 {
+		delete(spenders, spender)
+
+		return
+	}
+
+	if spenders[spender] += amount; spenders[spender] <= 0 {
+		delete(spenders, spender)
+	}
+}
+
+func (w *propertyWorld) checkModel(t *testing.T) {
+	t.Helper()
+
+	for c, holders := range w.balances {
+		for h, amount := range holders {
+			if amount < 0 {
+				t.Fatalf("negative token balance of %v in %v, %d", h, c, amount)
+			}
+		}
+	}
+
+	for c, owners := range w.approved {
+		for o, spenders := range owners {
+			for s, amount := range spenders {
+				if amount < 0 {
+					t.Fatalf("negative allowance of %v by %v in %v, %d", s, o, c, amount)
+				}
+			}
+		}
+	}
+}
+
+func runPropertyWorld(t *testing.T, seed int64) *propertyWorld {
+	t.Helper()
+
+	w := newPropertyWorld(seed)
+
+	for i := 0; i < propertyBlocks; i++ {
+		w.runBlock(t)
+		w.checkModel(t)
+	}
+
+	return w
+}
+
+func TestTokenOperationsProperty(t *testing.T) {
+	var ops int
+
+	for seed := int64(0); seed < 30; seed++ {
+		t.Run(fmt.Sprintf("seed-%d", seed), func(t *testing.T) {
+			ops += runPropertyWorld(t, seed).ops
+		})
+	}
+
+	if ops < 1 {
+		t.Fatal("no operation processed")
+	}
+}
+
+func FuzzTokenOperations(f *testing.F) {
+	for _, seed := range []int64{1, 7, 42, 1 << 40} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, seed int64) {
+		runPropertyWorld(t, seed)
+	})
+}
@@ -0,0 +1,247 @@
+package token
+
+import (
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/hint"
+	"github.com/imfact-labs/mitum2/util/valuehash"
+	"github.com/imfact-labs/token-model/types"
+	"github.com/pkg/errors"
+)
+
+var (
+	RegisterGenesisTokenFactHint = hint.MustNewHint("mitum-token-register-genesis-token-operation-fact-v0.0.1")
+	RegisterGenesisTokenHint     = hint.MustNewHint("mitum-token-register-genesis-token-operation-v0.0.1")
+)
+
+type GenesisBalance struct {
+	address base.Address
+	amount  common.Big
+}
+
+func NewGenesisBalance(address base.Address, amount common.Big) GenesisBalance {
+	return GenesisBalance{address: address, amount: amount}
+}
+
+func (gb GenesisBalance) IsValid([]byte) error {
+	if err := util.CheckIsValiders(nil, false, gb.address); err != nil {
+		return err
+	}
+
+	if !gb.amount.OverNil() {
+		return common.ErrValOOR.Wrap(
+			errors.Errorf("genesis balance must be bigger than or equal to zero, got %v of %v", gb.amount, gb.address))
+	}
+
+	return nil
+}
+
+func (gb GenesisBalance) Bytes() []byte {
+	return util.ConcatBytesSlice(gb.address.Bytes(), gb.amount.Bytes())
+}
+
+func (gb GenesisBalance) Address() base.Address {
+	return gb.address
+}
+
+func (gb GenesisBalance) Amount() common.Big {
+	return gb.amount
+}
+
+// RegisterGenesisTokenFact registers the token model of contract with the
+// initial balances and allowances in genesis block; the contract account is
+// created with the owner and the total supply is the sum of balances.
+type RegisterGenesisTokenFact struct {
+	base.BaseFact
+	genesisNodeKey base.Publickey
+	owner          base.Address
+	contract       base.Address
+	symbol         types.TokenSymbol
+	name           string
+	decimal        common.Big
+	balances       []GenesisBalance
+	approveList    []types.ApproveBox
+}
+
+func NewRegisterGenesisTokenFact(
+	token []byte,
+	genesisNodeKey base.Publickey,
+	owner, contract base.Address,
+	symbol types.TokenSymbol,
+	name string,
+	decimal common.Big,
+	balances []GenesisBalance,
+	approveList []types.ApproveBox,
+) RegisterGenesisTokenFact {
+	fact := RegisterGenesisTokenFact{
+		BaseFact:       base.NewBaseFact(RegisterGenesisTokenFactHint, token),
+		genesisNodeKey: genesisNodeKey,
+		owner:          owner,
+		contract:       contract,
+		symbol:         symbol,
+		name:           name,
+		decimal:        decimal,
+		balances:       balances,
+		approveList:    approveList,
+	}
+	fact.SetHash(fact.GenerateHash())
+
+	return fact
+}
+
+func (fact RegisterGenesisTokenFact) IsValid(b []byte) error {
+	if err := util.CheckIsValiders(nil, false,
+		fact.BaseHinter, fact.genesisNodeKey, fact.owner, fact.contract, fact.symbol); err != nil {
+		return common.ErrFactInvalid.Wrap(err)
+	}
+
+	if fact.owner.Equal(fact.contract) {
+		return common.ErrFactInvalid.Wrap(
+			common.ErrSelfTarget.Wrap(errors.Errorf("owner %v is same with contract account", fact.owner)))
+	}
+
+	if fact.name == "" {
+		return common.ErrFactInvalid.Wrap(common.ErrValueInvalid.Wrap(errors.Errorf("empty name")))
+	}
+
+	if !fact.decimal.OverNil() {
+		return common.ErrFactInvalid.Wrap(
+			common.ErrValOOR.Wrap(
+				errors.Errorf("decimal must be bigger than or equal to zero, got %v", fact.decimal)))
+	}
+
+	founds := map[string]struct{}{}
+	for i := range fact.balances {
+		if err := fact.balances[i].IsValid(nil); err != nil {
+			return common.ErrFactInvalid.Wrap(err)
+		}
+
+		k := fact.balances[i].address.String()
+		if _, found := founds[k]; found {
+			return common.ErrFactInvalid.Wrap(common.ErrDupVal.Wrap(errors.Errorf("genesis balance of %v", k)))
+		}
+
+		founds[k] = struct{}{}
+	}
+
+	approvers := map[string]struct{}{}
+	for i := range fact.approveList {
+		if err := fact.approveList[i].IsValid(nil); err != nil {
+			return common.ErrFactInvalid.Wrap(err)
+		}
+
+		k := fact.approveList[i].Account().String()
+		if _, found := approvers[k]; found {
+			return common.ErrFactInvalid.Wrap(common.ErrDupVal.Wrap(errors.Errorf("genesis approve box of %v", k)))
+		}
+
+		if _, found := founds[k]; !found {
+			return common.ErrFactInvalid.Wrap(
+				common.ErrValueInvalid.Wrap(errors.Errorf("genesis balance of approve box account %v not found", k)))
+		}
+
+		approvers[k] = struct{}{}
+	}
+
+	if err := common.IsValidOperationFact(fact, b); err != nil {
+		return common.ErrFactInvalid.Wrap(err)
+	}
+
+	return nil
+}
+
+func (fact RegisterGenesisTokenFact) GenerateHash() util.Hash {
+	return valuehash.NewSHA256(fact.Bytes())
+}
+
+func (fact RegisterGenesisTokenFact) Bytes() []byte {
+	bs := make([][]byte, len(fact.balances)+len(fact.approveList)+7)
+	bs[0] = fact.Token()
+	bs[1] = []byte(fact.genesisNodeKey.String())
+	bs[2] = fact.owner.Bytes()
+	bs[3] = fact.contract.Bytes()
+	bs[4] = fact.symbol.Bytes()
+	bs[5] = []byte(fact.name)
+	bs[6] = fact.decimal.Bytes()
+
+	for i := range fact.balances {
+		bs[i+7] = fact.balances[i].Bytes()
+	}
+
+	for i := range fact.approveList {
+		bs[i+len(fact.balances)+7] = fact.approveList[i].Bytes()
+	}
+
+	return util.ConcatBytesSlice(bs...)
+}
+
+func (fact RegisterGenesisTokenFact) GenesisNodeKey() base.Publickey {
+	return fact.genesisNodeKey
+}
+
+func (fact RegisterGenesisTokenFact) Owner() base.Address {
+	return fact.owner
+}
+
+func (fact RegisterGenesisTokenFact) Contract() base.Address {
+	return fact.contract
+}
+
+func (fact RegisterGenesisTokenFact) Symbol() types.TokenSymbol {
+	return fact.symbol
+}
+
+func (fact RegisterGenesisTokenFact) Name() string {
+	return fact.name
+}
+
+func (fact RegisterGenesisTokenFact) Decimal() common.Big {
+	return fact.decimal
+}
+
+func (fact RegisterGenesisTokenFact) Balances() []GenesisBalance {
+	return fact.balances
+}
+
+func (fact RegisterGenesisTokenFact) ApproveList() []types.ApproveBox {
+	return fact.approveList
+}
+
+func (fact RegisterGenesisTokenFact) TotalSupply() common.Big {
+	total := common.ZeroBig
+	for i := range fact.balances {
+		total = total.Add(fact.balances[i].amount)
+	}
+
+	return total
+}
+
+type RegisterGenesisToken struct {
+	common.BaseOperation
+}
+
+func NewRegisterGenesisToken(fact RegisterGenesisTokenFact) RegisterGenesisToken {
+	return RegisterGenesisToken{BaseOperation: common.NewBaseOperation(RegisterGenesisTokenHint, fact)}
+}
+
+func (op RegisterGenesisToken) IsValid(networkID []byte) error {
+	if err := op.BaseOperation.IsValid(networkID); err != nil {
+		return err
+	}
+
+	if len(op.Signs()) != 1 {
+		return util.ErrInvalid.Errorf("genesis token should be signed only by genesis node key")
+	}
+
+	fact, ok := op.Fact().(RegisterGenesisTokenFact)
+	if !ok {
+		return errors.Errorf("expected RegisterGenesisTokenFact, not %T", op.Fact())
+	}
+
+	if !fact.genesisNodeKey.Equal(op.Signs()[0].Signer()) {
+		return util.ErrInvalid.Errorf("not signed by genesis node key")
+	}
+
+	return nil
+}
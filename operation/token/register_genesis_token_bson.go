@@ -0,0 +1,95 @@
+package token
+
+import (
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/currency-model/utils/bsonenc"
+	"github.com/imfact-labs/mitum2/util/hint"
+	"github.com/imfact-labs/mitum2/util/valuehash"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func (gb GenesisBalance) MarshalBSON() ([]byte, error) {
+	return bsonenc.Marshal(
+		bson.M{
+			"address": gb.address,
+			"amount":  gb.amount,
+		},
+	)
+}
+
+func (fact RegisterGenesisTokenFact) MarshalBSON() ([]byte, error) {
+	return bsonenc.Marshal(
+		bson.M{
+			"_hint":            fact.Hint().String(),
+			"genesis_node_key": fact.genesisNodeKey.String(),
+			"owner":            fact.owner,
+			"contract":         fact.contract,
+			"symbol":           fact.symbol,
+			"name":             fact.name,
+			"decimal":          fact.decimal,
+			"balances":         fact.balances,
+			"approve_list":     fact.approveList,
+			"hash":             fact.BaseFact.Hash().String(),
+			"token":            fact.BaseFact.Token(),
+		},
+	)
+}
+
+type RegisterGenesisTokenFactBSONUnmarshaler struct {
+	Hint           string                          `bson:"_hint"`
+	GenesisNodeKey string                          `bson:"genesis_node_key"`
+	Owner          string                          `bson:"owner"`
+	Contract       string                          `bson:"contract"`
+	Symbol         string                          `bson:"symbol"`
+	Name           string                          `bson:"name"`
+	Decimal        string                          `bson:"decimal"`
+	Balances       []GenesisBalanceJSONUnmarshaler `bson:"balances"`
+	ApproveList    bson.Raw                        `bson:"approve_list"`
+}
+
+func (fact *RegisterGenesisTokenFact) DecodeBSON(b []byte, enc *bsonenc.Encoder) error {
+	var ubf common.BaseFactBSONUnmarshaler
+
+	if err := enc.Unmarshal(b, &ubf); err != nil {
+		return common.DecorateError(err, common.ErrDecodeBson, *fact)
+	}
+
+	fact.BaseFact.SetHash(valuehash.NewBytesFromString(ubf.Hash))
+	if err := fact.BaseFact.SetToken(ubf.Token); err != nil {
+		return common.DecorateError(err, common.ErrDecodeBson, *fact)
+	}
+
+	var uf RegisterGenesisTokenFactBSONUnmarshaler
+	if err := bson.Unmarshal(b, &uf); err != nil {
+		return common.DecorateError(err, common.ErrDecodeBson, *fact)
+	}
+
+	ht, err := hint.ParseHint(uf.Hint)
+	if err != nil {
+		return common.DecorateError(err, common.ErrDecodeBson, *fact)
+	}
+	fact.BaseHinter = hint.NewBaseHinter(ht)
+
+	if err := fact.unpack(enc,
+		uf.GenesisNodeKey, uf.Owner, uf.Contract, uf.Symbol, uf.Name, uf.Decimal, uf.Balances, uf.ApproveList,
+	); err != nil {
+		return common.DecorateError(err, common.ErrDecodeBson, *fact)
+	}
+
+	return nil
+}
+
+func (op RegisterGenesisToken) MarshalBSON() ([]byte, error) {
+	return marshalOperationBSON(op)
+}
+
+func (op *RegisterGenesisToken) DecodeBSON(b []byte, enc *bsonenc.Encoder) error {
+	var ubo common.BaseOperation
+	if err := ubo.DecodeBSON(b, enc); err != nil {
+		return common.DecorateError(err, common.ErrDecodeBson, *op)
+	}
+
+	op.BaseOperation = ubo
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package token
+
+import (
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util/encoder"
+	"github.com/imfact-labs/token-model/types"
+	"github.com/pkg/errors"
+)
+
+func (gb *GenesisBalance) unpack(enc encoder.Encoder, ad, am string) error {
+	switch a, err := base.DecodeAddress(ad, enc); {
+	case err != nil:
+		return err
+	default:
+		gb.address = a
+	}
+
+	big, err := common.NewBigFromString(am)
+	if err != nil {
+		return err
+	}
+	gb.amount = big
+
+	return nil
+}
+
+func (fact *RegisterGenesisTokenFact) unpack(
+	enc encoder.Encoder,
+	gk, ow, ca, sb, nm, de string,
+	bs []GenesisBalanceJSONUnmarshaler,
+	bap []byte,
+) error {
+	switch pk, err := base.DecodePublickeyFromString(gk, enc); {
+	case err != nil:
+		return err
+	default:
+		fact.genesisNodeKey = pk
+	}
+
+	switch a, err := base.DecodeAddress(ow, enc); {
+	case err != nil:
+		return err
+	default:
+		fact.owner = a
+	}
+
+	switch a, err := base.DecodeAddress(ca, enc); {
+	case err != nil:
+		return err
+	default:
+		fact.contract = a
+	}
+
+	fact.symbol = types.TokenSymbol(sb)
+	fact.name = nm
+
+	big, err := common.NewBigFromString(de)
+	if err != nil {
+		return err
+	}
+	fact.decimal = big
+
+	fact.balances = make([]GenesisBalance, len(bs))
+	for i := range bs {
+		if err := fact.balances[i].unpack(enc, bs[i].Address, bs[i].Amount); err != nil {
+			return err
+		}
+	}
+
+	hap, err := enc.DecodeSlice(bap)
+	if err != nil {
+		return err
+	}
+
+	fact.approveList = make([]types.ApproveBox, len(hap))
+	for i, h := range hap {
+		ap, ok := h.(types.ApproveBox)
+		if !ok {
+			return errors.Errorf("expected %T, not %T", types.ApproveBox{}, h)
+		}
+
+		fact.approveList[i] = ap
+	}
+
+	return nil
+}
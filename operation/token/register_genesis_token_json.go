@@ -0,0 +1,87 @@
+package token
+
+import (
+	"encoding/json"
+
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/encoder"
+	"github.com/imfact-labs/token-model/types"
+)
+
+type GenesisBalanceJSONMarshaler struct {
+	Address base.Address `json:"address"`
+	Amount  common.Big   `json:"amount"`
+}
+
+func (gb GenesisBalance) MarshalJSON() ([]byte, error) {
+	return util.MarshalJSON(GenesisBalanceJSONMarshaler{
+		Address: gb.address,
+		Amount:  gb.amount,
+	})
+}
+
+type GenesisBalanceJSONUnmarshaler struct {
+	Address string `json:"address" bson:"address"`
+	Amount  string `json:"amount" bson:"amount"`
+}
+
+type RegisterGenesisTokenFactJSONMarshaler struct {
+	base.BaseFactJSONMarshaler
+	GenesisNodeKey base.Publickey     `json:"genesis_node_key"`
+	Owner          base.Address       `json:"owner"`
+	Contract       base.Address       `json:"contract"`
+	Symbol         types.TokenSymbol  `json:"symbol"`
+	Name           string             `json:"name"`
+	Decimal        common.Big         `json:"decimal"`
+	Balances       []GenesisBalance   `json:"balances"`
+	ApproveList    []types.ApproveBox `json:"approve_list"`
+}
+
+func (fact RegisterGenesisTokenFact) MarshalJSON() ([]byte, error) {
+	return util.MarshalJSON(RegisterGenesisTokenFactJSONMarshaler{
+		BaseFactJSONMarshaler: fact.BaseFact.JSONMarshaler(),
+		GenesisNodeKey:        fact.genesisNodeKey,
+		Owner:                 fact.owner,
+		Contract:              fact.contract,
+		Symbol:                fact.symbol,
+		Name:                  fact.name,
+		Decimal:               fact.decimal,
+		Balances:              fact.balances,
+		ApproveList:           fact.approveList,
+	})
+}
+
+type RegisterGenesisTokenFactJSONUnmarshaler struct {
+	base.BaseFactJSONUnmarshaler
+	GenesisNodeKey string                          `json:"genesis_node_key"`
+	Owner          string                          `json:"owner"`
+	Contract       string                          `json:"contract"`
+	Symbol         string                          `json:"symbol"`
+	Name           string                          `json:"name"`
+	Decimal        string                          `json:"decimal"`
+	Balances       []GenesisBalanceJSONUnmarshaler `json:"balances"`
+	ApproveList    json.RawMessage                 `json:"approve_list"`
+}
+
+func (fact *RegisterGenesisTokenFact) DecodeJSON(b []byte, enc encoder.Encoder) error {
+	var uf RegisterGenesisTokenFactJSONUnmarshaler
+	if err := enc.Unmarshal(b, &uf); err != nil {
+		return common.DecorateError(err, common.ErrDecodeJson, *fact)
+	}
+
+	fact.BaseFact.SetJSONUnmarshaler(uf.BaseFactJSONUnmarshaler)
+
+	if err := fact.unpack(enc,
+		uf.GenesisNodeKey, uf.Owner, uf.Contract, uf.Symbol, uf.Name, uf.Decimal, uf.Balances, uf.ApproveList,
+	); err != nil {
+		return common.DecorateError(err, common.ErrDecodeJson, *fact)
+	}
+
+	return nil
+}
+
+func (op RegisterGenesisToken) MarshalJSON() ([]byte, error) {
+	return util.MarshalJSON(op.BaseOperation)
+}
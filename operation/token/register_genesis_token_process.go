@@ -0,0 +1,142 @@
+package token
+
+import (
+	"context"
+
+	"github.com/imfact-labs/currency-model/common"
+	cstate "github.com/imfact-labs/currency-model/state"
+	ccstate "github.com/imfact-labs/currency-model/state/currency"
+	statee "github.com/imfact-labs/currency-model/state/extension"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/token-model/state"
+	"github.com/imfact-labs/token-model/types"
+	"github.com/pkg/errors"
+)
+
+func (op RegisterGenesisToken) PreProcess(
+	ctx context.Context, _ base.GetStateFunc,
+) (context.Context, base.OperationProcessReasonError, error) {
+	return ctx, nil, nil
+}
+
+// Process creates the contract account of owner, the token design, the symbol
+// registry entry and the balances; the accounts of owner and the balance
+// holders are created if not exist, like Mint. The contract account is marked
+// as registered by RegisterModel, so it can not be distinguished from the
+// token model registered after genesis. The symbol registry entry and the
+// canonical policy are written when the SymbolRegistryHeightContextKey and the
+// CanonicalApproveListHeightContextKey of ctx are the genesis height.
+func (op RegisterGenesisToken) Process(
+	ctx context.Context, getStateFunc base.GetStateFunc) (
+	[]base.StateMergeValue, base.OperationProcessReasonError, error,
+) {
+	fact, ok := op.Fact().(RegisterGenesisTokenFact)
+	if !ok {
+		return nil, nil, errors.Errorf("expected %T, not %T", RegisterGenesisTokenFact{}, op.Fact())
+	}
+
+	registryHeight, canonicalHeight := base.NilHeight, base.NilHeight
+	if err := util.LoadFromContext(ctx,
+		SymbolRegistryHeightContextKey, &registryHeight,
+		CanonicalApproveListHeightContextKey, &canonicalHeight,
+	); err != nil {
+		return nil, nil, err
+	}
+
+	g := state.NewStateKeyGenerator(fact.Contract().String())
+
+	symbolRegistry := isSymbolRegistryHeight(registryHeight, base.GenesisHeight)
+
+	keys := []string{
+		ccstate.AccountStateKey(fact.Contract()),
+		statee.StateKeyContractAccount(fact.Contract()),
+		g.Design(),
+	}
+
+	if symbolRegistry {
+		keys = append(keys, state.StateKeySymbol(fact.Symbol().String()))
+	}
+
+	for _, k := range keys {
+		if _, err := cstate.NotExistsState(k, "genesis token", getStateFunc); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	ks, err := ctypes.NewContractAccountKeys()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ac, err := ctypes.NewAccount(fact.Contract(), ks)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cas := ctypes.NewContractAccountStatus(fact.Owner(), nil)
+	cas.SetActive(true)
+	h := RegisterModelHint
+	cas.SetRegisterOperation(&h)
+
+	policy := newPolicy(
+		isCanonicalApproveListHeight(canonicalHeight, base.GenesisHeight), fact.TotalSupply(), fact.approveList)
+	if err := policy.IsValid(nil); err != nil {
+		return nil, ErrInvalid(policy, err), nil
+	}
+
+	design := types.NewDesign(fact.Symbol(), fact.Name(), fact.Decimal(), policy)
+	if err := design.IsValid(nil); err != nil {
+		return nil, ErrInvalid(design, err), nil
+	}
+
+	sts := []base.StateMergeValue{
+		cstate.NewStateMergeValue(ccstate.AccountStateKey(fact.Contract()), ccstate.NewAccountStateValue(ac)),
+		cstate.NewStateMergeValue(
+			statee.StateKeyContractAccount(fact.Contract()), statee.NewContractAccountStateValue(cas)),
+		state.NewDesignStateMergeValue(g.Design(), design),
+	}
+
+	if symbolRegistry {
+		sts = append(sts, cstate.NewStateMergeValue(
+			state.StateKeySymbol(fact.Symbol().String()), state.NewSymbolStateValue(fact.Contract(), false)))
+	}
+
+	balances := fact.Balances()
+
+	accounts := make([]base.Address, 0, len(balances)+1)
+	accounts = append(accounts, fact.Owner())
+	for i := range balances {
+		accounts = append(accounts, balances[i].Address())
+	}
+
+	created := map[string]struct{}{fact.Contract().String(): {}}
+	for i := range accounts {
+		if _, found := created[accounts[i].String()]; found {
+			continue
+		}
+		created[accounts[i].String()] = struct{}{}
+
+		smv, err := cstate.CreateNotExistAccount(accounts[i], getStateFunc)
+		if err != nil {
+			return nil, base.NewBaseOperationProcessReasonError("%w", err), nil
+		} else if smv != nil {
+			sts = append(sts, smv)
+		}
+	}
+
+	for i := range balances {
+		k := g.TokenBalance(balances[i].Address().String())
+
+		sts = append(sts, common.NewBaseStateMergeValue(
+			k,
+			state.NewAddTokenBalanceStateValue(balances[i].Amount()),
+			func(height base.Height, st base.State) base.StateValueMerger {
+				return state.NewTokenBalanceStateValueMerger(height, k, st)
+			},
+		))
+	}
+
+	return sts, nil, nil
+}
@@ -42,6 +42,10 @@ func (fact *RegisterModelFact) DecodeBSON(b []byte, enc *bsonenc.Encoder) error
 	return nil
 }
 
+func (op RegisterModel) MarshalBSON() ([]byte, error) {
+	return marshalOperationBSON(op)
+}
+
 func (op *RegisterModel) DecodeBSON(b []byte, enc *bsonenc.Encoder) error {
 	var ubo common.BaseOperation
 	if err := ubo.DecodeBSON(b, enc); err != nil {
@@ -31,9 +31,15 @@ func (RegisterModel) Process(
 
 type RegisterModelProcessor struct {
 	*base.BaseOperationProcessor
+	symbolRegistry bool
+	canonical      bool
 }
 
-func NewRegisterModelProcessor() ctypes.GetNewProcessor {
+// NewRegisterModelProcessor returns the processor, which checks and adds the
+// symbol registry entry from the registry height; below it, the symbol is not
+// checked like the blocks before the registry. The canonical policy is written
+// from the canonical approve list height.
+func NewRegisterModelProcessor(registryHeight, canonicalHeight base.Height) ctypes.GetNewProcessor {
 	return func(
 		height base.Height,
 		getStateFunc base.GetStateFunc,
@@ -56,6 +62,8 @@ func NewRegisterModelProcessor() ctypes.GetNewProcessor {
 		}
 
 		opp.BaseOperationProcessor = b
+		opp.symbolRegistry = isSymbolRegistryHeight(registryHeight, height)
+		opp.canonical = isCanonicalApproveListHeight(canonicalHeight, height)
 
 		return opp, nil
 	}
@@ -86,6 +94,27 @@ func (opp *RegisterModelProcessor) PreProcess(
 				Wrap(common.ErrMServiceE).Errorf("token design for contract account %v", fact.Contract())), nil
 	}
 
+	if !opp.symbolRegistry {
+		return ctx, nil, nil
+	}
+
+	switch holder, err := state.SymbolHolder(fact.Symbol().String(), getStateFunc); {
+	case err != nil:
+		return ctx, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMStateValInvalid).Errorf("token symbol %v: %v", fact.Symbol(), err)), nil
+	case holder != nil:
+		return ctx, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMStateE).Errorf("token symbol %v held by contract account %v", fact.Symbol(), holder)), nil
+	}
+
+	if err := state.ReserveSymbol(ctx, fact.Symbol().String()); err != nil {
+		return ctx, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.
+				Wrap(common.ErrMStateE).Errorf("%v", err)), nil
+	}
+
 	return ctx, nil, nil
 }
 
@@ -104,7 +133,7 @@ func (opp *RegisterModelProcessor) Process(
 
 	var sts []base.StateMergeValue
 
-	policy := types.NewPolicy(fact.InitialSupply(), []types.ApproveBox{})
+	policy := newPolicy(opp.canonical, fact.InitialSupply(), []types.ApproveBox{})
 	if err := policy.IsValid(nil); err != nil {
 		return nil, ErrInvalid(policy, err), nil
 	}
@@ -114,10 +143,14 @@ func (opp *RegisterModelProcessor) Process(
 		return nil, ErrInvalid(design, err), nil
 	}
 
-	sts = append(sts, cstate.NewStateMergeValue(
-		g.Design(),
-		state.NewDesignStateValue(design),
-	))
+	sts = append(sts, state.NewDesignStateMergeValue(g.Design(), design))
+
+	if opp.symbolRegistry {
+		sts = append(sts, cstate.NewStateMergeValue(
+			state.StateKeySymbol(fact.Symbol().String()),
+			state.NewSymbolStateValue(fact.Contract(), false),
+		))
+	}
 
 	st, err := cstate.ExistsState(statee.StateKeyContractAccount(fact.Contract()), "contract account", getStateFunc)
 	if err != nil {
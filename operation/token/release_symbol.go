@@ -0,0 +1,99 @@
+package token
+
+import (
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/currency-model/operation/extras"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/hint"
+	"github.com/imfact-labs/mitum2/util/valuehash"
+	"github.com/imfact-labs/token-model/operation/processor"
+	"github.com/imfact-labs/token-model/types"
+)
+
+var (
+	ReleaseSymbolFactHint = hint.MustNewHint("mitum-token-release-symbol-operation-fact-v0.0.1")
+	ReleaseSymbolHint     = hint.MustNewHint("mitum-token-release-symbol-operation-v0.0.1")
+)
+
+// ReleaseSymbolFact releases the token symbol held by the contract from the
+// symbol registry; the design of contract is not changed.
+type ReleaseSymbolFact struct {
+	TokenFact
+	symbol types.TokenSymbol
+}
+
+func NewReleaseSymbolFact(
+	token []byte,
+	sender, contract base.Address,
+	currency ctypes.CurrencyID,
+	symbol types.TokenSymbol,
+) ReleaseSymbolFact {
+	fact := ReleaseSymbolFact{
+		TokenFact: NewTokenFact(
+			base.NewBaseFact(ReleaseSymbolFactHint, token), sender, contract, currency,
+		),
+		symbol: symbol,
+	}
+	fact.SetHash(fact.GenerateHash())
+	return fact
+}
+
+func (fact ReleaseSymbolFact) IsValid(b []byte) error {
+	if err := util.CheckIsValiders(nil, false, fact.TokenFact, fact.symbol); err != nil {
+		return common.ErrFactInvalid.Wrap(err)
+	}
+
+	if err := common.IsValidOperationFact(fact, b); err != nil {
+		return common.ErrFactInvalid.Wrap(err)
+	}
+	return nil
+}
+
+func (fact ReleaseSymbolFact) GenerateHash() util.Hash {
+	return valuehash.NewSHA256(fact.Bytes())
+}
+
+func (fact ReleaseSymbolFact) Bytes() []byte {
+	return util.ConcatBytesSlice(
+		fact.TokenFact.Bytes(),
+		fact.symbol.Bytes(),
+	)
+}
+
+func (fact ReleaseSymbolFact) Symbol() types.TokenSymbol {
+	return fact.symbol
+}
+
+func (fact ReleaseSymbolFact) ActiveContractOwnerHandlerOnly() [][2]base.Address {
+	return [][2]base.Address{{fact.contract, fact.sender}}
+}
+
+func (fact ReleaseSymbolFact) DupKey() (map[ctypes.DuplicationKeyType][]string, error) {
+	r := make(map[ctypes.DuplicationKeyType][]string)
+	r[extras.DuplicationKeyTypeContractStatus] = []string{fact.contract.String()}
+	r[processor.DuplicationTypeTokenSymbol] = []string{fact.symbol.String()}
+
+	return r, nil
+}
+
+type ReleaseSymbol struct {
+	extras.ExtendedOperation
+}
+
+func (op ReleaseSymbol) DupKey() (map[ctypes.DuplicationKeyType][]string, error) {
+	r := make(map[ctypes.DuplicationKeyType][]string)
+
+	if err := extras.AddOperationFeePayerDupKeys(r, op); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func NewReleaseSymbol(fact ReleaseSymbolFact) ReleaseSymbol {
+	return ReleaseSymbol{
+		ExtendedOperation: extras.NewExtendedOperation(ReleaseSymbolHint, fact),
+	}
+}
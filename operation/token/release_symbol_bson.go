@@ -0,0 +1,57 @@
+package token
+
+import (
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/currency-model/operation/extras"
+	"github.com/imfact-labs/currency-model/utils/bsonenc"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func (fact ReleaseSymbolFact) MarshalBSON() ([]byte, error) {
+	m := fact.TokenFact.marshalMap()
+
+	m["symbol"] = fact.symbol
+
+	return bsonenc.Marshal(m)
+}
+
+type ReleaseSymbolFactBSONUnmarshaler struct {
+	Symbol string `bson:"symbol"`
+}
+
+func (fact *ReleaseSymbolFact) DecodeBSON(b []byte, enc *bsonenc.Encoder) error {
+	if err := fact.TokenFact.DecodeBSON(b, enc); err != nil {
+		return common.DecorateError(err, common.ErrDecodeBson, *fact)
+	}
+
+	var uf ReleaseSymbolFactBSONUnmarshaler
+	if err := bson.Unmarshal(b, &uf); err != nil {
+		return common.DecorateError(err, common.ErrDecodeBson, *fact)
+	}
+
+	fact.unpack(uf.Symbol)
+
+	return nil
+}
+
+func (op ReleaseSymbol) MarshalBSON() ([]byte, error) {
+	return marshalOperationBSON(op)
+}
+
+func (op *ReleaseSymbol) DecodeBSON(b []byte, enc *bsonenc.Encoder) error {
+	var ubo common.BaseOperation
+	if err := ubo.DecodeBSON(b, enc); err != nil {
+		return common.DecorateError(err, common.ErrDecodeBson, *op)
+	}
+
+	op.BaseOperation = ubo
+
+	var ueo extras.BaseOperationExtensions
+	if err := ueo.DecodeBSON(b, enc); err != nil {
+		return common.DecorateError(err, common.ErrDecodeBson, *op)
+	}
+
+	op.BaseOperationExtensions = &ueo
+
+	return nil
+}
@@ -0,0 +1,9 @@
+package token
+
+import (
+	"github.com/imfact-labs/token-model/types"
+)
+
+func (fact *ReleaseSymbolFact) unpack(symbol string) {
+	fact.symbol = types.TokenSymbol(symbol)
+}
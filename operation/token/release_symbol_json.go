@@ -0,0 +1,65 @@
+package token
+
+import (
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/currency-model/operation/extras"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/encoder"
+	"github.com/imfact-labs/token-model/types"
+)
+
+type ReleaseSymbolFactJSONMarshaler struct {
+	TokenFactJSONMarshaler
+	Symbol types.TokenSymbol `json:"symbol"`
+}
+
+func (fact ReleaseSymbolFact) MarshalJSON() ([]byte, error) {
+	return util.MarshalJSON(ReleaseSymbolFactJSONMarshaler{
+		TokenFactJSONMarshaler: fact.TokenFact.JSONMarshaler(),
+		Symbol:                 fact.symbol,
+	})
+}
+
+type ReleaseSymbolFactJSONUnMarshaler struct {
+	Symbol string `json:"symbol"`
+}
+
+func (fact *ReleaseSymbolFact) DecodeJSON(b []byte, enc encoder.Encoder) error {
+	if err := fact.TokenFact.DecodeJSON(b, enc); err != nil {
+		return common.DecorateError(err, common.ErrDecodeJson, *fact)
+	}
+
+	var uf ReleaseSymbolFactJSONUnMarshaler
+	if err := enc.Unmarshal(b, &uf); err != nil {
+		return common.DecorateError(err, common.ErrDecodeJson, *fact)
+	}
+
+	fact.unpack(uf.Symbol)
+
+	return nil
+}
+
+func (op ReleaseSymbol) MarshalJSON() ([]byte, error) {
+	return util.MarshalJSON(OperationMarshaler{
+		BaseOperationJSONMarshaler:           op.BaseOperation.JSONMarshaler(),
+		BaseOperationExtensionsJSONMarshaler: op.BaseOperationExtensions.JSONMarshaler(),
+	})
+}
+
+func (op *ReleaseSymbol) DecodeJSON(b []byte, enc encoder.Encoder) error {
+	var ubo common.BaseOperation
+	if err := ubo.DecodeJSON(b, enc); err != nil {
+		return common.DecorateError(err, common.ErrDecodeJson, *op)
+	}
+
+	op.BaseOperation = ubo
+
+	var ueo extras.BaseOperationExtensions
+	if err := ueo.DecodeJSON(b, enc); err != nil {
+		return common.DecorateError(err, common.ErrDecodeJson, *op)
+	}
+
+	op.BaseOperationExtensions = &ueo
+
+	return nil
+}
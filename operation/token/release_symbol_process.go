@@ -0,0 +1,136 @@
+package token
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/imfact-labs/currency-model/common"
+	cstate "github.com/imfact-labs/currency-model/state"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/token-model/state"
+	"github.com/imfact-labs/token-model/utils"
+	"github.com/pkg/errors"
+)
+
+var releaseSymbolProcessorPool = sync.Pool{
+	New: func() interface{} {
+		return new(ReleaseSymbolProcessor)
+	},
+}
+
+func (ReleaseSymbol) Process(
+	_ context.Context, _ base.GetStateFunc,
+) ([]base.StateMergeValue, base.OperationProcessReasonError, error) {
+	return nil, nil, nil
+}
+
+type ReleaseSymbolProcessor struct {
+	*base.BaseOperationProcessor
+	symbolRegistry bool
+}
+
+// NewReleaseSymbolProcessor returns the processor; below the registry height,
+// the symbol can not be released.
+func NewReleaseSymbolProcessor(registryHeight base.Height) ctypes.GetNewProcessor {
+	return func(
+		height base.Height,
+		getStateFunc base.GetStateFunc,
+		newPreProcessConstraintFunc base.NewOperationProcessorProcessFunc,
+		newProcessConstraintFunc base.NewOperationProcessorProcessFunc,
+	) (base.OperationProcessor, error) {
+		t := ReleaseSymbolProcessor{}
+		e := util.StringError("%s", utils.ErrStringCreate(fmt.Sprintf("new %T", t)))
+
+		nopp := releaseSymbolProcessorPool.Get()
+		opp, ok := nopp.(*ReleaseSymbolProcessor)
+		if !ok {
+			return nil, e.Wrap(errors.Errorf("%s", utils.ErrStringTypeCast(&t, nopp)))
+		}
+
+		b, err := base.NewBaseOperationProcessor(
+			height, getStateFunc, newPreProcessConstraintFunc, newProcessConstraintFunc)
+		if err != nil {
+			return nil, e.Wrap(err)
+		}
+
+		opp.BaseOperationProcessor = b
+		opp.symbolRegistry = isSymbolRegistryHeight(registryHeight, height)
+
+		return opp, nil
+	}
+}
+
+func (opp *ReleaseSymbolProcessor) PreProcess(
+	ctx context.Context, op base.Operation, getStateFunc base.GetStateFunc,
+) (context.Context, base.OperationProcessReasonError, error) {
+	fact, ok := op.Fact().(ReleaseSymbolFact)
+	if !ok {
+		return ctx, base.NewBaseOperationProcessReasonError(
+			"%s",
+			common.ErrMPreProcess.
+				Wrap(common.ErrMTypeMismatch).
+				Errorf("expected %T, not %T", ReleaseSymbolFact{}, op.Fact())), nil
+	}
+
+	if err := fact.IsValid(nil); err != nil {
+		return ctx, base.NewBaseOperationProcessReasonError(
+			"%s",
+			common.ErrMPreProcess.
+				Errorf("%v", err)), nil
+	}
+
+	if !opp.symbolRegistry {
+		return ctx, base.NewBaseOperationProcessReasonError(
+			"%s",
+			common.ErrMPreProcess.
+				Errorf("token symbol registry not kept at height %v", opp.Height())), nil
+	}
+
+	switch holder, err := state.SymbolHolder(fact.Symbol().String(), getStateFunc); {
+	case err != nil:
+		return ctx, base.NewBaseOperationProcessReasonError(
+			"%s",
+			common.ErrMPreProcess.
+				Wrap(common.ErrMStateValInvalid).Errorf("token symbol %v: %v", fact.Symbol(), err)), nil
+	case holder == nil:
+		return ctx, base.NewBaseOperationProcessReasonError(
+			"%s",
+			common.ErrMPreProcess.
+				Wrap(common.ErrMStateNF).Errorf("token symbol %v not held by any contract account", fact.Symbol())), nil
+	case !holder.Equal(fact.Contract()):
+		return ctx, base.NewBaseOperationProcessReasonError(
+			"%s",
+			common.ErrMPreProcess.
+				Wrap(common.ErrMAccountNAth).Errorf("token symbol %v held by contract account %v, not %v",
+				fact.Symbol(), holder, fact.Contract())), nil
+	}
+
+	return ctx, nil, nil
+}
+
+func (opp *ReleaseSymbolProcessor) Process(
+	_ context.Context, op base.Operation, _ base.GetStateFunc) (
+	[]base.StateMergeValue, base.OperationProcessReasonError, error,
+) {
+	e := util.StringError("%s", ErrStringProcess(*opp))
+
+	fact, ok := op.Fact().(ReleaseSymbolFact)
+	if !ok {
+		return nil, nil, e.Wrap(errors.Errorf("%s", utils.ErrStringTypeCast(ReleaseSymbolFact{}, op.Fact())))
+	}
+
+	return []base.StateMergeValue{
+		cstate.NewStateMergeValue(
+			state.StateKeySymbol(fact.Symbol().String()),
+			state.NewSymbolStateValue(fact.Contract(), true),
+		),
+	}, nil, nil
+}
+
+func (opp *ReleaseSymbolProcessor) Close() error {
+	releaseSymbolProcessorPool.Put(opp)
+	return nil
+}
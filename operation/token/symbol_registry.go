@@ -0,0 +1,18 @@
+package token
+
+import (
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+)
+
+// SymbolRegistryHeightContextKey is the symbol registry height for
+// RegisterGenesisToken, which is processed without the processor.
+var SymbolRegistryHeightContextKey = util.ContextKey("token-symbol-registry-height")
+
+// isSymbolRegistryHeight tells whether the token symbol registry is kept at
+// the height. The registry is kept from the registry height; base.NilHeight
+// does not keep the registry, so the existing networks process the blocks in
+// the same way until every node agrees on the registry height.
+func isSymbolRegistryHeight(registryHeight, height base.Height) bool {
+	return registryHeight > base.NilHeight && height >= registryHeight
+}
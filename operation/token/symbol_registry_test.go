@@ -0,0 +1,251 @@
+package token
+
+import (
+	"context"
+	"testing"
+
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/currency-model/operation/test"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/token-model/state"
+	"github.com/imfact-labs/token-model/types"
+)
+
+func runTestSymbolProcessor(
+	t *testing.T, ctx context.Context, tp *test.TestProcessor, f ctypes.GetNewProcessor, op base.Operation,
+) ([]base.StateMergeValue, error) {
+	t.Helper()
+
+	opp, err := f(base.Height(1), tp.GetStateFunc, nil, nil)
+	if err != nil {
+		t.Fatalf("new processor: %v", err)
+	}
+
+	defer func() {
+		_ = opp.Close()
+	}()
+
+	if _, reason, err := opp.PreProcess(ctx, op, tp.GetStateFunc); err != nil {
+		t.Fatalf("preprocess: %v", err)
+	} else if reason != nil {
+		return nil, reason
+	}
+
+	stvs, reason, err := opp.Process(ctx, op, tp.GetStateFunc)
+	if err != nil {
+		t.Fatalf("process: %v", err)
+	} else if reason != nil {
+		return nil, reason
+	}
+
+	return stvs, nil
+}
+
+func findTestSymbolValue(stvs []base.StateMergeValue, symbol string) *state.SymbolStateValue {
+	for i := range stvs {
+		if stvs[i].Key() != state.StateKeySymbol(symbol) {
+			continue
+		}
+
+		v, ok := stvs[i].Value().(state.SymbolStateValue)
+		if !ok {
+			return nil
+		}
+
+		return &v
+	}
+
+	return nil
+}
+
+func TestRegisterModelSymbolRegistryHeight(t *testing.T) {
+	cases := []struct {
+		name           string
+		registryHeight base.Height
+		registry       bool
+	}{
+		{name: "registry not kept", registryHeight: base.NilHeight},
+		{name: "below registry height", registryHeight: base.Height(2)},
+		{name: "registry height", registryHeight: base.Height(1), registry: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			tp := &test.TestProcessor{}
+			tp.Setup(test.NewMockStateGetter())
+
+			senders := make([]test.Account, 1)
+			contracts := make([]test.Account, 2)
+
+			tp.SetAccount(tp.NewPrivateKey("sender"), 1000, tp.GenesisCurrency, senders, true)
+			tp.SetContractAccount(senders[0].Address(), tp.NewPrivateKey("contract"), 1000,
+				tp.GenesisCurrency, contracts[:1], true)
+			tp.SetContractAccount(senders[0].Address(), tp.NewPrivateKey("holder"), 1000,
+				tp.GenesisCurrency, contracts[1:], true)
+
+			// NOTE the symbol is held by the other contract.
+			NewTestSymbolState(tp, "ABC", contracts[1].Address(), false, true)
+
+			op := NewRegisterModel(NewRegisterModelFact(
+				[]byte("token"), senders[0].Address(), contracts[0].Address(), tp.GenesisCurrency,
+				types.TokenSymbol("ABC"), "abc", common.NewBig(0), common.NewBig(100),
+			))
+			_ = op.Sign(senders[0].Priv(), tp.NetworkID)
+
+			stvs, err := runTestSymbolProcessor(t, context.Background(), tp, NewRegisterModelProcessor(c.registryHeight, base.GenesisHeight), op)
+
+			switch {
+			case c.registry && err == nil:
+				t.Fatal("expected symbol held error")
+			case c.registry:
+				return
+			case err != nil:
+				t.Fatalf("register model: %v", err)
+			}
+
+			if v := findTestSymbolValue(stvs, "ABC"); v != nil {
+				t.Fatalf("expected no symbol registry entry, got %v", v.Contract())
+			}
+		})
+	}
+}
+
+func TestBackfillSymbol(t *testing.T) {
+	cases := []struct {
+		name           string
+		registryHeight base.Height
+		symbol         string
+		entry          bool
+		err            bool
+	}{
+		{name: "backfill", registryHeight: base.GenesisHeight, symbol: "ABC"},
+		{name: "registry not kept", registryHeight: base.NilHeight, symbol: "ABC", err: true},
+		{name: "below registry height", registryHeight: base.Height(2), symbol: "ABC", err: true},
+		{name: "symbol not same with design", registryHeight: base.GenesisHeight, symbol: "XYZ", err: true},
+		{name: "released entry", registryHeight: base.GenesisHeight, symbol: "ABC", entry: true, err: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			tp := &test.TestProcessor{}
+			tp.Setup(test.NewMockStateGetter())
+
+			a := newTestTransferAccounts(tp)
+
+			NewTestDesignState(tp, a.contract.Address(), types.NewDesign(
+				types.TokenSymbol("ABC"), "abc", common.NewBig(0), types.NewPolicy(common.NewBig(100), nil),
+			), true)
+
+			if c.entry {
+				NewTestSymbolState(tp, "ABC", a.contract.Address(), true, true)
+			}
+
+			op := NewBackfillSymbol(NewBackfillSymbolFact(
+				[]byte("token"), a.receiver.Address(), a.contract.Address(), a.currency, types.TokenSymbol(c.symbol),
+			))
+			_ = op.Sign(a.receiver.Priv(), tp.NetworkID)
+
+			stvs, err := runTestSymbolProcessor(t, context.Background(), tp, NewBackfillSymbolProcessor(c.registryHeight), op)
+
+			switch {
+			case c.err && err == nil:
+				t.Fatal("expected backfill error")
+			case c.err:
+				return
+			case err != nil:
+				t.Fatalf("backfill: %v", err)
+			}
+
+			switch v := findTestSymbolValue(stvs, c.symbol); {
+			case v == nil:
+				t.Fatal("expected symbol registry entry")
+			case !v.Contract().Equal(a.contract.Address()) || v.Released():
+				t.Fatalf("unexpected symbol registry entry, %v released=%v", v.Contract(), v.Released())
+			}
+		})
+	}
+}
+
+func TestSymbolReservationInProposal(t *testing.T) {
+	tp := &test.TestProcessor{}
+	tp.Setup(test.NewMockStateGetter())
+
+	a := newTestTransferAccounts(tp)
+
+	contracts := make([]test.Account, 1)
+	tp.SetContractAccount(a.sender.Address(), tp.NewPrivateKey("other-contract"), 1000,
+		a.currency, contracts, true)
+
+	NewTestDesignState(tp, a.contract.Address(), types.NewDesign(
+		types.TokenSymbol("ABC"), "abc", common.NewBig(0), types.NewPolicy(common.NewBig(100), nil),
+	), true)
+
+	ctx := context.WithValue(context.Background(), state.SymbolReservationContextKey, state.NewSymbolReservation())
+
+	backfill := NewBackfillSymbol(NewBackfillSymbolFact(
+		[]byte("token"), a.receiver.Address(), a.contract.Address(), a.currency, types.TokenSymbol("ABC"),
+	))
+	_ = backfill.Sign(a.receiver.Priv(), tp.NetworkID)
+
+	if _, err := runTestSymbolProcessor(t, ctx, tp, NewBackfillSymbolProcessor(base.GenesisHeight), backfill); err != nil {
+		t.Fatalf("backfill: %v", err)
+	}
+
+	// NOTE the entry of backfill is not in state yet, but the symbol is
+	// reserved in the same proposal.
+	register := NewRegisterModel(NewRegisterModelFact(
+		[]byte("token"), a.sender.Address(), contracts[0].Address(), a.currency,
+		types.TokenSymbol("ABC"), "abc", common.NewBig(0), common.NewBig(100),
+	))
+	_ = register.Sign(a.sender.Priv(), tp.NetworkID)
+
+	if _, err := runTestSymbolProcessor(t, ctx, tp, NewRegisterModelProcessor(base.GenesisHeight, base.GenesisHeight), register); err == nil {
+		t.Fatal("expected symbol reserved error")
+	}
+}
+
+func TestRegisterGenesisTokenSymbolRegistryHeight(t *testing.T) {
+	cases := []struct {
+		name           string
+		registryHeight base.Height
+		registry       bool
+	}{
+		{name: "registry not kept", registryHeight: base.NilHeight},
+		{name: "registry after genesis", registryHeight: base.Height(2)},
+		{name: "registry from genesis", registryHeight: base.GenesisHeight, registry: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			tp := &test.TestProcessor{}
+			tp.Setup(test.NewMockStateGetter())
+
+			a := newTestTransferAccounts(tp)
+			contract := ctypes.NewAddress("0x6DC072838c96e06B4bbc763A8D0687Dc4a22f95E")
+
+			op := NewRegisterGenesisToken(NewRegisterGenesisTokenFact(
+				[]byte("token"), base.NewMPrivatekey().Publickey(), a.sender.Address(), contract,
+				types.TokenSymbol("ABC"), "abc", common.NewBig(0),
+				[]GenesisBalance{NewGenesisBalance(a.sender.Address(), common.NewBig(100))}, nil,
+			))
+
+			ctx := context.WithValue(context.Background(), SymbolRegistryHeightContextKey, c.registryHeight)
+
+			stvs, reason, err := op.Process(ctx, tp.GetStateFunc)
+			switch {
+			case err != nil:
+				t.Fatalf("process: %v", err)
+			case reason != nil:
+				t.Fatalf("process: %v", reason)
+			}
+
+			switch v := findTestSymbolValue(stvs, "ABC"); {
+			case c.registry && v == nil:
+				t.Fatal("expected symbol registry entry")
+			case !c.registry && v != nil:
+				t.Fatalf("expected no symbol registry entry, got %v", v.Contract())
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package token
+
+import (
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/currency-model/operation/test"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/token-model/types"
+)
+
+type TestApproveProcessor struct {
+	*test.BaseTestOperationProcessorWithItem[Approve, ApproveItem]
+}
+
+func NewTestApproveProcessor(tp *test.TestProcessor) TestApproveProcessor {
+	t := test.NewBaseTestOperationProcessorWithItem[Approve, ApproveItem](tp)
+	return TestApproveProcessor{BaseTestOperationProcessorWithItem: &t}
+}
+
+func (t *TestApproveProcessor) Create() *TestApproveProcessor {
+	t.Opr, _ = NewApproveProcessor(base.GenesisHeight)(
+		base.GenesisHeight,
+		t.GetStateFunc,
+		nil, nil,
+	)
+	return t
+}
+
+func (t *TestApproveProcessor) SetCurrency(
+	cid string, am int64, addr base.Address, target []ctypes.CurrencyID, instate bool,
+) *TestApproveProcessor {
+	t.BaseTestOperationProcessorWithItem.SetCurrency(cid, am, addr, target, instate)
+
+	return t
+}
+
+func (t *TestApproveProcessor) SetAmount(
+	am int64, cid ctypes.CurrencyID, target []ctypes.Amount,
+) *TestApproveProcessor {
+	t.BaseTestOperationProcessorWithItem.SetAmount(am, cid, target)
+
+	return t
+}
+
+func (t *TestApproveProcessor) SetContractAccount(
+	owner base.Address, priv string, amount int64, cid ctypes.CurrencyID, target []test.Account, inState bool,
+) *TestApproveProcessor {
+	t.BaseTestOperationProcessorWithItem.SetContractAccount(owner, priv, amount, cid, target, inState)
+
+	return t
+}
+
+func (t *TestApproveProcessor) SetAccount(
+	priv string, amount int64, cid ctypes.CurrencyID, target []test.Account, inState bool,
+) *TestApproveProcessor {
+	t.BaseTestOperationProcessorWithItem.SetAccount(priv, amount, cid, target, inState)
+
+	return t
+}
+
+func (t *TestApproveProcessor) SetDesign(
+	contract base.Address, symbol string, name string, decimal, totalSupply int64,
+	approveList []types.ApproveBox, inState bool,
+) *TestApproveProcessor {
+	NewTestDesignState(t.TestProcessor, contract, types.NewDesign(
+		types.TokenSymbol(symbol),
+		name,
+		common.NewBig(decimal),
+		types.NewPolicy(common.NewBig(totalSupply), approveList),
+	), inState)
+
+	return t
+}
+
+func (t *TestApproveProcessor) SetTokenBalance(
+	contract, holder base.Address, amount int64, inState bool,
+) *TestApproveProcessor {
+	NewTestTokenBalanceState(t.TestProcessor, contract, holder, amount, inState)
+
+	return t
+}
+
+func (t *TestApproveProcessor) LoadOperation(fileName string,
+) *TestApproveProcessor {
+	t.BaseTestOperationProcessorWithItem.LoadOperation(fileName)
+
+	return t
+}
+
+func (t *TestApproveProcessor) Print(fileName string,
+) *TestApproveProcessor {
+	t.BaseTestOperationProcessorWithItem.Print(fileName)
+
+	return t
+}
+
+func (t *TestApproveProcessor) MakeItem(
+	contract base.Address, approved test.Account, amount int64, targetItems []ApproveItem,
+) *TestApproveProcessor {
+	item := NewApproveItem(contract, approved.Address(), common.NewBig(amount))
+	test.UpdateSlice[ApproveItem](item, targetItems)
+
+	return t
+}
+
+func (t *TestApproveProcessor) MakeOperation(
+	sender base.Address, privatekey base.Privatekey, items []ApproveItem, currency ctypes.CurrencyID,
+) *TestApproveProcessor {
+	op := NewApprove(
+		NewApproveFact(
+			[]byte("token"),
+			sender,
+			items,
+			currency,
+		))
+	_ = op.Sign(privatekey, t.NetworkID)
+	t.Op = op
+
+	return t
+}
+
+func (t *TestApproveProcessor) RunPreProcess() *TestApproveProcessor {
+	t.BaseTestOperationProcessorWithItem.RunPreProcess()
+
+	return t
+}
+
+func (t *TestApproveProcessor) RunProcess() *TestApproveProcessor {
+	t.BaseTestOperationProcessorWithItem.RunProcess()
+
+	return t
+}
+
+func (t *TestApproveProcessor) IsValid() *TestApproveProcessor {
+	t.BaseTestOperationProcessorWithItem.IsValid()
+
+	return t
+}
+
+func (t *TestApproveProcessor) Decode(fileName string) *TestApproveProcessor {
+	t.BaseTestOperationProcessorWithItem.Decode(fileName)
+
+	return t
+}
+
+// NewTestApproveItem returns the approve item of the addresses.
+func NewTestApproveItem(contract, approved base.Address, amount int64) ApproveItem {
+	return NewApproveItem(contract, approved, common.NewBig(amount))
+}
@@ -0,0 +1,114 @@
+package token
+
+import (
+	"github.com/imfact-labs/currency-model/operation/test"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/token-model/types"
+)
+
+type TestBackfillSymbolProcessor struct {
+	*test.BaseTestOperationProcessorNoItem[BackfillSymbol]
+}
+
+func NewTestBackfillSymbolProcessor(tp *test.TestProcessor) TestBackfillSymbolProcessor {
+	t := test.NewBaseTestOperationProcessorNoItem[BackfillSymbol](tp)
+	return TestBackfillSymbolProcessor{BaseTestOperationProcessorNoItem: &t}
+}
+
+func (t *TestBackfillSymbolProcessor) Create() *TestBackfillSymbolProcessor {
+	t.Opr, _ = NewBackfillSymbolProcessor(base.GenesisHeight)(
+		base.GenesisHeight,
+		t.GetStateFunc,
+		nil, nil,
+	)
+	return t
+}
+
+func (t *TestBackfillSymbolProcessor) SetCurrency(
+	cid string, am int64, addr base.Address, target []ctypes.CurrencyID, instate bool,
+) *TestBackfillSymbolProcessor {
+	t.BaseTestOperationProcessorNoItem.SetCurrency(cid, am, addr, target, instate)
+
+	return t
+}
+
+func (t *TestBackfillSymbolProcessor) SetAmount(
+	am int64, cid ctypes.CurrencyID, target []ctypes.Amount,
+) *TestBackfillSymbolProcessor {
+	t.BaseTestOperationProcessorNoItem.SetAmount(am, cid, target)
+
+	return t
+}
+
+func (t *TestBackfillSymbolProcessor) SetContractAccount(
+	owner base.Address, priv string, amount int64, cid ctypes.CurrencyID, target []test.Account, inState bool,
+) *TestBackfillSymbolProcessor {
+	t.BaseTestOperationProcessorNoItem.SetContractAccount(owner, priv, amount, cid, target, inState)
+
+	return t
+}
+
+func (t *TestBackfillSymbolProcessor) SetAccount(
+	priv string, amount int64, cid ctypes.CurrencyID, target []test.Account, inState bool,
+) *TestBackfillSymbolProcessor {
+	t.BaseTestOperationProcessorNoItem.SetAccount(priv, amount, cid, target, inState)
+
+	return t
+}
+
+func (t *TestBackfillSymbolProcessor) LoadOperation(fileName string,
+) *TestBackfillSymbolProcessor {
+	t.BaseTestOperationProcessorNoItem.LoadOperation(fileName)
+
+	return t
+}
+
+func (t *TestBackfillSymbolProcessor) Print(fileName string,
+) *TestBackfillSymbolProcessor {
+	t.BaseTestOperationProcessorNoItem.Print(fileName)
+
+	return t
+}
+
+func (t *TestBackfillSymbolProcessor) MakeOperation(
+	sender base.Address, privatekey base.Privatekey, contract base.Address,
+	symbol string, currency ctypes.CurrencyID,
+) *TestBackfillSymbolProcessor {
+	op := NewBackfillSymbol(
+		NewBackfillSymbolFact(
+			[]byte("token"),
+			sender,
+			contract,
+			currency,
+			types.TokenSymbol(symbol),
+		))
+	_ = op.Sign(privatekey, t.NetworkID)
+	t.Op = op
+
+	return t
+}
+
+func (t *TestBackfillSymbolProcessor) RunPreProcess() *TestBackfillSymbolProcessor {
+	t.BaseTestOperationProcessorNoItem.RunPreProcess()
+
+	return t
+}
+
+func (t *TestBackfillSymbolProcessor) RunProcess() *TestBackfillSymbolProcessor {
+	t.BaseTestOperationProcessorNoItem.RunProcess()
+
+	return t
+}
+
+func (t *TestBackfillSymbolProcessor) IsValid() *TestBackfillSymbolProcessor {
+	t.BaseTestOperationProcessorNoItem.IsValid()
+
+	return t
+}
+
+func (t *TestBackfillSymbolProcessor) Decode(fileName string) *TestBackfillSymbolProcessor {
+	t.BaseTestOperationProcessorNoItem.Decode(fileName)
+
+	return t
+}
@@ -17,7 +17,7 @@ func NewTestBurnProcessor(tp *test.TestProcessor) TestBurnProcessor {
 }
 
 func (t *TestBurnProcessor) Create() *TestBurnProcessor {
-	t.Opr, _ = NewBurnProcessor()(
+	t.Opr, _ = NewBurnProcessor(base.GenesisHeight)(
 		base.GenesisHeight,
 		t.GetStateFunc,
 		nil, nil,
@@ -17,7 +17,7 @@ func NewTestMintProcessor(tp *test.TestProcessor) TestMintProcessor {
 }
 
 func (t *TestMintProcessor) Create() *TestMintProcessor {
-	t.Opr, _ = NewMintProcessor()(
+	t.Opr, _ = NewMintProcessor(base.GenesisHeight)(
 		base.GenesisHeight,
 		t.GetStateFunc,
 		nil, nil,
@@ -18,7 +18,7 @@ func NewTestRegisterTokenProcessor(tp *test.TestProcessor) TestRegisterTokenProc
 }
 
 func (t *TestRegisterTokenProcessor) Create() *TestRegisterTokenProcessor {
-	t.Opr, _ = NewRegisterModelProcessor()(
+	t.Opr, _ = NewRegisterModelProcessor(base.GenesisHeight, base.GenesisHeight)(
 		base.GenesisHeight,
 		t.GetStateFunc,
 		nil, nil,
@@ -0,0 +1,114 @@
+package token
+
+import (
+	"github.com/imfact-labs/currency-model/operation/test"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/token-model/types"
+)
+
+type TestReleaseSymbolProcessor struct {
+	*test.BaseTestOperationProcessorNoItem[ReleaseSymbol]
+}
+
+func NewTestReleaseSymbolProcessor(tp *test.TestProcessor) TestReleaseSymbolProcessor {
+	t := test.NewBaseTestOperationProcessorNoItem[ReleaseSymbol](tp)
+	return TestReleaseSymbolProcessor{BaseTestOperationProcessorNoItem: &t}
+}
+
+func (t *TestReleaseSymbolProcessor) Create() *TestReleaseSymbolProcessor {
+	t.Opr, _ = NewReleaseSymbolProcessor(base.GenesisHeight)(
+		base.GenesisHeight,
+		t.GetStateFunc,
+		nil, nil,
+	)
+	return t
+}
+
+func (t *TestReleaseSymbolProcessor) SetCurrency(
+	cid string, am int64, addr base.Address, target []ctypes.CurrencyID, instate bool,
+) *TestReleaseSymbolProcessor {
+	t.BaseTestOperationProcessorNoItem.SetCurrency(cid, am, addr, target, instate)
+
+	return t
+}
+
+func (t *TestReleaseSymbolProcessor) SetAmount(
+	am int64, cid ctypes.CurrencyID, target []ctypes.Amount,
+) *TestReleaseSymbolProcessor {
+	t.BaseTestOperationProcessorNoItem.SetAmount(am, cid, target)
+
+	return t
+}
+
+func (t *TestReleaseSymbolProcessor) SetContractAccount(
+	owner base.Address, priv string, amount int64, cid ctypes.CurrencyID, target []test.Account, inState bool,
+) *TestReleaseSymbolProcessor {
+	t.BaseTestOperationProcessorNoItem.SetContractAccount(owner, priv, amount, cid, target, inState)
+
+	return t
+}
+
+func (t *TestReleaseSymbolProcessor) SetAccount(
+	priv string, amount int64, cid ctypes.CurrencyID, target []test.Account, inState bool,
+) *TestReleaseSymbolProcessor {
+	t.BaseTestOperationProcessorNoItem.SetAccount(priv, amount, cid, target, inState)
+
+	return t
+}
+
+func (t *TestReleaseSymbolProcessor) LoadOperation(fileName string,
+) *TestReleaseSymbolProcessor {
+	t.BaseTestOperationProcessorNoItem.LoadOperation(fileName)
+
+	return t
+}
+
+func (t *TestReleaseSymbolProcessor) Print(fileName string,
+) *TestReleaseSymbolProcessor {
+	t.BaseTestOperationProcessorNoItem.Print(fileName)
+
+	return t
+}
+
+func (t *TestReleaseSymbolProcessor) MakeOperation(
+	sender base.Address, privatekey base.Privatekey, contract base.Address,
+	symbol string, currency ctypes.CurrencyID,
+) *TestReleaseSymbolProcessor {
+	op := NewReleaseSymbol(
+		NewReleaseSymbolFact(
+			[]byte("token"),
+			sender,
+			contract,
+			currency,
+			types.TokenSymbol(symbol),
+		))
+	_ = op.Sign(privatekey, t.NetworkID)
+	t.Op = op
+
+	return t
+}
+
+func (t *TestReleaseSymbolProcessor) RunPreProcess() *TestReleaseSymbolProcessor {
+	t.BaseTestOperationProcessorNoItem.RunPreProcess()
+
+	return t
+}
+
+func (t *TestReleaseSymbolProcessor) RunProcess() *TestReleaseSymbolProcessor {
+	t.BaseTestOperationProcessorNoItem.RunProcess()
+
+	return t
+}
+
+func (t *TestReleaseSymbolProcessor) IsValid() *TestReleaseSymbolProcessor {
+	t.BaseTestOperationProcessorNoItem.IsValid()
+
+	return t
+}
+
+func (t *TestReleaseSymbolProcessor) Decode(fileName string) *TestReleaseSymbolProcessor {
+	t.BaseTestOperationProcessorNoItem.Decode(fileName)
+
+	return t
+}
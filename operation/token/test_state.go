@@ -0,0 +1,47 @@
+package token
+
+import (
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/currency-model/operation/test"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/token-model/state"
+	"github.com/imfact-labs/token-model/types"
+)
+
+func NewTestDesignState(tp *test.TestProcessor, contract base.Address, design types.Design, inState bool) {
+	st := common.NewBaseState(
+		base.Height(1),
+		state.StateKeyDesign(contract.String()),
+		state.NewDesignStateValue(design),
+		nil,
+		[]util.Hash{},
+	)
+	tp.SetState(st, inState)
+}
+
+func NewTestTokenBalanceState(
+	tp *test.TestProcessor, contract, holder base.Address, amount int64, inState bool,
+) {
+	st := common.NewBaseState(
+		base.Height(1),
+		state.StateKeyTokenBalance(contract.String(), holder.String()),
+		state.NewTokenBalanceStateValue(common.NewBig(amount)),
+		nil,
+		[]util.Hash{},
+	)
+	tp.SetState(st, inState)
+}
+
+func NewTestSymbolState(
+	tp *test.TestProcessor, symbol string, contract base.Address, released bool, inState bool,
+) {
+	st := common.NewBaseState(
+		base.Height(1),
+		state.StateKeySymbol(symbol),
+		state.NewSymbolStateValue(contract, released),
+		nil,
+		[]util.Hash{},
+	)
+	tp.SetState(st, inState)
+}
@@ -0,0 +1,89 @@
+package token
+
+import (
+	"testing"
+
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/currency-model/operation/test"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/token-model/state"
+)
+
+type testTransferAccounts struct {
+	sender   test.Account
+	receiver test.Account
+	contract test.Account
+	currency ctypes.CurrencyID
+}
+
+func newTestTransferAccounts(tp *test.TestProcessor) testTransferAccounts {
+	var a testTransferAccounts
+
+	senders := make([]test.Account, 1)
+	receivers := make([]test.Account, 1)
+	contracts := make([]test.Account, 1)
+
+	tp.SetAccount(tp.NewPrivateKey("sender"), 1000, tp.GenesisCurrency, senders, true)
+	tp.SetAccount(tp.NewPrivateKey("receiver"), 1000, tp.GenesisCurrency, receivers, true)
+	tp.SetContractAccount(senders[0].Address(), tp.NewPrivateKey("contract"), 1000, tp.GenesisCurrency, contracts, true)
+
+	a.sender = senders[0]
+	a.receiver = receivers[0]
+	a.contract = contracts[0]
+	a.currency = tp.GenesisCurrency
+
+	return a
+}
+
+func checkTestTokenBalance(t *testing.T, tp *test.TestProcessor, contract, holder base.Address, expected int64) {
+	t.Helper()
+
+	st, found, err := tp.GetStateFunc(state.StateKeyTokenBalance(contract.String(), holder.String()))
+	switch {
+	case err != nil:
+		t.Fatalf("token balance of %v: %v", holder, err)
+	case !found:
+		t.Fatalf("token balance of %v not found", holder)
+	}
+
+	amount, err := state.StateTokenBalanceValue(st)
+	if err != nil {
+		t.Fatalf("token balance of %v: %v", holder, err)
+	}
+
+	if !amount.Equal(common.NewBig(expected)) {
+		t.Fatalf("token balance of %v, expected %d, got %v", holder, expected, amount)
+	}
+}
+
+func checkTestAllowance(
+	t *testing.T, tp *test.TestProcessor, contract, owner, approved base.Address, expected int64,
+) {
+	t.Helper()
+
+	st, found, err := tp.GetStateFunc(state.StateKeyDesign(contract.String()))
+	switch {
+	case err != nil:
+		t.Fatalf("design of %v: %v", contract, err)
+	case !found:
+		t.Fatalf("design of %v not found", contract)
+	}
+
+	design, err := state.StateDesignValue(st)
+	if err != nil {
+		t.Fatalf("design of %v: %v", contract, err)
+	}
+
+	amount := common.ZeroBig
+
+	if box := design.Policy().GetApproveBox(owner); box != nil {
+		if info := box.GetApproveInfo(approved); info != nil {
+			amount = info.Amount()
+		}
+	}
+
+	if !amount.Equal(common.NewBig(expected)) {
+		t.Fatalf("allowance of %v by %v, expected %d, got %v", approved, owner, expected, amount)
+	}
+}
@@ -0,0 +1,149 @@
+package token
+
+import (
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/currency-model/operation/test"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/token-model/types"
+)
+
+type TestTransferProcessor struct {
+	*test.BaseTestOperationProcessorWithItem[Transfer, TransferItem]
+}
+
+func NewTestTransferProcessor(tp *test.TestProcessor) TestTransferProcessor {
+	t := test.NewBaseTestOperationProcessorWithItem[Transfer, TransferItem](tp)
+	return TestTransferProcessor{BaseTestOperationProcessorWithItem: &t}
+}
+
+func (t *TestTransferProcessor) Create() *TestTransferProcessor {
+	t.Opr, _ = NewTransferProcessor()(
+		base.GenesisHeight,
+		t.GetStateFunc,
+		nil, nil,
+	)
+	return t
+}
+
+func (t *TestTransferProcessor) SetCurrency(
+	cid string, am int64, addr base.Address, target []ctypes.CurrencyID, instate bool,
+) *TestTransferProcessor {
+	t.BaseTestOperationProcessorWithItem.SetCurrency(cid, am, addr, target, instate)
+
+	return t
+}
+
+func (t *TestTransferProcessor) SetAmount(
+	am int64, cid ctypes.CurrencyID, target []ctypes.Amount,
+) *TestTransferProcessor {
+	t.BaseTestOperationProcessorWithItem.SetAmount(am, cid, target)
+
+	return t
+}
+
+func (t *TestTransferProcessor) SetContractAccount(
+	owner base.Address, priv string, amount int64, cid ctypes.CurrencyID, target []test.Account, inState bool,
+) *TestTransferProcessor {
+	t.BaseTestOperationProcessorWithItem.SetContractAccount(owner, priv, amount, cid, target, inState)
+
+	return t
+}
+
+func (t *TestTransferProcessor) SetAccount(
+	priv string, amount int64, cid ctypes.CurrencyID, target []test.Account, inState bool,
+) *TestTransferProcessor {
+	t.BaseTestOperationProcessorWithItem.SetAccount(priv, amount, cid, target, inState)
+
+	return t
+}
+
+func (t *TestTransferProcessor) SetDesign(
+	contract base.Address, symbol string, name string, decimal, totalSupply int64,
+	approveList []types.ApproveBox, inState bool,
+) *TestTransferProcessor {
+	NewTestDesignState(t.TestProcessor, contract, types.NewDesign(
+		types.TokenSymbol(symbol),
+		name,
+		common.NewBig(decimal),
+		types.NewPolicy(common.NewBig(totalSupply), approveList),
+	), inState)
+
+	return t
+}
+
+func (t *TestTransferProcessor) SetTokenBalance(
+	contract, holder base.Address, amount int64, inState bool,
+) *TestTransferProcessor {
+	NewTestTokenBalanceState(t.TestProcessor, contract, holder, amount, inState)
+
+	return t
+}
+
+func (t *TestTransferProcessor) LoadOperation(fileName string,
+) *TestTransferProcessor {
+	t.BaseTestOperationProcessorWithItem.LoadOperation(fileName)
+
+	return t
+}
+
+func (t *TestTransferProcessor) Print(fileName string,
+) *TestTransferProcessor {
+	t.BaseTestOperationProcessorWithItem.Print(fileName)
+
+	return t
+}
+
+func (t *TestTransferProcessor) MakeItem(
+	contract base.Address, receiver test.Account, amount int64, targetItems []TransferItem,
+) *TestTransferProcessor {
+	item := NewTransferItem(contract, receiver.Address(), common.NewBig(amount))
+	test.UpdateSlice[TransferItem](item, targetItems)
+
+	return t
+}
+
+func (t *TestTransferProcessor) MakeOperation(
+	sender base.Address, privatekey base.Privatekey, items []TransferItem, currency ctypes.CurrencyID,
+) *TestTransferProcessor {
+	op := NewTransfer(
+		NewTransferFact(
+			[]byte("token"),
+			sender,
+			items,
+			currency,
+		))
+	_ = op.Sign(privatekey, t.NetworkID)
+	t.Op = op
+
+	return t
+}
+
+func (t *TestTransferProcessor) RunPreProcess() *TestTransferProcessor {
+	t.BaseTestOperationProcessorWithItem.RunPreProcess()
+
+	return t
+}
+
+func (t *TestTransferProcessor) RunProcess() *TestTransferProcessor {
+	t.BaseTestOperationProcessorWithItem.RunProcess()
+
+	return t
+}
+
+func (t *TestTransferProcessor) IsValid() *TestTransferProcessor {
+	t.BaseTestOperationProcessorWithItem.IsValid()
+
+	return t
+}
+
+func (t *TestTransferProcessor) Decode(fileName string) *TestTransferProcessor {
+	t.BaseTestOperationProcessorWithItem.Decode(fileName)
+
+	return t
+}
+
+// NewTestTransferItem returns the transfer item of the addresses.
+func NewTestTransferItem(contract, receiver base.Address, amount int64) TransferItem {
+	return NewTransferItem(contract, receiver, common.NewBig(amount))
+}
@@ -0,0 +1,149 @@
+package token
+
+import (
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/currency-model/operation/test"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/token-model/types"
+)
+
+type TestTransferFromProcessor struct {
+	*test.BaseTestOperationProcessorWithItem[TransferFrom, TransferFromItem]
+}
+
+func NewTestTransferFromProcessor(tp *test.TestProcessor) TestTransferFromProcessor {
+	t := test.NewBaseTestOperationProcessorWithItem[TransferFrom, TransferFromItem](tp)
+	return TestTransferFromProcessor{BaseTestOperationProcessorWithItem: &t}
+}
+
+func (t *TestTransferFromProcessor) Create() *TestTransferFromProcessor {
+	t.Opr, _ = NewTransferFromProcessor(base.GenesisHeight)(
+		base.GenesisHeight,
+		t.GetStateFunc,
+		nil, nil,
+	)
+	return t
+}
+
+func (t *TestTransferFromProcessor) SetCurrency(
+	cid string, am int64, addr base.Address, target []ctypes.CurrencyID, instate bool,
+) *TestTransferFromProcessor {
+	t.BaseTestOperationProcessorWithItem.SetCurrency(cid, am, addr, target, instate)
+
+	return t
+}
+
+func (t *TestTransferFromProcessor) SetAmount(
+	am int64, cid ctypes.CurrencyID, target []ctypes.Amount,
+) *TestTransferFromProcessor {
+	t.BaseTestOperationProcessorWithItem.SetAmount(am, cid, target)
+
+	return t
+}
+
+func (t *TestTransferFromProcessor) SetContractAccount(
+	owner base.Address, priv string, amount int64, cid ctypes.CurrencyID, target []test.Account, inState bool,
+) *TestTransferFromProcessor {
+	t.BaseTestOperationProcessorWithItem.SetContractAccount(owner, priv, amount, cid, target, inState)
+
+	return t
+}
+
+func (t *TestTransferFromProcessor) SetAccount(
+	priv string, amount int64, cid ctypes.CurrencyID, target []test.Account, inState bool,
+) *TestTransferFromProcessor {
+	t.BaseTestOperationProcessorWithItem.SetAccount(priv, amount, cid, target, inState)
+
+	return t
+}
+
+func (t *TestTransferFromProcessor) SetDesign(
+	contract base.Address, symbol string, name string, decimal, totalSupply int64,
+	approveList []types.ApproveBox, inState bool,
+) *TestTransferFromProcessor {
+	NewTestDesignState(t.TestProcessor, contract, types.NewDesign(
+		types.TokenSymbol(symbol),
+		name,
+		common.NewBig(decimal),
+		types.NewPolicy(common.NewBig(totalSupply), approveList),
+	), inState)
+
+	return t
+}
+
+func (t *TestTransferFromProcessor) SetTokenBalance(
+	contract, holder base.Address, amount int64, inState bool,
+) *TestTransferFromProcessor {
+	NewTestTokenBalanceState(t.TestProcessor, contract, holder, amount, inState)
+
+	return t
+}
+
+func (t *TestTransferFromProcessor) LoadOperation(fileName string,
+) *TestTransferFromProcessor {
+	t.BaseTestOperationProcessorWithItem.LoadOperation(fileName)
+
+	return t
+}
+
+func (t *TestTransferFromProcessor) Print(fileName string,
+) *TestTransferFromProcessor {
+	t.BaseTestOperationProcessorWithItem.Print(fileName)
+
+	return t
+}
+
+func (t *TestTransferFromProcessor) MakeItem(
+	contract base.Address, receiver, target test.Account, amount int64, targetItems []TransferFromItem,
+) *TestTransferFromProcessor {
+	item := NewTransferFromItem(contract, receiver.Address(), target.Address(), common.NewBig(amount))
+	test.UpdateSlice[TransferFromItem](item, targetItems)
+
+	return t
+}
+
+func (t *TestTransferFromProcessor) MakeOperation(
+	sender base.Address, privatekey base.Privatekey, items []TransferFromItem, currency ctypes.CurrencyID,
+) *TestTransferFromProcessor {
+	op := NewTransferFrom(
+		NewTransferFromFact(
+			[]byte("token"),
+			sender,
+			items,
+			currency,
+		))
+	_ = op.Sign(privatekey, t.NetworkID)
+	t.Op = op
+
+	return t
+}
+
+func (t *TestTransferFromProcessor) RunPreProcess() *TestTransferFromProcessor {
+	t.BaseTestOperationProcessorWithItem.RunPreProcess()
+
+	return t
+}
+
+func (t *TestTransferFromProcessor) RunProcess() *TestTransferFromProcessor {
+	t.BaseTestOperationProcessorWithItem.RunProcess()
+
+	return t
+}
+
+func (t *TestTransferFromProcessor) IsValid() *TestTransferFromProcessor {
+	t.BaseTestOperationProcessorWithItem.IsValid()
+
+	return t
+}
+
+func (t *TestTransferFromProcessor) Decode(fileName string) *TestTransferFromProcessor {
+	t.BaseTestOperationProcessorWithItem.Decode(fileName)
+
+	return t
+}
+
+// NewTestTransferFromItem returns the transfer-from item of the addresses.
+func NewTestTransferFromItem(contract, receiver, target base.Address, amount int64) TransferFromItem {
+	return NewTransferFromItem(contract, receiver, target, common.NewBig(amount))
+}
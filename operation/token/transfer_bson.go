@@ -57,13 +57,7 @@ func (fact *TransferFact) DecodeBSON(b []byte, enc *bsonenc.Encoder) error {
 }
 
 func (op Transfer) MarshalBSON() ([]byte, error) {
-	return bsonenc.Marshal(
-		bson.M{
-			"_hint": op.Hint().String(),
-			"hash":  op.Hash().String(),
-			"fact":  op.Fact(),
-			"signs": op.Signs(),
-		})
+	return marshalOperationBSON(op)
 }
 
 func (op *Transfer) DecodeBSON(b []byte, enc *bsonenc.Encoder) error {
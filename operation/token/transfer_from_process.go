@@ -149,7 +149,10 @@ func (opp *TransferFromItemProcessor) Process(
 		apb.SetApproveInfo(types.NewApproveInfo(opp.sender, am))
 	}
 
-	approveBoxList[idx] = apb
+	policy := design.Policy()
+	policy.MergeApproveBox(apb)
+	opp.designs[opp.item.Contract().String()] = types.NewDesign(
+		design.Symbol(), design.Name(), design.Decimal(), policy)
 
 	receiver := opp.item.Receiver()
 	amount := opp.item.Amount()
@@ -189,9 +192,12 @@ func (opp *TransferFromItemProcessor) Close() {
 
 type TransferFromProcessor struct {
 	*base.BaseOperationProcessor
+	canonical bool
 }
 
-func NewTransferFromProcessor() ctypes.GetNewProcessor {
+// NewTransferFromProcessor returns the processor, which writes the canonical policy
+// from the canonical approve list height.
+func NewTransferFromProcessor(canonicalHeight base.Height) ctypes.GetNewProcessor {
 	return func(
 		height base.Height,
 		getStateFunc base.GetStateFunc,
@@ -213,6 +219,7 @@ func NewTransferFromProcessor() ctypes.GetNewProcessor {
 		}
 
 		opp.BaseOperationProcessor = b
+		opp.canonical = isCanonicalApproveListHeight(canonicalHeight, height)
 
 		return opp, nil
 	}
@@ -246,21 +253,42 @@ func (opp *TransferFromProcessor) PreProcess(
 			}
 		}
 
-		keyGenerator := state.NewStateKeyGenerator(fact.Items()[i].Contract().String())
-		st, _ := cstate.ExistsState(keyGenerator.Design(), "design", getStateFunc)
-		design, _ := state.StateDesignValue(st)
-		if _, found := designs[fact.Items()[i].contract.String()]; !found {
-			designs[fact.Items()[i].contract.String()] = *design
+		contract := fact.Items()[i].Contract().String()
+		if _, found := designs[contract]; found {
+			continue
+		}
+
+		keyGenerator := state.NewStateKeyGenerator(contract)
+		st, err := cstate.ExistsState(keyGenerator.Design(), "design", getStateFunc)
+		if err != nil {
+			return ctx, base.NewBaseOperationProcessReasonError(
+				common.ErrMPreProcess.Wrap(common.ErrMServiceNF).Errorf(
+					"token service state for contract account %v", contract)), nil
 		}
+
+		design, err := state.StateDesignValue(st)
+		if err != nil {
+			return ctx, base.NewBaseOperationProcessReasonError(
+				common.ErrMPreProcess.Wrap(common.ErrMStateValInvalid).Errorf(
+					"token service state value for contract account %v", contract)), nil
+		}
+
+		designs[contract] = *design
 	}
 
+	totalAmounts := map[string]common.Big{}
+
 	for holder, required := range requiredMap {
-		_, err := PrepareSenderTotalAmounts(holder, required, getStateFunc)
+		amounts, err := PrepareSenderTotalAmounts(holder, required, getStateFunc)
 		if err != nil {
 			return ctx, base.NewBaseOperationProcessReasonError(
 				common.ErrMPreProcess.
 					Errorf("%v", err)), nil
 		}
+
+		for key, amount := range amounts {
+			totalAmounts[key] = amount
+		}
 	}
 
 	for i := range fact.Items() {
@@ -285,6 +313,11 @@ func (opp *TransferFromProcessor) PreProcess(
 		t.Close()
 	}
 
+	if err := state.ReserveTokenBalances(ctx, totalAmounts, getStateFunc); err != nil {
+		return ctx, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.Wrap(common.ErrMValueInvalid).Errorf("%v", err)), nil
+	}
+
 	return ctx, nil, nil
 }
 
@@ -345,10 +378,7 @@ func (opp *TransferFromProcessor) Process( // nolint:dupl
 
 	for k, de := range designs {
 		g := state.NewStateKeyGenerator(k)
-		stateMergeValues = append(stateMergeValues, cstate.NewStateMergeValue(
-			g.Design(),
-			state.NewDesignStateValue(de),
-		))
+		stateMergeValues = append(stateMergeValues, state.NewDesignStateMergeValue(g.Design(), designOfHeight(opp.canonical, de)))
 	}
 
 	for holder, required := range requiredMap {
@@ -0,0 +1,141 @@
+package token
+
+import (
+	"testing"
+
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/currency-model/operation/test"
+	"github.com/imfact-labs/token-model/types"
+)
+
+func TestTransferFromProcess(t *testing.T) {
+	cases := []struct {
+		name      string
+		design    bool
+		allowance int64
+		amount    int64
+		receiver  func(testTransferAccounts, *test.TestProcessor) test.Account
+		err       bool
+	}{
+		{name: "transfer-from", design: true, allowance: 50, amount: 10},
+		{name: "design missing", design: false, allowance: 50, amount: 10, err: true},
+		{name: "allowance insufficient", design: true, allowance: 5, amount: 10, err: true},
+		{
+			name: "receiver is contract account", design: true, allowance: 50, amount: 10, err: true,
+			receiver: func(a testTransferAccounts, tp *test.TestProcessor) test.Account {
+				accounts := make([]test.Account, 1)
+				tp.SetContractAccount(a.sender.Address(), tp.NewPrivateKey("other-contract"), 0,
+					a.currency, accounts, true)
+
+				return accounts[0]
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			tp := &test.TestProcessor{}
+			tp.Setup(test.NewMockStateGetter())
+
+			a := newTestTransferAccounts(tp)
+
+			targets := make([]test.Account, 1)
+			tp.SetAccount(tp.NewPrivateKey("target"), 1000, a.currency, targets, true)
+			target := targets[0]
+
+			receiver := a.receiver
+			if c.receiver != nil {
+				receiver = c.receiver(a, tp)
+			}
+
+			p := NewTestTransferFromProcessor(tp)
+			items := make([]TransferFromItem, 1)
+
+			if c.design {
+				p.SetDesign(a.contract.Address(), "ABC", "abc", 0, 100, []types.ApproveBox{
+					types.NewApproveBox(target.Address(), []types.ApproveInfo{
+						types.NewApproveInfo(a.sender.Address(), common.NewBig(c.allowance)),
+					}),
+				}, true)
+			}
+
+			p.SetTokenBalance(a.contract.Address(), target.Address(), 100, true).
+				MakeItem(a.contract.Address(), receiver, target, c.amount, items).
+				MakeOperation(a.sender.Address(), a.sender.Priv(), items, a.currency).
+				Create().
+				IsValid()
+
+			if err := p.Error(); err != nil {
+				t.Fatalf("invalid operation: %v", err)
+			}
+
+			p.RunPreProcess()
+
+			switch err := p.Error(); {
+			case c.err && err == nil:
+				t.Fatal("expected preprocess error")
+			case c.err:
+				return
+			case err != nil:
+				t.Fatalf("preprocess: %v", err)
+			}
+
+			p.RunProcess()
+
+			if err := p.Error(); err != nil {
+				t.Fatalf("process: %v", err)
+			}
+
+			checkTestTokenBalance(t, tp, a.contract.Address(), target.Address(), 100-c.amount)
+			checkTestTokenBalance(t, tp, a.contract.Address(), receiver.Address(), c.amount)
+			checkTestAllowance(t, tp, a.contract.Address(), target.Address(), a.sender.Address(),
+				c.allowance-c.amount)
+		})
+	}
+}
+
+func TestTransferFromFactDuplicateItems(t *testing.T) {
+	tp := &test.TestProcessor{}
+	tp.Setup(test.NewMockStateGetter())
+
+	a := newTestTransferAccounts(tp)
+
+	targets := make([]test.Account, 1)
+	tp.SetAccount(tp.NewPrivateKey("target"), 1000, a.currency, targets, true)
+	target := targets[0].Address()
+
+	cases := []struct {
+		name  string
+		items []TransferFromItem
+		err   bool
+	}{
+		{
+			name: "single item",
+			items: []TransferFromItem{
+				NewTestTransferFromItem(a.contract.Address(), a.receiver.Address(), target, 10),
+			},
+		},
+		{
+			name: "duplicate items",
+			items: []TransferFromItem{
+				NewTestTransferFromItem(a.contract.Address(), a.receiver.Address(), target, 10),
+				NewTestTransferFromItem(a.contract.Address(), a.receiver.Address(), target, 20),
+			},
+			err: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			p := NewTestTransferFromProcessor(tp)
+			p.MakeOperation(a.sender.Address(), a.sender.Priv(), c.items, a.currency).IsValid()
+
+			switch err := p.Error(); {
+			case c.err && err == nil:
+				t.Fatal("expected invalid operation")
+			case !c.err && err != nil:
+				t.Fatalf("invalid operation: %v", err)
+			}
+		})
+	}
+}
@@ -155,7 +155,7 @@ func (opp *TransferProcessor) PreProcess(
 		}
 	}
 
-	_, err := PrepareSenderTotalAmounts(fact.Sender().String(), required, getStateFunc)
+	totalAmounts, err := PrepareSenderTotalAmounts(fact.Sender().String(), required, getStateFunc)
 	if err != nil {
 		return nil, base.NewBaseOperationProcessReasonError(
 			common.ErrMPreProcess.Wrap(common.ErrMValueInvalid).Errorf(err.Error())), nil
@@ -199,6 +199,11 @@ func (opp *TransferProcessor) PreProcess(
 		}
 	}
 
+	if err := state.ReserveTokenBalances(ctx, totalAmounts, getStateFunc); err != nil {
+		return ctx, base.NewBaseOperationProcessReasonError(
+			common.ErrMPreProcess.Wrap(common.ErrMValueInvalid).Errorf("%v", err)), nil
+	}
+
 	return ctx, nil, nil
 }
 
@@ -0,0 +1,125 @@
+package token
+
+import (
+	"testing"
+
+	"github.com/imfact-labs/currency-model/operation/test"
+)
+
+func TestTransferProcess(t *testing.T) {
+	cases := []struct {
+		name     string
+		design   bool
+		balance  int64
+		amount   int64
+		receiver func(testTransferAccounts, *test.TestProcessor) test.Account
+		err      bool
+	}{
+		{name: "transfer", design: true, balance: 100, amount: 10},
+		{name: "design missing", design: false, balance: 100, amount: 10, err: true},
+		{name: "balance insufficient", design: true, balance: 5, amount: 10, err: true},
+		{
+			name: "receiver is contract account", design: true, balance: 100, amount: 10, err: true,
+			receiver: func(a testTransferAccounts, tp *test.TestProcessor) test.Account {
+				accounts := make([]test.Account, 1)
+				tp.SetContractAccount(a.sender.Address(), tp.NewPrivateKey("other-contract"), 0,
+					a.currency, accounts, true)
+
+				return accounts[0]
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			tp := &test.TestProcessor{}
+			tp.Setup(test.NewMockStateGetter())
+
+			a := newTestTransferAccounts(tp)
+
+			receiver := a.receiver
+			if c.receiver != nil {
+				receiver = c.receiver(a, tp)
+			}
+
+			p := NewTestTransferProcessor(tp)
+			items := make([]TransferItem, 1)
+
+			if c.design {
+				p.SetDesign(a.contract.Address(), "ABC", "abc", 0, c.balance, nil, true)
+			}
+
+			p.SetTokenBalance(a.contract.Address(), a.sender.Address(), c.balance, true).
+				MakeItem(a.contract.Address(), receiver, c.amount, items).
+				MakeOperation(a.sender.Address(), a.sender.Priv(), items, a.currency).
+				Create().
+				IsValid()
+
+			if err := p.Error(); err != nil {
+				t.Fatalf("invalid operation: %v", err)
+			}
+
+			p.RunPreProcess()
+
+			switch err := p.Error(); {
+			case c.err && err == nil:
+				t.Fatal("expected preprocess error")
+			case c.err:
+				return
+			case err != nil:
+				t.Fatalf("preprocess: %v", err)
+			}
+
+			p.RunProcess()
+
+			if err := p.Error(); err != nil {
+				t.Fatalf("process: %v", err)
+			}
+
+			checkTestTokenBalance(t, tp, a.contract.Address(), a.sender.Address(), c.balance-c.amount)
+			checkTestTokenBalance(t, tp, a.contract.Address(), receiver.Address(), c.amount)
+		})
+	}
+}
+
+func TestTransferFactDuplicateItems(t *testing.T) {
+	tp := &test.TestProcessor{}
+	tp.Setup(test.NewMockStateGetter())
+
+	a := newTestTransferAccounts(tp)
+
+	cases := []struct {
+		name  string
+		items []TransferItem
+		err   bool
+	}{
+		{
+			name: "single item",
+			items: []TransferItem{
+				NewTestTransferItem(a.contract.Address(), a.receiver.Address(), 10),
+			},
+		},
+		{
+			name: "duplicate items",
+			items: []TransferItem{
+				NewTestTransferItem(a.contract.Address(), a.receiver.Address(), 10),
+				NewTestTransferItem(a.contract.Address(), a.receiver.Address(), 20),
+			},
+			err: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			p := NewTestTransferProcessor(tp)
+			p.MakeOperation(a.sender.Address(), a.sender.Priv(), c.items, a.currency).IsValid()
+
+			switch err := p.Error(); {
+			case c.err && err == nil:
+				t.Fatal("expected invalid operation")
+			case !c.err && err != nil:
+				t.Fatalf("invalid operation: %v", err)
+			}
+		})
+	}
+}
@@ -16,6 +16,7 @@ var AddedHinters = []encoder.DecodeDetail{
 
 	{Hint: state.DesignStateValueHint, Instance: state.DesignStateValue{}},
 	{Hint: state.TokenBalanceStateValueHint, Instance: state.TokenBalanceStateValue{}},
+	{Hint: state.SymbolStateValueHint, Instance: state.SymbolStateValue{}},
 
 	{Hint: token.RegisterModelHint, Instance: token.RegisterModel{}},
 	{Hint: token.MintHint, Instance: token.Mint{}},
@@ -26,6 +27,10 @@ var AddedHinters = []encoder.DecodeDetail{
 	{Hint: token.TransferItemHint, Instance: token.TransferItem{}},
 	{Hint: token.TransferFromHint, Instance: token.TransferFrom{}},
 	{Hint: token.TransferFromItemHint, Instance: token.TransferFromItem{}},
+	{Hint: token.ReleaseSymbolHint, Instance: token.ReleaseSymbol{}},
+	{Hint: token.BackfillSymbolHint, Instance: token.BackfillSymbol{}},
+	{Hint: token.RegisterGenesisTokenHint, Instance: token.RegisterGenesisToken{}},
+	{Hint: token.RegisterGenesisTokenFactHint, Instance: token.RegisterGenesisTokenFact{}},
 }
 
 var AddedSupportedHinters = []encoder.DecodeDetail{
@@ -35,4 +40,6 @@ var AddedSupportedHinters = []encoder.DecodeDetail{
 	{Hint: token.ApproveFactHint, Instance: token.ApproveFact{}},
 	{Hint: token.TransferFactHint, Instance: token.TransferFact{}},
 	{Hint: token.TransferFromFactHint, Instance: token.TransferFromFact{}},
+	{Hint: token.ReleaseSymbolFactHint, Instance: token.ReleaseSymbolFact{}},
+	{Hint: token.BackfillSymbolFactHint, Instance: token.BackfillSymbolFact{}},
 }
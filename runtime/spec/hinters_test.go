@@ -0,0 +1,359 @@
+package spec_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/imfact-labs/currency-model/common"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/currency-model/utils/bsonenc"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/encoder"
+	jsonenc "github.com/imfact-labs/mitum2/util/encoder/json"
+	"github.com/imfact-labs/mitum2/util/hint"
+	"github.com/imfact-labs/token-model/operation/token"
+	"github.com/imfact-labs/token-model/runtime/spec"
+	"github.com/imfact-labs/token-model/runtime/steps"
+	"github.com/imfact-labs/token-model/state"
+	"github.com/imfact-labs/token-model/types"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+var testNetworkID = base.NetworkID("hinters-test")
+
+func newTestEncoders(t *testing.T) (*encoder.Encoders, encoder.Encoder) {
+	t.Helper()
+
+	jenc := jsonenc.NewEncoder()
+	encs := encoder.NewEncoders(jenc, jenc)
+
+	benc := bsonenc.NewEncoder()
+	if err := encs.AddEncoder(benc); err != nil {
+		t.Fatalf("add bson encoder: %v", err)
+	}
+
+	if err := steps.LoadHinters(encs); err != nil {
+		t.Fatalf("load hinters: %v", err)
+	}
+
+	return encs, benc
+}
+
+func signTestOperation[T any, PT interface {
+	*T
+	Sign(base.Privatekey, base.NetworkID) error
+}](t *testing.T, op T, priv base.Privatekey) T {
+	t.Helper()
+
+	if err := PT(&op).Sign(priv, testNetworkID); err != nil {
+		t.Fatalf("sign %T: %v", op, err)
+	}
+
+	return op
+}
+
+// testHinterInstances returns the sample instance of every hint in
+// spec.AddedHinters.
+func testHinterInstances(t *testing.T) map[string]interface{} {
+	t.Helper()
+
+	priv := base.NewMPrivatekey()
+	sender := ctypes.NewAddress("0xCE8133c8498F5034296947645dd9E83c9c5288E9")
+	contract := ctypes.NewAddress("0x6DC072838c96e06B4bbc763A8D0687Dc4a22f95E")
+	receiver := ctypes.NewAddress("0x936E736056C2B51702b505B72433C8A305ad2675")
+	target := ctypes.NewAddress("0xE7Cc9f5F0E730048a6ff16C4e2f1B7b2546a1587")
+
+	ft := []byte("hinters-test")
+	cid := ctypes.CurrencyID("MCC")
+	symbol := types.TokenSymbol("HNT")
+
+	info := types.NewApproveInfo(receiver, common.NewBig(30))
+	box := types.NewApproveBox(sender, []types.ApproveInfo{info})
+	policy := types.NewPolicy(common.NewBig(1000), []types.ApproveBox{box})
+	design := types.NewDesign(symbol, "hinters token", common.NewBig(18), policy)
+
+	approveItem := token.NewApproveItem(contract, receiver, common.NewBig(30))
+	transferItem := token.NewTransferItem(contract, receiver, common.NewBig(20))
+	transferFromItem := token.NewTransferFromItem(contract, target, sender, common.NewBig(15))
+
+	genesisFact := token.NewRegisterGenesisTokenFact(
+		[]byte("genesis"), priv.Publickey(), sender, contract, symbol, "hinters token", common.NewBig(18),
+		[]token.GenesisBalance{token.NewGenesisBalance(sender, common.NewBig(1000))},
+		[]types.ApproveBox{box},
+	)
+
+	instances := []interface{}{
+		info,
+		box,
+		policy,
+		design,
+		state.NewDesignStateValue(design),
+		state.NewTokenBalanceStateValue(common.NewBig(900)),
+		state.NewSymbolStateValue(contract, false),
+		signTestOperation(t, token.NewRegisterModel(token.NewRegisterModelFact(
+			ft, sender, contract, cid, symbol, "hinters token", common.NewBig(18), common.NewBig(1000),
+		)), priv),
+		signTestOperation(t, token.NewMint(token.NewMintFact(
+			ft, sender, contract, cid, receiver, common.NewBig(100),
+		)), priv),
+		signTestOperation(t, token.NewBurn(token.NewBurnFact(
+			ft, sender, contract, cid, sender, common.NewBig(10),
+		)), priv),
+		signTestOperation(t, token.NewApprove(token.NewApproveFact(
+			ft, sender, []token.ApproveItem{approveItem}, cid,
+		)), priv),
+		approveItem,
+		signTestOperation(t, token.NewTransfer(token.NewTransferFact(
+			ft, sender, []token.TransferItem{transferItem}, cid,
+		)), priv),
+		transferItem,
+		signTestOperation(t, token.NewTransferFrom(token.NewTransferFromFact(
+			ft, receiver, []token.TransferFromItem{transferFromItem}, cid,
+		)), priv),
+		transferFromItem,
+		signTestOperation(t, token.NewReleaseSymbol(token.NewReleaseSymbolFact(
+			ft, sender, contract, cid, symbol,
+		)), priv),
+		signTestOperation(t, token.NewBackfillSymbol(token.NewBackfillSymbolFact(
+			ft, sender, contract, cid, symbol,
+		)), priv),
+		signTestOperation(t, token.NewRegisterGenesisToken(genesisFact), priv),
+		genesisFact,
+	}
+
+	m := map[string]interface{}{}
+
+	for i := range instances {
+		hr, ok := instances[i].(hint.Hinter)
+		if !ok {
+			t.Fatalf("expected hint.Hinter, not %T", instances[i])
+		}
+
+		m[hr.Hint().String()] = instances[i]
+	}
+
+	return m
+}
+
+func TestAddedHintersRoundTrip(t *testing.T) {
+	encs, benc := newTestEncoders(t)
+	instances := testHinterInstances(t)
+
+	for _, d := range spec.AddedHinters {
+		t.Run(d.Hint.String(), func(t *testing.T) {
+			i, found := instances[d.Hint.String()]
+			if !found {
+				t.Fatalf("no sample instance of %v", d.Hint)
+			}
+
+			jb, err := util.MarshalJSON(i)
+			if err != nil {
+				t.Fatalf("marshal json: %v", err)
+			}
+
+			checkRoundTrip(t, encs.JSON(), d.Hint, jb, util.MarshalJSON)
+
+			bb, err := bsonenc.Marshal(i)
+			if err != nil {
+				t.Fatalf("marshal bson: %v", err)
+			}
+
+			checkRoundTrip(t, benc, d.Hint, bb, bsonenc.Marshal)
+		})
+	}
+}
+
+// checkRoundTrip decodes b and compares the marshaled decoded with b; the
+// bson is compared by the documents, because the keys of bson marshaled from
+// map are not ordered and the time of bson is in milliseconds.
+func checkRoundTrip(
+	t *testing.T, enc encoder.Encoder, ht hint.Hint, b []byte, marshal func(interface{}) ([]byte, error),
+) {
+	t.Helper()
+
+	decoded, err := enc.Decode(b)
+	if err != nil {
+		t.Fatalf("decode by %v: %v", enc.Hint(), err)
+	}
+
+	if hr, ok := decoded.(hint.Hinter); !ok || !hr.Hint().Equal(ht) {
+		t.Fatalf("decoded by %v, expected %v, got %T", enc.Hint(), ht, decoded)
+	}
+
+	db, err := marshal(decoded)
+	if err != nil {
+		t.Fatalf("marshal decoded by %v: %v", enc.Hint(), err)
+	}
+
+	if enc.Hint().Type() != bsonenc.BSONEncoderHint.Type() {
+		if !bytes.Equal(b, db) {
+			t.Fatalf("decoded by %v not matched; expected=%s decoded=%s", enc.Hint(), b, db)
+		}
+
+		return
+	}
+
+	var a, c bson.M
+
+	if err := bson.Unmarshal(b, &a); err != nil {
+		t.Fatalf("unmarshal bson: %v", err)
+	}
+
+	if err := bson.Unmarshal(db, &c); err != nil {
+		t.Fatalf("unmarshal bson of decoded: %v", err)
+	}
+
+	if !reflect.DeepEqual(normalizeBSON(a), normalizeBSON(c)) {
+		t.Fatalf("decoded by %v not matched; expected=%v decoded=%v", enc.Hint(), a, c)
+	}
+}
+
+func normalizeBSON(i interface{}) interface{} {
+	switch t := i.(type) {
+	case bson.M:
+		m := map[string]interface{}{}
+		for k, v := range t {
+			m[k] = normalizeBSON(v)
+		}
+
+		return m
+	case bson.D:
+		m := map[string]interface{}{}
+		for j := range t {
+			m[t[j].Key] = normalizeBSON(t[j].Value)
+		}
+
+		return m
+	case bson.A:
+		a := make([]interface{}, len(t))
+		for j := range t {
+			a[j] = normalizeBSON(t[j])
+		}
+
+		return a
+	default:
+		return i
+	}
+}
+
+// testLegacyDesignStateValue is the design state value stored before
+// PolicyHint v0.0.2; neither the approve list nor the approved is sorted by
+// account.
+const testLegacyDesignStateValue = `{"_hint":"mitum-token-design-state-value-v0.0.1","design":{"_hint":"mitum-token-design-v0.0.1","symbol":"HNT","name":"hinters token","decimal":"18","policy":{"_hint":"mitum-token-policy-v0.0.1","total_supply":"1000","approve_list":[{"_hint":"mitum-token-approve-box-v0.0.1","account":"0xE7Cc9f5F0E730048a6ff16C4e2f1B7b2546a1587fca","approved":[{"_hint":"mitum-token-approve-info-v0.0.1","account":"0xCE8133c8498F5034296947645dd9E83c9c5288E9fca","amount":"5"},{"_hint":"mitum-token-approve-info-v0.0.1","account":"0x936E736056C2B51702b505B72433C8A305ad2675fca","amount":"30"}]},{"_hint":"mitum-token-approve-box-v0.0.1","account":"0xCE8133c8498F5034296947645dd9E83c9c5288E9fca","approved":[{"_hint":"mitum-token-approve-info-v0.0.1","account":"0x936E736056C2B51702b505B72433C8A305ad2675fca","amount":"7"}]}]}}}` // revive:disable-line:line-length-limit
+
+func TestLegacyDesignStateValue(t *testing.T) {
+	encs, benc := newTestEncoders(t)
+
+	decode := func(t *testing.T, enc encoder.Encoder, b []byte) types.Design {
+		t.Helper()
+
+		i, err := enc.Decode(b)
+		if err != nil {
+			t.Fatalf("decode by %v: %v", enc.Hint(), err)
+		}
+
+		v, ok := i.(state.DesignStateValue)
+		if !ok {
+			t.Fatalf("expected DesignStateValue, not %T", i)
+		}
+
+		if err := v.IsValid(nil); err != nil {
+			t.Fatalf("invalid legacy design state value: %v", err)
+		}
+
+		return v.Design()
+	}
+
+	accounts := func(policy types.Policy) []string {
+		var l []string
+
+		for _, box := range policy.ApproveList() {
+			l = append(l, box.Account().String())
+
+			for _, info := range box.Approved() {
+				l = append(l, info.Account().String())
+			}
+		}
+
+		return l
+	}
+
+	design := decode(t, encs.JSON(), []byte(testLegacyDesignStateValue))
+
+	if !design.Policy().IsLegacy() {
+		t.Fatalf("expected legacy policy, not %v", design.Policy().Hint())
+	}
+
+	expected := []string{
+		"0xE7Cc9f5F0E730048a6ff16C4e2f1B7b2546a1587fca",
+		"0xCE8133c8498F5034296947645dd9E83c9c5288E9fca",
+		"0x936E736056C2B51702b505B72433C8A305ad2675fca",
+		"0xCE8133c8498F5034296947645dd9E83c9c5288E9fca",
+		"0x936E736056C2B51702b505B72433C8A305ad2675fca",
+	}
+
+	if got := accounts(design.Policy()); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("order of legacy policy not kept; expected=%v got=%v", expected, got)
+	}
+
+	t.Run("json", func(t *testing.T) {
+		b, err := util.MarshalJSON(state.NewDesignStateValue(design))
+		if err != nil {
+			t.Fatalf("marshal json: %v", err)
+		}
+
+		if string(b) != testLegacyDesignStateValue {
+			t.Fatalf("legacy design state value not matched; expected=%s marshaled=%s", testLegacyDesignStateValue, b)
+		}
+	})
+
+	t.Run("bson", func(t *testing.T) {
+		b, err := bsonenc.Marshal(state.NewDesignStateValue(design))
+		if err != nil {
+			t.Fatalf("marshal bson: %v", err)
+		}
+
+		decoded := decode(t, benc, b)
+
+		if !decoded.Policy().IsLegacy() {
+			t.Fatalf("expected legacy policy, not %v", decoded.Policy().Hint())
+		}
+
+		if got := accounts(decoded.Policy()); !reflect.DeepEqual(got, expected) {
+			t.Fatalf("order of legacy policy not kept; expected=%v got=%v", expected, got)
+		}
+	})
+
+	t.Run("canonical", func(t *testing.T) {
+		policy := design.Policy().Canonical()
+
+		if policy.IsLegacy() || !policy.Hint().Equal(types.PolicyHint) {
+			t.Fatalf("expected canonical policy, not %v", policy.Hint())
+		}
+
+		if err := policy.IsValid(nil); err != nil {
+			t.Fatalf("invalid canonical policy: %v", err)
+		}
+
+		sorted := []string{
+			"0xCE8133c8498F5034296947645dd9E83c9c5288E9fca",
+			"0x936E736056C2B51702b505B72433C8A305ad2675fca",
+			"0xE7Cc9f5F0E730048a6ff16C4e2f1B7b2546a1587fca",
+			"0x936E736056C2B51702b505B72433C8A305ad2675fca",
+			"0xCE8133c8498F5034296947645dd9E83c9c5288E9fca",
+		}
+
+		if got := accounts(policy); !reflect.DeepEqual(got, sorted) {
+			t.Fatalf("canonical policy not sorted; expected=%v got=%v", sorted, got)
+		}
+
+		b, err := util.MarshalJSON(policy)
+		if err != nil {
+			t.Fatalf("marshal json: %v", err)
+		}
+
+		checkRoundTrip(t, encs.JSON(), types.PolicyHint, b, util.MarshalJSON)
+	})
+}
@@ -0,0 +1,549 @@
+package steps
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+
+	"github.com/imfact-labs/currency-model/operation/currency"
+	isaacoperation "github.com/imfact-labs/currency-model/operation/isaac"
+	ccstate "github.com/imfact-labs/currency-model/state/currency"
+	currencytypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/isaac"
+	"github.com/imfact-labs/mitum2/launch"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/encoder"
+	"github.com/imfact-labs/mitum2/util/hint"
+	"github.com/imfact-labs/mitum2/util/logging"
+	"github.com/imfact-labs/token-model/operation/token"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+// GenesisBlockGenerator is the genesis block generator of currency model with
+// RegisterGenesisToken; the generator of currency model can not be extended
+// by the other facts, so it is kept same with it except the token facts.
+type GenesisBlockGenerator struct {
+	local                base.LocalNode
+	encs                 *encoder.Encoders
+	db                   isaac.Database
+	proposal             base.ProposalSignFact
+	ivp                  base.INITVoteproof
+	avp                  base.ACCEPTVoteproof
+	loadImportedBlockMap func() (base.BlockMap, bool, error)
+	*logging.Logging
+	dataroot  string
+	networkID base.NetworkID
+	facts     []base.Fact
+	ops       []base.Operation
+	ctx       context.Context
+}
+
+func NewGenesisBlockGenerator(
+	local base.LocalNode,
+	networkID base.NetworkID,
+	encs *encoder.Encoders,
+	db isaac.Database,
+	dataroot string,
+	facts []base.Fact,
+	loadImportedBlockMap func() (base.BlockMap, bool, error),
+	ctx context.Context,
+) *GenesisBlockGenerator {
+	return &GenesisBlockGenerator{
+		Logging: logging.NewLogging(func(zctx zerolog.Context) zerolog.Context {
+			return zctx.Str("module", "genesis-block-generator")
+		}),
+		local:                local,
+		networkID:            networkID,
+		encs:                 encs,
+		db:                   db,
+		dataroot:             dataroot,
+		facts:                facts,
+		loadImportedBlockMap: loadImportedBlockMap,
+		ctx:                  ctx,
+	}
+}
+
+func (g *GenesisBlockGenerator) Generate() (base.BlockMap, error) {
+	e := util.StringError("generate genesis block")
+
+	if err := g.generateOperations(); err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	if err := g.newProposal(nil); err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	if err := g.process(); err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	switch blockmap, found, err := g.loadImportedBlockMap(); {
+	case err != nil:
+		return nil, e.Wrap(err)
+	case !found:
+		return nil, util.ErrNotFound.Errorf("Blockmap")
+	default:
+		if err := blockmap.IsValid(g.networkID); err != nil {
+			return nil, e.Wrap(err)
+		}
+
+		g.Log().Info().Interface("blockmap", blockmap).Msg("genesis block generated")
+
+		if err := g.closeDatabase(); err != nil {
+			return nil, e.Wrap(err)
+		}
+
+		return blockmap, nil
+	}
+}
+
+func (g *GenesisBlockGenerator) generateOperations() error {
+	g.ops = make([]base.Operation, len(g.facts))
+
+	types := map[string]struct{}{}
+	contracts := map[string]struct{}{}
+	symbols := map[string]struct{}{}
+
+	for i := range g.facts {
+		fact := g.facts[i]
+
+		var err error
+
+		hinter, ok := fact.(hint.Hinter)
+		if !ok {
+			return errors.Errorf("Fact does not support Hinter")
+		}
+
+		switch ht := hinter.Hint(); {
+		case ht.IsCompatible(isaacoperation.SuffrageGenesisJoinFactHint):
+			if _, found := types[ht.String()]; found {
+				return errors.Errorf("Multiple join operation found")
+			}
+
+			g.ops[i], err = g.joinOperation(fact)
+		case ht.IsCompatible(isaacoperation.GenesisNetworkPolicyFactHint):
+			if _, found := types[ht.String()]; found {
+				return errors.Errorf("Multiple network policy operation found")
+			}
+
+			g.ops[i], err = g.networkPolicyOperation(fact)
+		case ht.IsCompatible(currency.RegisterGenesisCurrencyFactHint):
+			if _, found := types[ht.String()]; found {
+				return errors.Errorf("Multiple RegisterGenesisCurrency operation found")
+			}
+
+			g.ops[i], err = g.registerGenesisCurrencyOperation(fact, g.networkID)
+		case ht.IsCompatible(token.RegisterGenesisTokenFactHint):
+			g.ops[i], err = g.registerGenesisTokenOperation(fact, contracts, symbols)
+		default:
+			return errors.Errorf("Unknown genesis fact, %q", ht)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		types[hinter.Hint().String()] = struct{}{}
+	}
+
+	return nil
+}
+
+func (g *GenesisBlockGenerator) joinOperation(i base.Fact) (base.Operation, error) {
+	e := util.StringError("make join operation")
+
+	basefact, ok := i.(isaacoperation.SuffrageGenesisJoinFact)
+	if !ok {
+		return nil, e.WithMessage(nil, "expected SuffrageGenesisJoinFact, not %T", i)
+	}
+
+	fact := isaacoperation.NewSuffrageGenesisJoinFact(basefact.Nodes(), g.networkID)
+
+	if err := fact.IsValid(g.networkID); err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	op := isaacoperation.NewSuffrageGenesisJoin(fact)
+	if err := op.Sign(g.local.Privatekey(), g.networkID); err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	g.Log().Debug().Interface("operation", op).Msg("genesis join operation created")
+
+	return op, nil
+}
+
+func (g *GenesisBlockGenerator) networkPolicyOperation(i base.Fact) (base.Operation, error) {
+	e := util.StringError("make networkPolicy operation")
+
+	basefact, ok := i.(isaacoperation.GenesisNetworkPolicyFact)
+	if !ok {
+		return nil, e.WithMessage(nil, "expected GenesisNetworkPolicyFact, not %T", i)
+	}
+
+	fact := isaacoperation.NewGenesisNetworkPolicyFact(basefact.Policy())
+
+	if err := fact.IsValid(nil); err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	op := isaacoperation.NewGenesisNetworkPolicy(fact)
+	if err := op.Sign(g.local.Privatekey(), g.networkID); err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	g.Log().Debug().Interface("operation", op).Msg("genesis network policy operation created")
+
+	return op, nil
+}
+
+func (g *GenesisBlockGenerator) registerGenesisCurrencyOperation(i base.Fact, token []byte) (base.Operation, error) {
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Println("Panic:", r)
+			os.Exit(1)
+		}
+	}()
+	e := util.StringError("make registerGenesisCurrency operation")
+
+	basefact, ok := i.(currency.RegisterGenesisCurrencyFact)
+	if !ok {
+		return nil, e.WithMessage(nil, "expected RegisterGenesisCurrencyFact, not %T", i)
+	}
+	acks, err := currencytypes.NewBaseAccountKeys(basefact.Keys().Keys(), basefact.Keys().Threshold())
+	if err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	var design launch.NodeDesign
+	err = util.LoadFromContextOK(g.ctx,
+		launch.DesignContextKey, &design,
+	)
+	if err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	if !basefact.GenesisNodeKey().Equal(design.Privatekey.Publickey()) {
+		panic(errors.Errorf(
+			"GenesisNodeKey, %v is not match with local node key, %v",
+			basefact.GenesisNodeKey().String(),
+			design.Privatekey.Publickey().String(),
+		))
+	}
+
+	fact := currency.NewRegisterGenesisCurrencyFact(token, basefact.GenesisNodeKey(), acks, basefact.Currencies())
+	if err := fact.IsValid(g.networkID); err != nil {
+		return nil, e.Wrap(err)
+	}
+	op := currency.NewRegisterGenesisCurrency(fact)
+	if err := op.Sign(g.local.Privatekey(), g.networkID); err != nil {
+		return nil, e.Wrap(err)
+	}
+	g.Log().Debug().Interface("operation", op).Msg("genesis join operation created")
+
+	return op, nil
+}
+
+func (g *GenesisBlockGenerator) registerGenesisTokenOperation(
+	i base.Fact, contracts, symbols map[string]struct{},
+) (base.Operation, error) {
+	e := util.StringError("make registerGenesisToken operation")
+
+	basefact, ok := i.(token.RegisterGenesisTokenFact)
+	if !ok {
+		return nil, e.Wrap(errors.Errorf("expected RegisterGenesisTokenFact, not %T", i))
+	}
+
+	if _, found := contracts[basefact.Contract().String()]; found {
+		return nil, e.Wrap(errors.Errorf("multiple RegisterGenesisToken operation found for %v", basefact.Contract()))
+	}
+
+	params := DefaultTokenParams()
+	if err := util.LoadFromContext(g.ctx, TokenParamsContextKey, &params); err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	// NOTE the symbol registry states of genesis block are not seen by the
+	// other genesis operations, so the duplicated symbols are rejected here
+	// when the registry is kept from genesis.
+	if _, found := symbols[basefact.Symbol().String()]; found && params.SymbolRegistryHeight == base.GenesisHeight {
+		return nil, e.Wrap(errors.Errorf("multiple RegisterGenesisToken operation found for symbol %v", basefact.Symbol()))
+	}
+
+	contracts[basefact.Contract().String()] = struct{}{}
+	symbols[basefact.Symbol().String()] = struct{}{}
+
+	if err := g.checkGenesisNodeKey(basefact.GenesisNodeKey()); err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	// NOTE the token of fact is the network id with contract, so the facts of
+	// different contracts have different hashes.
+	fact := token.NewRegisterGenesisTokenFact(
+		util.ConcatBytesSlice(g.networkID, basefact.Contract().Bytes()),
+		basefact.GenesisNodeKey(),
+		basefact.Owner(),
+		basefact.Contract(),
+		basefact.Symbol(),
+		basefact.Name(),
+		basefact.Decimal(),
+		basefact.Balances(),
+		basefact.ApproveList(),
+	)
+	if err := fact.IsValid(g.networkID); err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	op := token.NewRegisterGenesisToken(fact)
+	if err := op.Sign(g.local.Privatekey(), g.networkID); err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	accounts, err := g.genesisAccountKeys()
+	if err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	g.Log().Debug().Interface("operation", op).Msg("genesis token operation created")
+
+	return genesisTokenOperation{
+		RegisterGenesisToken: op,
+		accounts:             accounts,
+		params:               params,
+	}, nil
+}
+
+// genesisAccountKeys returns the account state keys of the genesis accounts
+// created by RegisterGenesisCurrency.
+func (g *GenesisBlockGenerator) genesisAccountKeys() (map[string]struct{}, error) {
+	keys := map[string]struct{}{}
+
+	for i := range g.facts {
+		fact, ok := g.facts[i].(currency.RegisterGenesisCurrencyFact)
+		if !ok {
+			continue
+		}
+
+		// NOTE the keys of design fact are rebuilt like
+		// registerGenesisCurrencyOperation.
+		ks, err := currencytypes.NewBaseAccountKeys(fact.Keys().Keys(), fact.Keys().Threshold())
+		if err != nil {
+			return nil, err
+		}
+
+		a, err := currencytypes.NewAddressFromKeys(ks)
+		if err != nil {
+			return nil, err
+		}
+
+		keys[ccstate.AccountStateKey(a)] = struct{}{}
+	}
+
+	return keys, nil
+}
+
+func (g *GenesisBlockGenerator) checkGenesisNodeKey(pub base.Publickey) error {
+	if !pub.Equal(g.local.Publickey()) {
+		return errors.Errorf("genesis node key, %v not matched with local node key, %v", pub, g.local.Publickey())
+	}
+
+	return nil
+}
+
+func (g *GenesisBlockGenerator) newProposal(ops [][2]util.Hash) error {
+	e := util.StringError("make genesis proposal")
+
+	nops := make([][2]util.Hash, len(ops)+len(g.ops))
+	copy(nops[:len(ops)], ops)
+
+	for i := range g.ops {
+		nops[i+len(ops)][0] = g.ops[i].Hash()
+		nops[i+len(ops)][1] = g.ops[i].Fact().Hash()
+	}
+
+	fact := isaac.NewProposalFact(base.GenesisPoint, g.local.Address(), nil, nops)
+	sign := isaac.NewProposalSignFact(fact)
+
+	if err := sign.Sign(g.local.Privatekey(), g.networkID); err != nil {
+		return e.Wrap(err)
+	}
+
+	if err := sign.IsValid(g.networkID); err != nil {
+		return e.Wrap(err)
+	}
+
+	g.proposal = sign
+
+	g.Log().Debug().Interface("proposal", sign).Msg("proposal created for genesis")
+
+	return nil
+}
+
+func (g *GenesisBlockGenerator) initVoetproof() error {
+	e := util.StringError("make genesis init voteproof")
+
+	fact := isaac.NewINITBallotFact(base.GenesisPoint, nil, g.proposal.Fact().Hash(), nil)
+	if err := fact.IsValid(nil); err != nil {
+		return e.Wrap(err)
+	}
+
+	sf := isaac.NewINITBallotSignFact(fact)
+	if err := sf.NodeSign(g.local.Privatekey(), g.networkID, g.local.Address()); err != nil {
+		return e.Wrap(err)
+	}
+
+	if err := sf.IsValid(g.networkID); err != nil {
+		return e.Wrap(err)
+	}
+
+	vp := isaac.NewINITVoteproof(fact.Point().Point)
+	vp.
+		SetMajority(fact).
+		SetSignFacts([]base.BallotSignFact{sf}).
+		SetThreshold(base.MaxThreshold).
+		Finish()
+
+	if err := vp.IsValid(g.networkID); err != nil {
+		return e.Wrap(err)
+	}
+
+	g.ivp = vp
+
+	g.Log().Debug().Interface("init_voteproof", vp).Msg("init voteproof created for genesis")
+
+	return nil
+}
+
+func (g *GenesisBlockGenerator) acceptVoteproof(proposal, newblock util.Hash) error {
+	e := util.StringError("make genesis accept voteproof")
+
+	fact := isaac.NewACCEPTBallotFact(base.GenesisPoint, proposal, newblock, nil)
+	if err := fact.IsValid(nil); err != nil {
+		return e.Wrap(err)
+	}
+
+	sf := isaac.NewACCEPTBallotSignFact(fact)
+	if err := sf.NodeSign(g.local.Privatekey(), g.networkID, g.local.Address()); err != nil {
+		return e.Wrap(err)
+	}
+
+	if err := sf.IsValid(g.networkID); err != nil {
+		return e.Wrap(err)
+	}
+
+	vp := isaac.NewACCEPTVoteproof(fact.Point().Point)
+	vp.
+		SetMajority(fact).
+		SetSignFacts([]base.BallotSignFact{sf}).
+		SetThreshold(base.MaxThreshold).
+		Finish()
+
+	if err := vp.IsValid(g.networkID); err != nil {
+		return e.Wrap(err)
+	}
+
+	g.avp = vp
+
+	g.Log().Debug().Interface("init_voteproof", vp).Msg("accept voteproof created for genesis")
+
+	return nil
+}
+
+func (g *GenesisBlockGenerator) process() error {
+	e := util.StringError("process blockgenerator")
+
+	if err := g.initVoetproof(); err != nil {
+		return e.Wrap(err)
+	}
+
+	pp, err := g.newProposalProcessor()
+	if err != nil {
+		return e.Wrap(err)
+	}
+
+	_ = pp.SetLogging(g.Logging)
+
+	switch m, err := pp.Process(context.Background(), g.ivp); {
+	case err != nil:
+		return e.Wrap(err)
+	default:
+		if err := m.IsValid(g.networkID); err != nil {
+			return e.Wrap(err)
+		}
+
+		g.Log().Info().Interface("manifest", m).Msg("genesis block generated")
+
+		if err := g.acceptVoteproof(g.proposal.Fact().Hash(), m.Hash()); err != nil {
+			return e.Wrap(err)
+		}
+	}
+
+	if _, err := pp.Save(context.Background(), g.avp); err != nil {
+		return e.Wrap(err)
+	}
+
+	return nil
+}
+
+func (g *GenesisBlockGenerator) closeDatabase() error {
+	e := util.StringError("close database")
+
+	if err := g.db.MergeAllPermanent(); err != nil {
+		return e.WithMessage(err, "merge temps")
+	}
+
+	return nil
+}
+
+func (g *GenesisBlockGenerator) newProposalProcessor() (*isaac.DefaultProposalProcessor, error) {
+	args := isaac.NewDefaultProposalProcessorArgs()
+	args.NewWriterFunc = launch.NewBlockWriterFunc(
+		g.local, g.networkID, g.dataroot, g.encs.JSON(), g.encs.Default(), g.db, math.MaxInt16, 0)
+	args.GetStateFunc = func(key string) (base.State, bool, error) {
+		return nil, false, nil
+	}
+	args.GetOperationFunc = func(_ context.Context, operationhash, _ util.Hash) (base.Operation, error) {
+		for i := range g.ops {
+			op := g.ops[i]
+			if operationhash.Equal(op.Hash()) {
+				return op, nil
+			}
+		}
+
+		return nil, util.ErrNotFound.Errorf("Operation not found")
+	}
+
+	return isaac.NewDefaultProposalProcessor(g.proposal, nil, args)
+}
+
+// genesisTokenOperation is RegisterGenesisToken which sees the genesis accounts
+// of RegisterGenesisCurrency as existing. The states of the other operations
+// are not seen in genesis block, so without them, the owner or the balance
+// holder of genesis account would be created again without keys. The heights
+// of TokenParams are given to RegisterGenesisToken by the context.
+type genesisTokenOperation struct {
+	token.RegisterGenesisToken
+	accounts map[string]struct{}
+	params   TokenParams
+}
+
+func (op genesisTokenOperation) Process(
+	ctx context.Context, getStateFunc base.GetStateFunc,
+) ([]base.StateMergeValue, base.OperationProcessReasonError, error) {
+	nctx := util.ContextWithValues(ctx, map[util.ContextKey]interface{}{
+		token.SymbolRegistryHeightContextKey:       op.params.SymbolRegistryHeight,
+		token.CanonicalApproveListHeightContextKey: op.params.CanonicalApproveListHeight,
+	})
+
+	return op.RegisterGenesisToken.Process(nctx, func(key string) (base.State, bool, error) {
+		if _, found := op.accounts[key]; found {
+			return nil, true, nil
+		}
+
+		return getStateFunc(key)
+	})
+}
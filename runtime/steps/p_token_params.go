@@ -0,0 +1,130 @@
+package steps
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/launch"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/logging"
+	"github.com/imfact-labs/mitum2/util/ps"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	PNameLoadTokenParams  = ps.Name("mitum-token-load-params")
+	TokenParamsContextKey = util.ContextKey("token-params")
+)
+
+// TokenParams is the network parameters of token model, which is loaded from
+// `parameters.token` of node design; like the other network parameters, every
+// node of network should have the same TokenParams.
+type TokenParams struct {
+	// SymbolRegistryHeight is the height from which the token symbol registry
+	// is kept. base.NilHeight does not keep the registry.
+	SymbolRegistryHeight base.Height
+	// CanonicalApproveListHeight is the height from which the policy of
+	// design is written canonical; the legacy policy is migrated when it is
+	// changed from the height. base.NilHeight keeps writing the legacy policy.
+	CanonicalApproveListHeight base.Height
+}
+
+func DefaultTokenParams() TokenParams {
+	return TokenParams{
+		SymbolRegistryHeight:       base.NilHeight,
+		CanonicalApproveListHeight: base.NilHeight,
+	}
+}
+
+func (p TokenParams) IsValid([]byte) error {
+	if p.SymbolRegistryHeight < base.NilHeight {
+		return util.ErrInvalid.Errorf("wrong symbol registry height, %v", p.SymbolRegistryHeight)
+	}
+
+	if p.CanonicalApproveListHeight < base.NilHeight {
+		return util.ErrInvalid.Errorf("wrong canonical approve list height, %v", p.CanonicalApproveListHeight)
+	}
+
+	return nil
+}
+
+type tokenParamsYAML struct {
+	SymbolRegistryHeight       *base.Height `yaml:"symbol_registry_height,omitempty"`
+	CanonicalApproveListHeight *base.Height `yaml:"canonical_approve_list_height,omitempty"`
+}
+
+// LoadTokenParams loads TokenParams from the node design file; without
+// `parameters.token`, DefaultTokenParams is returned.
+func LoadTokenParams(f string) (TokenParams, error) {
+	e := util.StringError("load token params")
+
+	params := DefaultTokenParams()
+
+	b, err := os.ReadFile(filepath.Clean(f))
+	if err != nil {
+		return params, e.Wrap(err)
+	}
+
+	nb, err := util.ReplaceEnvVariables(b)
+	if err != nil {
+		return params, e.Wrap(err)
+	}
+
+	var m struct {
+		Parameters struct {
+			Token *tokenParamsYAML `yaml:"token"`
+		} `yaml:"parameters"`
+	}
+
+	if err := yaml.Unmarshal(nb, &m); err != nil {
+		return params, e.Wrap(err)
+	}
+
+	if u := m.Parameters.Token; u != nil {
+		if u.SymbolRegistryHeight != nil {
+			params.SymbolRegistryHeight = *u.SymbolRegistryHeight
+		}
+
+		if u.CanonicalApproveListHeight != nil {
+			params.CanonicalApproveListHeight = *u.CanonicalApproveListHeight
+		}
+	}
+
+	if err := params.IsValid(nil); err != nil {
+		return params, e.Wrap(err)
+	}
+
+	return params, nil
+}
+
+// PLoadTokenParams loads TokenParams from the node design file, like the
+// digest design of currency model.
+func PLoadTokenParams(pctx context.Context) (context.Context, error) {
+	e := util.StringError("load token params")
+
+	var log *logging.Logging
+	var flag launch.DesignFlag
+
+	if err := util.LoadFromContextOK(pctx,
+		launch.LoggingContextKey, &log,
+		launch.DesignFlagContextKey, &flag,
+	); err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	if flag.Scheme() != "file" {
+		return pctx, e.Wrap(errors.Errorf("unknown token params design uri, %q", flag.URL()))
+	}
+
+	params, err := LoadTokenParams(flag.URL().Path)
+	if err != nil {
+		return pctx, err
+	}
+
+	log.Log().Debug().Interface("params", params).Msg("token params loaded")
+
+	return context.WithValue(pctx, TokenParamsContextKey, params), nil
+}
@@ -0,0 +1,78 @@
+package steps
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/imfact-labs/mitum2/base"
+)
+
+func TestLoadTokenParams(t *testing.T) {
+	cases := []struct {
+		name     string
+		design   string
+		expected TokenParams
+		err      bool
+	}{
+		{
+			name: "without token params",
+			design: `parameters:
+  isaac:
+    threshold: "100"
+`,
+			expected: DefaultTokenParams(),
+		},
+		{
+			name: "token params",
+			design: `parameters:
+  token:
+    symbol_registry_height: 10
+    canonical_approve_list_height: 20
+`,
+			expected: TokenParams{SymbolRegistryHeight: base.Height(10), CanonicalApproveListHeight: base.Height(20)},
+		},
+		{
+			name: "symbol registry height only",
+			design: `parameters:
+  token:
+    symbol_registry_height: 0
+`,
+			expected: TokenParams{SymbolRegistryHeight: base.GenesisHeight, CanonicalApproveListHeight: base.NilHeight},
+		},
+		{
+			name: "wrong height",
+			design: `parameters:
+  token:
+    canonical_approve_list_height: -2
+`,
+			err: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			f := filepath.Join(t.TempDir(), "design.yml")
+			if err := os.WriteFile(f, []byte(c.design), 0o600); err != nil {
+				t.Fatalf("write design: %v", err)
+			}
+
+			params, err := LoadTokenParams(f)
+
+			switch {
+			case c.err:
+				if err == nil {
+					t.Fatal("expected error, but nil")
+				}
+
+				return
+			case err != nil:
+				t.Fatalf("load token params: %v", err)
+			}
+
+			if params != c.expected {
+				t.Fatalf("token params not matched; expected=%+v params=%+v", c.expected, params)
+			}
+		})
+	}
+}
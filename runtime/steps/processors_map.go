@@ -37,6 +37,11 @@ func POperationProcessorsMap(pctx context.Context) (context.Context, error) {
 		return pctx, err
 	}
 
+	params := DefaultTokenParams()
+	if err := util.LoadFromContext(pctx, TokenParamsContextKey, &params); err != nil {
+		return pctx, err
+	}
+
 	//err := opr.SetCheckDuplicationFunc(processor.CheckDuplication)
 	//if err != nil {
 	//	return pctx, err
@@ -46,14 +51,7 @@ func POperationProcessorsMap(pctx context.Context) (context.Context, error) {
 		return pctx, err
 	}
 
-	processors := []processorInfo{
-		{token.RegisterModelHint, token.NewRegisterModelProcessor()},
-		{token.MintHint, token.NewMintProcessor()},
-		{token.BurnHint, token.NewBurnProcessor()},
-		{token.ApproveHint, token.NewApproveProcessor()},
-		{token.TransferHint, token.NewTransferProcessor()},
-		{token.TransferFromHint, token.NewTransferFromProcessor()},
-	}
+	processors := operationProcessors(params)
 
 	for i := range processors {
 		p := processors[i]
@@ -62,16 +60,7 @@ func POperationProcessorsMap(pctx context.Context) (context.Context, error) {
 			return pctx, err
 		}
 
-		if err := set.Add(p.hint,
-			func(height base.Height, getStatef base.GetStateFunc) (base.OperationProcessor, error) {
-				return opr.New(
-					height,
-					getStatef,
-					nil,
-					nil,
-				)
-			},
-		); err != nil {
+		if err := set.Add(p.hint, newOperationProcessorFunc(opr)); err != nil {
 			return pctx, err
 		}
 	}
@@ -81,3 +70,61 @@ func POperationProcessorsMap(pctx context.Context) (context.Context, error) {
 
 	return pctx, nil
 }
+
+func operationProcessors(params TokenParams) []processorInfo {
+	return []processorInfo{
+		{token.RegisterModelHint, token.NewRegisterModelProcessor(
+			params.SymbolRegistryHeight, params.CanonicalApproveListHeight)},
+		{token.MintHint, token.NewMintProcessor(params.CanonicalApproveListHeight)},
+		{token.BurnHint, token.NewBurnProcessor(params.CanonicalApproveListHeight)},
+		{token.ApproveHint, token.NewApproveProcessor(params.CanonicalApproveListHeight)},
+		{token.TransferHint, token.NewTransferProcessor()},
+		{token.TransferFromHint, token.NewTransferFromProcessor(params.CanonicalApproveListHeight)},
+		{token.ReleaseSymbolHint, token.NewReleaseSymbolProcessor(params.SymbolRegistryHeight)},
+		{token.BackfillSymbolHint, token.NewBackfillSymbolProcessor(params.SymbolRegistryHeight)},
+	}
+}
+
+// newOperationProcessorFunc wraps the OperationProcessor of opr with the
+// reservation and the supply guard.
+func newOperationProcessorFunc(opr *cprocessor.OperationProcessor) isaac.NewOperationProcessorInternalFunc {
+	return func(height base.Height, getStatef base.GetStateFunc) (base.OperationProcessor, error) {
+		opp, err := opr.New(
+			height,
+			getStatef,
+			nil,
+			nil,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		return newReservationOperationProcessor(newSupplyGuardOperationProcessor(opp)), nil
+	}
+}
+
+// NewOperationProcessorFunc returns the function to create the
+// OperationProcessor of POperationProcessorsMap, to process the token
+// operations out of the node, like simulation; the params should be same with
+// the ones of node.
+func NewOperationProcessorFunc(params TokenParams) (isaac.NewOperationProcessorInternalFunc, error) {
+	opr := cprocessor.NewOperationProcessor()
+
+	if err := opr.SetCheckDuplicationFunc(cprocessor.CheckDuplication); err != nil {
+		return nil, err
+	}
+
+	if err := opr.SetGetNewProcessorFunc(cprocessor.GetNewProcessor); err != nil {
+		return nil, err
+	}
+
+	processors := operationProcessors(params)
+
+	for i := range processors {
+		if err := opr.SetProcessor(processors[i].hint, processors[i].processor); err != nil {
+			return nil, err
+		}
+	}
+
+	return newOperationProcessorFunc(opr), nil
+}
@@ -0,0 +1,46 @@
+package steps
+
+import (
+	"context"
+
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/token-model/state"
+)
+
+// reservationOperationProcessor sets the new TokenBalanceReservation and
+// SymbolReservation to the context of PreProcess; the context is passed to the
+// next operation of proposal, so the reservations are created once in
+// proposal.
+type reservationOperationProcessor struct {
+	base.OperationProcessor
+}
+
+func newReservationOperationProcessor(opp base.OperationProcessor) base.OperationProcessor {
+	if opp == nil {
+		return nil
+	}
+
+	return reservationOperationProcessor{OperationProcessor: opp}
+}
+
+func (opp reservationOperationProcessor) PreProcess(
+	ctx context.Context, op base.Operation, getStateFunc base.GetStateFunc,
+) (context.Context, base.OperationProcessReasonError, error) {
+	if ctx.Value(state.TokenBalanceReservationContextKey) == nil {
+		ctx = context.WithValue(ctx, state.TokenBalanceReservationContextKey, state.NewTokenBalanceReservation())
+	}
+
+	if ctx.Value(state.SymbolReservationContextKey) == nil {
+		ctx = context.WithValue(ctx, state.SymbolReservationContextKey, state.NewSymbolReservation())
+	}
+
+	return opp.OperationProcessor.PreProcess(ctx, op, getStateFunc)
+}
+
+func (opp reservationOperationProcessor) OperationReceipt() base.OperationReceipt {
+	if i, ok := opp.OperationProcessor.(base.OperationReceiptProvider); ok {
+		return i.OperationReceipt()
+	}
+
+	return nil
+}
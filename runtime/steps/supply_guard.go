@@ -0,0 +1,379 @@
+package steps
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/hint"
+	"github.com/imfact-labs/token-model/state"
+	"github.com/pkg/errors"
+)
+
+// supplyGuardOperationProcessor checks the token supply conservation of the
+// processed operation; in each contract, the sum of the token balance changes
+// should be same with the total supply change of design against the last
+// block. The conservation of block is checked by the supply check value of
+// contract with the closed values of the block. When not conserved, the
+// proposal is not processed, so the block is not voted.
+type supplyGuardOperationProcessor struct {
+	base.OperationProcessor
+}
+
+func newSupplyGuardOperationProcessor(opp base.OperationProcessor) base.OperationProcessor {
+	if opp == nil {
+		return nil
+	}
+
+	return supplyGuardOperationProcessor{OperationProcessor: opp}
+}
+
+func (opp supplyGuardOperationProcessor) Process(
+	ctx context.Context, op base.Operation, getStateFunc base.GetStateFunc,
+) ([]base.StateMergeValue, base.OperationProcessReasonError, error) {
+	stvs, reason, err := opp.OperationProcessor.Process(ctx, op, getStateFunc)
+	if err != nil || reason != nil {
+		return stvs, reason, err
+	}
+
+	if err := checkSupply(stvs, getStateFunc); err != nil {
+		return nil, nil, util.StringError("token supply not conserved").WithMessage(err, "fact=%v", op.Fact().Hash())
+	}
+
+	checks, err := newSupplyCheckStateMergeValues(stvs, getStateFunc)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return append(stvs, checks...), nil, nil
+}
+
+func (opp supplyGuardOperationProcessor) OperationReceipt() base.OperationReceipt {
+	if i, ok := opp.OperationProcessor.(base.OperationReceiptProvider); ok {
+		return i.OperationReceipt()
+	}
+
+	return nil
+}
+
+// checkSupply compares the balance changes of values with the total supply
+// changes of designs by contract. The other values do not change the supply,
+// so they are left to their mergers.
+func checkSupply(values []base.StateMergeValue, getStateFunc base.GetStateFunc) error {
+	balances := map[string]common.Big{}
+	supplies := map[string]common.Big{}
+
+	add := func(m map[string]common.Big, contract string, amount common.Big) {
+		if d, found := m[contract]; found {
+			m[contract] = d.Add(amount)
+
+			return
+		}
+
+		m[contract] = amount
+	}
+
+	for i := range values {
+		key := values[i].Key()
+
+		if !state.IsStateDesignKey(key) && !state.IsStateTokenBalanceKey(key) {
+			continue
+		}
+
+		contract, _, err := state.ParseTokenStateKey(key)
+		if err != nil {
+			return err
+		}
+
+		switch t := values[i].Value().(type) {
+		case state.DesignStateValue:
+			prev, err := lastTotalSupply(contract, getStateFunc)
+			if err != nil {
+				return err
+			}
+
+			add(supplies, contract, t.Design().Policy().TotalSupply().Sub(prev))
+		case state.AddTokenBalanceStateValue:
+			add(balances, contract, t.Amount)
+		case state.DeductTokenBalanceStateValue:
+			add(balances, contract, t.Amount.Neg())
+		}
+	}
+
+	contracts := make([]string, 0, len(balances)+len(supplies))
+
+	for contract := range balances {
+		contracts = append(contracts, contract)
+	}
+
+	for contract := range supplies {
+		if _, found := balances[contract]; !found {
+			contracts = append(contracts, contract)
+		}
+	}
+
+	sort.Strings(contracts)
+
+	for i := range contracts {
+		balance, supply := common.ZeroBig, common.ZeroBig
+
+		if d, found := balances[contracts[i]]; found {
+			balance = d
+		}
+
+		if d, found := supplies[contracts[i]]; found {
+			supply = d
+		}
+
+		if !balance.Equal(supply) {
+			return errors.Errorf("contract=%s; change of balances, %v not matched with change of total supply, %v",
+				contracts[i], balance, supply)
+		}
+	}
+
+	return nil
+}
+
+func lastTotalSupply(contract string, getStateFunc base.GetStateFunc) (common.Big, error) {
+	switch st, found, err := getStateFunc(state.StateKeyDesign(contract)); {
+	case err != nil:
+		return common.ZeroBig, err
+	case !found:
+		return common.ZeroBig, nil
+	default:
+		design, err := state.StateDesignValue(st)
+		if err != nil {
+			return common.ZeroBig, err
+		}
+
+		return design.Policy().TotalSupply(), nil
+	}
+}
+
+var supplyCheckStateValueHint = hint.MustNewHint("mitum-token-supply-check-state-value-v0.0.1")
+
+// supplyCheckStateKey is the key of supply check value of contract; the state
+// of the key is not stored.
+func supplyCheckStateKey(contract string) string {
+	return fmt.Sprintf("%s:supply-check", state.StateKeyTokenPrefix(contract))
+}
+
+// supplyCheckStateValue carries the design and token balance values of
+// contract with the states of the last block.
+type supplyCheckStateValue struct {
+	hint.BaseHinter
+	values []base.StateMergeValue
+	states map[string]base.State
+}
+
+func (v supplyCheckStateValue) IsValid([]byte) error {
+	return v.BaseHinter.IsValid(supplyCheckStateValueHint.Type().Bytes())
+}
+
+func (supplyCheckStateValue) HashBytes() []byte {
+	return nil
+}
+
+// newSupplyCheckStateMergeValues returns the supply check values of the
+// contracts of values.
+func newSupplyCheckStateMergeValues(
+	values []base.StateMergeValue, getStateFunc base.GetStateFunc,
+) ([]base.StateMergeValue, error) {
+	var contracts []string
+	m := map[string]*supplyCheckStateValue{}
+
+	for i := range values {
+		key := values[i].Key()
+
+		if !state.IsStateDesignKey(key) && !state.IsStateTokenBalanceKey(key) {
+			continue
+		}
+
+		contract, _, err := state.ParseTokenStateKey(key)
+		if err != nil {
+			return nil, err
+		}
+
+		v, found := m[contract]
+		if !found {
+			v = &supplyCheckStateValue{
+				BaseHinter: hint.NewBaseHinter(supplyCheckStateValueHint),
+				states:     map[string]base.State{},
+			}
+			m[contract] = v
+
+			contracts = append(contracts, contract)
+		}
+
+		if _, found := v.states[key]; !found {
+			switch st, found, err := getStateFunc(key); {
+			case err != nil:
+				return nil, err
+			case found:
+				v.states[key] = st
+			default:
+				v.states[key] = nil
+			}
+		}
+
+		v.values = append(v.values, values[i])
+	}
+
+	smvs := make([]base.StateMergeValue, len(contracts))
+
+	for i := range contracts {
+		key := supplyCheckStateKey(contracts[i])
+
+		smvs[i] = common.NewBaseStateMergeValue(
+			key,
+			*m[contracts[i]],
+			func(height base.Height, st base.State) base.StateValueMerger {
+				return newSupplyCheckStateValueMerger(height, key, st)
+			},
+		)
+	}
+
+	return smvs, nil
+}
+
+// supplyCheckStateValueMerger merges the design and token balance values of
+// contract in block like the states merger of block, and checks the sum of
+// the closed token balance changes is same with the closed total supply
+// change. The state is not stored by base.ErrIgnoreStateValue.
+type supplyCheckStateValueMerger struct {
+	*common.BaseStateValueMerger
+	height base.Height
+	keys   []string
+	values map[string][]supplyCheckValue
+	states map[string]base.State
+	sync.Mutex
+}
+
+type supplyCheckValue struct {
+	value base.StateMergeValue
+	fact  util.Hash
+}
+
+func newSupplyCheckStateValueMerger(height base.Height, key string, st base.State) *supplyCheckStateValueMerger {
+	return &supplyCheckStateValueMerger{
+		BaseStateValueMerger: common.NewBaseStateValueMerger(height, key, st),
+		height:               height,
+		values:               map[string][]supplyCheckValue{},
+		states:               map[string]base.State{},
+	}
+}
+
+func (s *supplyCheckStateValueMerger) Merge(value base.StateValue, ops util.Hash) error {
+	s.Lock()
+	defer s.Unlock()
+
+	t, ok := value.(supplyCheckStateValue)
+	if !ok {
+		return errors.Errorf("unsupported supply check state value, %T", value)
+	}
+
+	for i := range t.values {
+		key := t.values[i].Key()
+
+		if _, found := s.values[key]; !found {
+			s.keys = append(s.keys, key)
+			s.states[key] = t.states[key]
+		}
+
+		s.values[key] = append(s.values[key], supplyCheckValue{value: t.values[i], fact: ops})
+	}
+
+	return nil
+}
+
+func (s *supplyCheckStateValueMerger) CloseValue() (base.State, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	if err := s.check(); err != nil {
+		return nil, util.StringError("token supply of block not conserved").WithMessage(err, "key=%v", s.Key())
+	}
+
+	return nil, base.ErrIgnoreStateValue.Errorf("token supply checked")
+}
+
+// check closes the values by key and compares the balance changes with the
+// total supply change against the last block.
+func (s *supplyCheckStateValueMerger) check() error {
+	balance, supply := common.ZeroBig, common.ZeroBig
+
+	sort.Strings(s.keys)
+
+	for _, key := range s.keys {
+		before := s.states[key]
+
+		var merger base.StateValueMerger
+
+		for _, v := range s.values[key] {
+			if merger == nil {
+				merger = v.value.Merger(s.height, before)
+			}
+
+			if err := merger.Merge(v.value.Value(), v.fact); err != nil {
+				return err
+			}
+		}
+
+		after, err := merger.CloseValue()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case state.IsStateDesignKey(key):
+			d, err := supplyCheckDiff(before, after, func(st base.State) (common.Big, error) {
+				design, err := state.StateDesignValue(st)
+				if err != nil {
+					return common.ZeroBig, err
+				}
+
+				return design.Policy().TotalSupply(), nil
+			})
+			if err != nil {
+				return err
+			}
+
+			supply = d
+		default:
+			d, err := supplyCheckDiff(before, after, state.StateTokenBalanceValue)
+			if err != nil {
+				return err
+			}
+
+			balance = balance.Add(d)
+		}
+	}
+
+	if !balance.Equal(supply) {
+		return errors.Errorf("change of balances, %v not matched with change of total supply, %v", balance, supply)
+	}
+
+	return nil
+}
+
+func supplyCheckDiff(before, after base.State, f func(base.State) (common.Big, error)) (common.Big, error) {
+	a, err := f(after)
+	if err != nil {
+		return common.ZeroBig, err
+	}
+
+	if before == nil {
+		return a, nil
+	}
+
+	b, err := f(before)
+	if err != nil {
+		return common.ZeroBig, err
+	}
+
+	return a.Sub(b), nil
+}
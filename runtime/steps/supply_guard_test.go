@@ -0,0 +1,274 @@
+package steps
+
+import (
+	"context"
+	"testing"
+
+	"github.com/imfact-labs/currency-model/common"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/token-model/operation/token"
+	"github.com/imfact-labs/token-model/state"
+	"github.com/imfact-labs/token-model/types"
+	"github.com/pkg/errors"
+)
+
+const testContract = "contract"
+
+// testValuesOperationProcessor returns the values as the processed values of
+// any operation.
+type testValuesOperationProcessor struct {
+	base.OperationProcessor
+	values []base.StateMergeValue
+}
+
+func (opp testValuesOperationProcessor) Process(
+	context.Context, base.Operation, base.GetStateFunc,
+) ([]base.StateMergeValue, base.OperationProcessReasonError, error) {
+	return opp.values, nil, nil
+}
+
+// testLastDesignStateValueMerger keeps the last merged design, so the total
+// supply changes of the other designs are lost.
+type testLastDesignStateValueMerger struct {
+	*common.BaseStateValueMerger
+	value base.StateValue
+}
+
+func (s *testLastDesignStateValueMerger) Merge(value base.StateValue, ops util.Hash) error {
+	s.value = value
+	s.AddOperation(ops)
+
+	return nil
+}
+
+func (s *testLastDesignStateValueMerger) CloseValue() (base.State, error) {
+	s.BaseStateValueMerger.SetValue(s.value)
+
+	return s.BaseStateValueMerger.CloseValue()
+}
+
+func newTestGetStateFunc(sts ...base.State) base.GetStateFunc {
+	m := map[string]base.State{}
+	for i := range sts {
+		m[sts[i].Key()] = sts[i]
+	}
+
+	return func(key string) (base.State, bool, error) {
+		st, found := m[key]
+
+		return st, found, nil
+	}
+}
+
+func newTestDesignState(supply int64) base.State {
+	return common.NewBaseState(base.Height(1), state.StateKeyDesign(testContract),
+		state.NewDesignStateValue(newTestDesign(supply)), nil, nil)
+}
+
+func newTestDesign(supply int64) types.Design {
+	return types.NewDesign("MCT", "token", common.NewBig(0), types.NewPolicy(common.NewBig(supply), nil))
+}
+
+func newTestMintValues(supply, amount int64, receiver string) []base.StateMergeValue {
+	return []base.StateMergeValue{
+		state.NewDesignStateMergeValue(state.StateKeyDesign(testContract), newTestDesign(supply)),
+		newTestTokenBalanceStateMergeValue(receiver, amount),
+	}
+}
+
+func newTestTokenBalanceStateMergeValue(holder string, amount int64) base.StateMergeValue {
+	key := state.StateKeyTokenBalance(testContract, holder)
+
+	var value base.StateValue = state.NewAddTokenBalanceStateValue(common.NewBig(amount))
+	if amount < 0 {
+		value = state.NewDeductTokenBalanceStateValue(common.NewBig(-amount))
+	}
+
+	return common.NewBaseStateMergeValue(
+		key,
+		value,
+		func(height base.Height, st base.State) base.StateValueMerger {
+			return state.NewTokenBalanceStateValueMerger(height, key, st)
+		},
+	)
+}
+
+func newTestOperation() base.Operation {
+	return token.NewMint(token.NewMintFact([]byte("token"), ctypes.NewStringAddress("sender"),
+		ctypes.NewStringAddress(testContract), ctypes.CurrencyID("MCC"), ctypes.NewStringAddress("receiver"),
+		common.NewBig(1)))
+}
+
+// mergeTestSupplyChecks merges the values of supply check like the states
+// merger of block and returns the error of closed supply check.
+func mergeTestSupplyChecks(t *testing.T, getStateFunc base.GetStateFunc, values ...[]base.StateMergeValue) error {
+	t.Helper()
+
+	var merger base.StateValueMerger
+
+	for i := range values {
+		for j := range values[i] {
+			smv := values[i][j]
+			if smv.Key() != supplyCheckStateKey(testContract) {
+				continue
+			}
+
+			if merger == nil {
+				st, _, _ := getStateFunc(smv.Key())
+				merger = smv.Merger(base.Height(2), st)
+			}
+
+			if err := merger.Merge(smv.Value(), newTestOperation().Fact().Hash()); err != nil {
+				t.Fatalf("merge supply check: %v", err)
+			}
+		}
+	}
+
+	if merger == nil {
+		t.Fatal("no supply check value")
+	}
+
+	switch st, err := merger.CloseValue(); {
+	case st != nil:
+		t.Fatalf("supply check state not ignored, %v", st)
+	case errors.Is(err, base.ErrIgnoreStateValue):
+		return nil
+	default:
+		return err
+	}
+
+	return nil
+}
+
+func newTestTokenBalanceState(holder string, amount int64) base.State {
+	return common.NewBaseState(base.Height(1), state.StateKeyTokenBalance(testContract, holder),
+		state.NewTokenBalanceStateValue(common.NewBig(amount)), nil, nil)
+}
+
+func TestSupplyGuardOperationProcessor(t *testing.T) {
+	getStateFunc := newTestGetStateFunc(newTestDesignState(100), newTestTokenBalanceState("a", 100))
+
+	cases := []struct {
+		name   string
+		values []base.StateMergeValue
+		err    bool
+	}{
+		{name: "mint", values: newTestMintValues(120, 20, "a")},
+		{name: "burn", values: []base.StateMergeValue{
+			state.NewDesignStateMergeValue(state.StateKeyDesign(testContract), newTestDesign(90)),
+			newTestTokenBalanceStateMergeValue("a", -10),
+		}},
+		{name: "transfer", values: []base.StateMergeValue{
+			newTestTokenBalanceStateMergeValue("a", -10),
+			newTestTokenBalanceStateMergeValue("b", 10),
+		}},
+		{name: "mint not conserved", values: newTestMintValues(120, 10, "a"), err: true},
+		{name: "transfer not conserved", values: []base.StateMergeValue{
+			newTestTokenBalanceStateMergeValue("a", -10),
+			newTestTokenBalanceStateMergeValue("b", 11),
+		}, err: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			opp := newSupplyGuardOperationProcessor(testValuesOperationProcessor{values: c.values})
+
+			values, reason, err := opp.Process(context.Background(), newTestOperation(), getStateFunc)
+
+			switch {
+			case reason != nil:
+				t.Fatalf("unexpected reason: %v", reason)
+			case c.err:
+				if err == nil {
+					t.Fatal("expected error, but nil")
+				}
+
+				return
+			case err != nil:
+				t.Fatalf("process: %v", err)
+			}
+
+			// NOTE the supply check value of contract is added.
+			if len(values) != len(c.values)+1 {
+				t.Fatalf("values not matched; expected=%d values=%d", len(c.values)+1, len(values))
+			}
+
+			if err := mergeTestSupplyChecks(t, getStateFunc, values); err != nil {
+				t.Fatalf("supply check: %v", err)
+			}
+		})
+	}
+}
+
+func TestSupplyCheckStateValueMerger(t *testing.T) {
+	getStateFunc := newTestGetStateFunc(newTestDesignState(100), newTestTokenBalanceState("a", 100))
+
+	process := func(values []base.StateMergeValue) []base.StateMergeValue {
+		opp := newSupplyGuardOperationProcessor(testValuesOperationProcessor{values: values})
+
+		values, _, err := opp.Process(context.Background(), newTestOperation(), getStateFunc)
+		if err != nil {
+			t.Fatalf("process: %v", err)
+		}
+
+		return values
+	}
+
+	t.Run("conserved block", func(t *testing.T) {
+		if err := mergeTestSupplyChecks(t, getStateFunc,
+			process(newTestMintValues(120, 20, "a")),
+			process(newTestMintValues(130, 30, "b")),
+			process([]base.StateMergeValue{
+				newTestTokenBalanceStateMergeValue("a", -40),
+				newTestTokenBalanceStateMergeValue("b", 40),
+			}),
+		); err != nil {
+			t.Fatalf("supply check: %v", err)
+		}
+	})
+
+	t.Run("closed design not conserved", func(t *testing.T) {
+		// NOTE every operation is conserved, but the design merger loses the
+		// total supply change of the former.
+		last := func(supply, amount int64, receiver string) []base.StateMergeValue {
+			key := state.StateKeyDesign(testContract)
+
+			return []base.StateMergeValue{
+				common.NewBaseStateMergeValue(
+					key,
+					state.NewDesignStateValue(newTestDesign(supply)),
+					func(height base.Height, st base.State) base.StateValueMerger {
+						return &testLastDesignStateValueMerger{
+							BaseStateValueMerger: common.NewBaseStateValueMerger(height, key, st),
+						}
+					},
+				),
+				newTestTokenBalanceStateMergeValue(receiver, amount),
+			}
+		}
+
+		if err := mergeTestSupplyChecks(t, getStateFunc,
+			process(last(120, 20, "a")),
+			process(last(130, 30, "b")),
+		); err == nil {
+			t.Fatal("expected error, but nil")
+		}
+	})
+
+	t.Run("negative balance", func(t *testing.T) {
+		if err := mergeTestSupplyChecks(t, getStateFunc,
+			process([]base.StateMergeValue{
+				newTestTokenBalanceStateMergeValue("a", -80),
+				newTestTokenBalanceStateMergeValue("b", 80),
+			}),
+			process([]base.StateMergeValue{
+				newTestTokenBalanceStateMergeValue("a", -80),
+				newTestTokenBalanceStateMergeValue("c", 80),
+			}),
+		); err == nil {
+			t.Fatal("expected error, but nil")
+		}
+	})
+}
@@ -3,6 +3,8 @@ package state
 import (
 	"fmt"
 	"strings"
+
+	"github.com/pkg/errors"
 )
 
 var TokenPrefix = "token"
@@ -36,3 +38,18 @@ func IsStateDesignKey(key string) bool {
 func IsStateTokenBalanceKey(key string) bool {
 	return strings.HasPrefix(key, TokenPrefix) && strings.HasSuffix(key, TokenBalanceSuffix)
 }
+
+// ParseTokenStateKey returns the contract and the address of token state key;
+// address is empty for the design key.
+func ParseTokenStateKey(key string) (contract string, address string, _ error) {
+	l := strings.Split(key, ":")
+
+	switch {
+	case len(l) == 3 && l[0] == TokenPrefix && l[2] == DesignSuffix:
+		return l[1], "", nil
+	case len(l) == 4 && l[0] == TokenPrefix && l[3] == TokenBalanceSuffix:
+		return l[1], l[2], nil
+	default:
+		return "", "", errors.Errorf("invalid token state key, %q", key)
+	}
+}
@@ -46,6 +46,10 @@ func (s DesignStateValue) IsValid([]byte) error {
 	return nil
 }
 
+func (s DesignStateValue) Design() types.Design {
+	return s.design
+}
+
 func (s DesignStateValue) HashBytes() []byte {
 	return s.design.Bytes()
 }
@@ -1,11 +1,13 @@
 package state
 
 import (
+	"sort"
 	"sync"
 
 	"github.com/imfact-labs/currency-model/common"
 	"github.com/imfact-labs/mitum2/base"
 	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/token-model/types"
 	"github.com/pkg/errors"
 )
 
@@ -80,7 +82,281 @@ func (s *TokenBalanceStateValueMerger) closeValue() (base.StateValue, error) {
 		existingAmount = existingAmount.Sub(s.remove)
 	}
 
+	if !existingAmount.OverNil() {
+		return nil, errors.Errorf("negative token balance, %v of %v", existingAmount, s.Key())
+	}
+
 	return NewTokenBalanceStateValue(
 		existingAmount,
 	), nil
 }
+
+// DesignStateValueMerger merges the designs of a contract in a block. The
+// changes of the designs against the existing one are summed up, so the
+// merged design does not depend on the order of merge; the total supply
+// changes by mint and burn, and the allowance changes by (owner, spender) of
+// the approve list by approve and transfer-from. The allowance, which is
+// removed by approve and used by transfer-from in the same block, is removed
+// without becoming negative. The merged design keeps the policy form of the
+// merged designs, which the processors decide by the height; the legacy
+// policy keeps the order of the existing approve list.
+type DesignStateValueMerger struct {
+	*common.BaseStateValueMerger
+	existing  *types.Design
+	allowance map[string]common.Big
+	supply    common.Big
+	approves  map[string]approveDelta
+	design    *types.Design
+	sync.Mutex
+}
+
+// approveDelta is the change of allowance of spender approved by owner.
+type approveDelta struct {
+	owner   base.Address
+	spender base.Address
+	amount  common.Big
+}
+
+func NewDesignStateValueMerger(height base.Height, key string, st base.State) *DesignStateValueMerger {
+	nst := st
+	if st == nil {
+		nst = common.NewBaseState(base.NilHeight, key, nil, nil, nil)
+	}
+
+	s := &DesignStateValueMerger{
+		BaseStateValueMerger: common.NewBaseStateValueMerger(height, nst.Key(), nst),
+		allowance:            map[string]common.Big{},
+		supply:               common.ZeroBig,
+		approves:             map[string]approveDelta{},
+	}
+
+	if nst.Value() != nil {
+		design := nst.Value().(DesignStateValue).design //nolint:forcetypeassert //...
+		s.existing = &design
+
+		for _, box := range design.Policy().ApproveList() {
+			for _, info := range box.Approved() {
+				s.allowance[approveDeltaKey(box.Account(), info.Account())] = info.Amount()
+			}
+		}
+	}
+
+	return s
+}
+
+func NewDesignStateMergeValue(key string, design types.Design) base.StateMergeValue {
+	return common.NewBaseStateMergeValue(
+		key,
+		NewDesignStateValue(design),
+		func(height base.Height, st base.State) base.StateValueMerger {
+			return NewDesignStateValueMerger(height, key, st)
+		},
+	)
+}
+
+func (s *DesignStateValueMerger) Merge(value base.StateValue, ops util.Hash) error {
+	s.Lock()
+	defer s.Unlock()
+
+	t, ok := value.(DesignStateValue)
+	if !ok {
+		return errors.Errorf("unsupported design state value, %T", value)
+	}
+
+	s.supply = s.supply.Add(t.design.Policy().TotalSupply().Sub(s.existingSupply()))
+	s.mergeApproveList(t.design.Policy().ApproveList())
+
+	if s.design == nil {
+		s.design = &t.design
+	}
+
+	s.AddOperation(ops)
+
+	return nil
+}
+
+func (s *DesignStateValueMerger) CloseValue() (base.State, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	newValue, err := s.closeValue()
+	if err != nil {
+		return nil, errors.WithMessage(err, "close DesignStateValueMerger")
+	}
+
+	s.BaseStateValueMerger.SetValue(newValue)
+
+	return s.BaseStateValueMerger.CloseValue()
+}
+
+func (s *DesignStateValueMerger) closeValue() (base.StateValue, error) {
+	if s.design == nil {
+		return nil, errors.Errorf("empty design, %v", s.Key())
+	}
+
+	totalSupply := s.existingSupply().Add(s.supply)
+	if !totalSupply.OverNil() {
+		return nil, errors.Errorf("negative total supply, %v of %v", totalSupply, s.Key())
+	}
+
+	// NOTE the design is registered once, so without existing, the merged
+	// design is the only one.
+	design := s.existing
+	if design == nil {
+		design = s.design
+	}
+
+	legacy := s.design.Policy().IsLegacy()
+
+	if totalSupply.Equal(design.Policy().TotalSupply()) && len(s.approves) < 1 &&
+		legacy == design.Policy().IsLegacy() {
+		return NewDesignStateValue(*design), nil
+	}
+
+	newPolicy, newApproveBox := types.NewPolicy, types.NewApproveBox
+	if legacy {
+		newPolicy, newApproveBox = types.NewLegacyPolicy, types.NewLegacyApproveBox
+	}
+
+	return NewDesignStateValue(types.NewDesign(
+		design.Symbol(),
+		design.Name(),
+		design.Decimal(),
+		newPolicy(totalSupply, s.approveList(design.Policy().ApproveList(), newApproveBox)),
+	)), nil
+}
+
+func (s *DesignStateValueMerger) existingSupply() common.Big {
+	if s.existing == nil {
+		return common.ZeroBig
+	}
+
+	return s.existing.Policy().TotalSupply()
+}
+
+// mergeApproveList adds the allowance changes of l against the existing
+// approve list.
+func (s *DesignStateValueMerger) mergeApproveList(l []types.ApproveBox) {
+	add := func(owner, spender base.Address, amount common.Big) {
+		if amount.IsZero() {
+			return
+		}
+
+		k := approveDeltaKey(owner, spender)
+
+		if d, found := s.approves[k]; found {
+			amount = d.amount.Add(amount)
+		}
+
+		s.approves[k] = approveDelta{owner: owner, spender: spender, amount: amount}
+	}
+
+	founds := map[string]struct{}{}
+
+	for _, box := range l {
+		for _, info := range box.Approved() {
+			k := approveDeltaKey(box.Account(), info.Account())
+			founds[k] = struct{}{}
+
+			if existing, found := s.allowance[k]; found {
+				add(box.Account(), info.Account(), info.Amount().Sub(existing))
+			} else {
+				add(box.Account(), info.Account(), info.Amount())
+			}
+		}
+	}
+
+	if s.existing == nil {
+		return
+	}
+
+	for _, box := range s.existing.Policy().ApproveList() {
+		for _, info := range box.Approved() {
+			if _, found := founds[approveDeltaKey(box.Account(), info.Account())]; !found {
+				add(box.Account(), info.Account(), info.Amount().Neg())
+			}
+		}
+	}
+}
+
+// approveList applies the allowance changes to the existing approve list;
+// the allowance not over zero is removed. The new allowances are appended in
+// order of (owner, spender).
+func (s *DesignStateValueMerger) approveList(
+	existing []types.ApproveBox,
+	newApproveBox func(base.Address, []types.ApproveInfo) types.ApproveBox,
+) []types.ApproveBox {
+	pending := make(map[string]approveDelta, len(s.approves))
+	for k := range s.approves {
+		pending[k] = s.approves[k]
+	}
+
+	added := func(owner base.Address) []types.ApproveInfo {
+		var keys []string
+
+		for k := range pending {
+			if pending[k].owner.Equal(owner) {
+				keys = append(keys, k)
+			}
+		}
+
+		sort.Strings(keys)
+
+		var infos []types.ApproveInfo
+
+		for i := range keys {
+			if d := pending[keys[i]]; d.amount.OverZero() {
+				infos = append(infos, types.NewApproveInfo(d.spender, d.amount))
+			}
+
+			delete(pending, keys[i])
+		}
+
+		return infos
+	}
+
+	boxes := make([]types.ApproveBox, 0, len(existing))
+
+	for _, box := range existing {
+		var infos []types.ApproveInfo
+
+		for _, info := range box.Approved() {
+			amount := info.Amount()
+
+			k := approveDeltaKey(box.Account(), info.Account())
+			if d, found := pending[k]; found {
+				amount = amount.Add(d.amount)
+
+				delete(pending, k)
+			}
+
+			if amount.OverZero() {
+				infos = append(infos, types.NewApproveInfo(info.Account(), amount))
+			}
+		}
+
+		boxes = append(boxes, newApproveBox(box.Account(), append(infos, added(box.Account())...)))
+	}
+
+	for len(pending) > 0 {
+		var first string
+
+		for k := range pending {
+			if len(first) < 1 || k < first {
+				first = k
+			}
+		}
+
+		owner := pending[first].owner
+
+		if infos := added(owner); len(infos) > 0 {
+			boxes = append(boxes, newApproveBox(owner, infos))
+		}
+	}
+
+	return boxes
+}
+
+func approveDeltaKey(owner, spender base.Address) string {
+	return owner.String() + "/" + spender.String()
+}
@@ -0,0 +1,337 @@
+package state
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/imfact-labs/currency-model/common"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util/valuehash"
+	"github.com/imfact-labs/token-model/types"
+)
+
+const (
+	testOwner   = "owner"
+	testSpender = "spender"
+	testOther   = "other"
+)
+
+func newTestDesign(supply int64, boxes ...types.ApproveBox) types.Design {
+	return types.NewDesign("MCT", "token", common.NewBig(0), types.NewPolicy(common.NewBig(supply), boxes))
+}
+
+func newTestApproveBox(owner string, allowances map[string]int64) types.ApproveBox {
+	var infos []types.ApproveInfo
+
+	for spender, amount := range allowances {
+		infos = append(infos, types.NewApproveInfo(ctypes.NewStringAddress(spender), common.NewBig(amount)))
+	}
+
+	return types.NewApproveBox(ctypes.NewStringAddress(owner), infos)
+}
+
+// permutations returns every order of n values.
+func permutations(n int) [][]int {
+	if n < 1 {
+		return [][]int{{}}
+	}
+
+	var ps [][]int
+
+	for _, p := range permutations(n - 1) {
+		for i := 0; i <= len(p); i++ {
+			q := make([]int, 0, n)
+			q = append(q, p[:i]...)
+			q = append(q, n-1)
+			q = append(q, p[i:]...)
+
+			ps = append(ps, q)
+		}
+	}
+
+	return ps
+}
+
+func TestDesignStateValueMerger(t *testing.T) {
+	key := StateKeyDesign("contract")
+
+	existing := newTestDesign(100, newTestApproveBox(testOwner, map[string]int64{
+		testSpender: 10,
+		testOther:   5,
+	}))
+
+	cases := []struct {
+		name     string
+		existing *types.Design
+		values   []types.Design
+		expected types.Design
+		err      bool
+	}{
+		{
+			name: "register",
+			values: []types.Design{
+				newTestDesign(30),
+			},
+			expected: newTestDesign(30),
+		},
+		{
+			name:     "mint, burn, approve and transfer-from",
+			existing: &existing,
+			values: []types.Design{
+				// NOTE mint
+				newTestDesign(120, existing.Policy().ApproveList()...),
+				// NOTE burn
+				newTestDesign(90, existing.Policy().ApproveList()...),
+				// NOTE approve of new spender and the existing one
+				newTestDesign(100,
+					newTestApproveBox(testOwner, map[string]int64{testSpender: 13, testOther: 5}),
+					newTestApproveBox(testOther, map[string]int64{testOwner: 7}),
+				),
+				// NOTE transfer-from uses up the allowance
+				newTestDesign(100,
+					newTestApproveBox(testOwner, map[string]int64{testSpender: 10}),
+				),
+			},
+			expected: newTestDesign(110,
+				newTestApproveBox(testOwner, map[string]int64{testSpender: 13}),
+				newTestApproveBox(testOther, map[string]int64{testOwner: 7}),
+			),
+		},
+		{
+			name:     "allowance removed by approve and used by transfer-from",
+			existing: &existing,
+			values: []types.Design{
+				// NOTE approve of zero amount removes the allowance
+				newTestDesign(100,
+					newTestApproveBox(testOwner, map[string]int64{testOther: 5}),
+				),
+				newTestDesign(100,
+					newTestApproveBox(testOwner, map[string]int64{testSpender: 6, testOther: 5}),
+				),
+			},
+			expected: newTestDesign(100,
+				newTestApproveBox(testOwner, map[string]int64{testOther: 5}),
+			),
+		},
+		{
+			name:     "negative total supply",
+			existing: &existing,
+			values: []types.Design{
+				newTestDesign(0, existing.Policy().ApproveList()...),
+				newTestDesign(0, existing.Policy().ApproveList()...),
+			},
+			err: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var st base.State
+			if c.existing != nil {
+				st = common.NewBaseState(base.Height(1), key, NewDesignStateValue(*c.existing), nil, nil)
+			}
+
+			// NOTE the merged design does not depend on the order of merge.
+			for _, order := range permutations(len(c.values)) {
+				merger := NewDesignStateValueMerger(base.Height(2), key, st)
+
+				for _, i := range order {
+					if err := merger.Merge(NewDesignStateValue(c.values[i]), valuehash.RandomSHA256()); err != nil {
+						t.Fatalf("merge: %v", err)
+					}
+				}
+
+				nst, err := merger.CloseValue()
+
+				switch {
+				case c.err:
+					if err == nil {
+						t.Fatalf("order=%v: expected error, but nil", order)
+					}
+
+					continue
+				case err != nil:
+					t.Fatalf("order=%v: close: %v", order, err)
+				}
+
+				design, err := StateDesignValue(nst)
+				if err != nil {
+					t.Fatalf("order=%v: design: %v", order, err)
+				}
+
+				if !bytes.Equal(design.Bytes(), c.expected.Bytes()) {
+					t.Fatalf("order=%v: design not matched; expected=%v design=%v",
+						order, c.expected.Policy().ApproveList(), design.Policy().ApproveList())
+				}
+
+				if err := design.IsValid(nil); err != nil {
+					t.Fatalf("order=%v: invalid design: %v", order, err)
+				}
+			}
+		})
+	}
+}
+
+func TestTokenBalanceStateValueMerger(t *testing.T) {
+	key := StateKeyTokenBalance("contract", testOwner)
+
+	cases := []struct {
+		name     string
+		existing int64
+		values   []base.StateValue
+		expected int64
+		err      bool
+	}{
+		{
+			name:     "add and deduct",
+			existing: 10,
+			values:   []base.StateValue{NewAddTokenBalanceStateValue(common.NewBig(7)), NewDeductTokenBalanceStateValue(common.NewBig(15))},
+			expected: 2,
+		},
+		{
+			name:     "deducted to zero",
+			existing: 10,
+			values:   []base.StateValue{NewDeductTokenBalanceStateValue(common.NewBig(10))},
+			expected: 0,
+		},
+		{
+			name:     "negative balance",
+			existing: 10,
+			values:   []base.StateValue{NewAddTokenBalanceStateValue(common.NewBig(3)), NewDeductTokenBalanceStateValue(common.NewBig(15))},
+			err:      true,
+		},
+		{
+			name:   "unsupported value",
+			values: []base.StateValue{NewTokenBalanceStateValue(common.NewBig(3))},
+			err:    true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			st := common.NewBaseState(base.Height(1), key, NewTokenBalanceStateValue(common.NewBig(c.existing)), nil, nil)
+			merger := NewTokenBalanceStateValueMerger(base.Height(2), key, st)
+
+			var err error
+
+			for i := range c.values {
+				if err = merger.Merge(c.values[i], valuehash.RandomSHA256()); err != nil {
+					break
+				}
+			}
+
+			var nst base.State
+			if err == nil {
+				nst, err = merger.CloseValue()
+			}
+
+			switch {
+			case c.err:
+				if err == nil {
+					t.Fatal("expected error, but nil")
+				}
+
+				return
+			case err != nil:
+				t.Fatalf("close: %v", err)
+			}
+
+			amount, err := StateTokenBalanceValue(nst)
+			if err != nil {
+				t.Fatalf("token balance: %v", err)
+			}
+
+			if !amount.Equal(common.NewBig(c.expected)) {
+				t.Fatalf("token balance not matched; expected=%v amount=%v", c.expected, amount)
+			}
+		})
+	}
+}
+
+func TestDesignStateValueMergerLegacy(t *testing.T) {
+	key := StateKeyDesign("contract")
+
+	existing := types.NewDesign("MCT", "token", common.NewBig(0), types.NewLegacyPolicy(common.NewBig(100),
+		[]types.ApproveBox{
+			types.NewLegacyApproveBox(ctypes.NewStringAddress(testSpender), []types.ApproveInfo{
+				types.NewApproveInfo(ctypes.NewStringAddress(testOwner), common.NewBig(3)),
+			}),
+			types.NewLegacyApproveBox(ctypes.NewStringAddress(testOwner), []types.ApproveInfo{
+				types.NewApproveInfo(ctypes.NewStringAddress(testSpender), common.NewBig(10)),
+				types.NewApproveInfo(ctypes.NewStringAddress(testOther), common.NewBig(5)),
+			}),
+		},
+	))
+
+	st := common.NewBaseState(base.Height(1), key, NewDesignStateValue(existing), nil, nil)
+
+	accounts := func(l []types.ApproveBox) []string {
+		var s []string
+
+		for i := range l {
+			s = append(s, l[i].Account().String())
+
+			for _, info := range l[i].Approved() {
+				s = append(s, info.Account().String())
+			}
+		}
+
+		return s
+	}
+
+	merge := func(t *testing.T, policy types.Policy) types.Policy {
+		t.Helper()
+
+		merger := NewDesignStateValueMerger(base.Height(2), key, st)
+
+		// NOTE mint
+		if err := merger.Merge(NewDesignStateValue(types.NewDesign("MCT", "token", common.NewBig(0), policy)),
+			valuehash.RandomSHA256()); err != nil {
+			t.Fatalf("merge: %v", err)
+		}
+
+		nst, err := merger.CloseValue()
+		if err != nil {
+			t.Fatalf("close: %v", err)
+		}
+
+		design, err := StateDesignValue(nst)
+		if err != nil {
+			t.Fatalf("design: %v", err)
+		}
+
+		if err := design.IsValid(nil); err != nil {
+			t.Fatalf("invalid design: %v", err)
+		}
+
+		return design.Policy()
+	}
+
+	t.Run("legacy", func(t *testing.T) {
+		policy := merge(t, types.NewLegacyPolicy(common.NewBig(120), existing.Policy().ApproveList()))
+
+		if !policy.IsLegacy() {
+			t.Fatal("legacy policy migrated")
+		}
+
+		expected, got := accounts(existing.Policy().ApproveList()), accounts(policy.ApproveList())
+		if !reflect.DeepEqual(expected, got) {
+			t.Fatalf("order of legacy policy not kept; expected=%v got=%v", expected, got)
+		}
+	})
+
+	t.Run("canonical", func(t *testing.T) {
+		policy := merge(t, existing.Policy().Canonical())
+
+		if policy.IsLegacy() {
+			t.Fatal("legacy policy not migrated")
+		}
+
+		expected, got := accounts(existing.Policy().Canonical().ApproveList()), accounts(policy.ApproveList())
+		if !reflect.DeepEqual(expected, got) {
+			t.Fatalf("canonical policy not sorted; expected=%v got=%v", expected, got)
+		}
+	})
+}
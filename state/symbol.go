@@ -0,0 +1,112 @@
+package state
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/hint"
+	"github.com/imfact-labs/token-model/utils"
+	"github.com/pkg/errors"
+)
+
+var (
+	SymbolStateValueHint = hint.MustNewHint("mitum-token-symbol-state-value-v0.0.1")
+	SymbolSuffix         = "symbol"
+)
+
+// SymbolStateValue is the entry of the token symbol registry; the symbol is
+// held by the contract until the owner of contract releases it. The released
+// symbol can be registered again by the other contract.
+type SymbolStateValue struct {
+	hint.BaseHinter
+	contract base.Address
+	released bool
+}
+
+func NewSymbolStateValue(contract base.Address, released bool) SymbolStateValue {
+	return SymbolStateValue{
+		BaseHinter: hint.NewBaseHinter(SymbolStateValueHint),
+		contract:   contract,
+		released:   released,
+	}
+}
+
+func (s SymbolStateValue) IsValid([]byte) error {
+	e := util.ErrInvalid.Errorf("%s", utils.ErrStringInvalid(s))
+
+	if err := s.BaseHinter.IsValid(SymbolStateValueHint.Type().Bytes()); err != nil {
+		return e.Wrap(err)
+	}
+
+	if err := util.CheckIsValiders(nil, false, s.contract); err != nil {
+		return e.Wrap(err)
+	}
+
+	return nil
+}
+
+func (s SymbolStateValue) HashBytes() []byte {
+	return util.ConcatBytesSlice(
+		s.contract.Bytes(),
+		util.BoolToBytes(s.released),
+	)
+}
+
+func (s SymbolStateValue) Contract() base.Address {
+	return s.contract
+}
+
+func (s SymbolStateValue) Released() bool {
+	return s.released
+}
+
+func StateSymbolValue(st base.State) (*SymbolStateValue, error) {
+	e := util.ErrNotFound.Errorf("%s", ErrStringStateNotFound(st.Key()))
+
+	v := st.Value()
+	if v == nil {
+		return nil, e.Wrap(errors.Errorf("nil value"))
+	}
+
+	s, ok := v.(SymbolStateValue)
+	if !ok {
+		return nil, e.Wrap(errors.Errorf("%s", utils.ErrStringTypeCast(SymbolStateValue{}, v)))
+	}
+
+	return &s, nil
+}
+
+// SymbolHolder returns the contract, which holds the symbol; nil is returned
+// when the symbol is not registered or released.
+func SymbolHolder(symbol string, getStateFunc base.GetStateFunc) (base.Address, error) {
+	switch st, found, err := getStateFunc(StateKeySymbol(symbol)); {
+	case err != nil:
+		return nil, err
+	case !found:
+		return nil, nil
+	default:
+		v, err := StateSymbolValue(st)
+		if err != nil {
+			return nil, err
+		}
+
+		if v.Released() {
+			return nil, nil
+		}
+
+		return v.Contract(), nil
+	}
+}
+
+func IsStateSymbolKey(key string) bool {
+	return strings.HasPrefix(key, TokenPrefix) && strings.HasSuffix(key, ":"+SymbolSuffix)
+}
+
+// StateKeySymbol returns the key of symbol registry; the token symbol has only
+// upper case letters, so it does not collide with the contract address of the
+// other token states.
+func StateKeySymbol(symbol string) string {
+	return fmt.Sprintf("%s:%s:%s", TokenPrefix, symbol, SymbolSuffix)
+}
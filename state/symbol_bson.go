@@ -0,0 +1,52 @@
+package state
+
+import (
+	"github.com/imfact-labs/currency-model/utils/bsonenc"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/hint"
+	"github.com/imfact-labs/token-model/utils"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func (s SymbolStateValue) MarshalBSON() ([]byte, error) {
+	return bsonenc.Marshal(
+		bson.M{
+			"_hint":    s.Hint().String(),
+			"contract": s.contract,
+			"released": s.released,
+		},
+	)
+}
+
+type SymbolStateValueBSONUnmarshaler struct {
+	Hint     string `bson:"_hint"`
+	Contract string `bson:"contract"`
+	Released bool   `bson:"released"`
+}
+
+func (s *SymbolStateValue) DecodeBSON(b []byte, enc *bsonenc.Encoder) error {
+	e := util.StringError(utils.ErrStringDecodeBSON(*s))
+
+	var u SymbolStateValueBSONUnmarshaler
+	if err := enc.Unmarshal(b, &u); err != nil {
+		return e.Wrap(err)
+	}
+
+	ht, err := hint.ParseHint(u.Hint)
+	if err != nil {
+		return e.Wrap(err)
+	}
+
+	s.BaseHinter = hint.NewBaseHinter(ht)
+
+	contract, err := base.DecodeAddress(u.Contract, enc)
+	if err != nil {
+		return e.Wrap(err)
+	}
+
+	s.contract = contract
+	s.released = u.Released
+
+	return nil
+}
@@ -0,0 +1,47 @@
+package state
+
+import (
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/imfact-labs/mitum2/util/encoder"
+	"github.com/imfact-labs/mitum2/util/hint"
+	"github.com/imfact-labs/token-model/utils"
+)
+
+type SymbolStateValueJSONMarshaler struct {
+	hint.BaseHinter
+	Contract base.Address `json:"contract"`
+	Released bool         `json:"released"`
+}
+
+func (s SymbolStateValue) MarshalJSON() ([]byte, error) {
+	return util.MarshalJSON(SymbolStateValueJSONMarshaler{
+		BaseHinter: s.BaseHinter,
+		Contract:   s.contract,
+		Released:   s.released,
+	})
+}
+
+type SymbolStateValueJSONUnmarshaler struct {
+	Contract string `json:"contract"`
+	Released bool   `json:"released"`
+}
+
+func (s *SymbolStateValue) DecodeJSON(b []byte, enc encoder.Encoder) error {
+	e := util.StringError(utils.ErrStringDecodeJSON(*s))
+
+	var u SymbolStateValueJSONUnmarshaler
+	if err := enc.Unmarshal(b, &u); err != nil {
+		return e.Wrap(err)
+	}
+
+	contract, err := base.DecodeAddress(u.Contract, enc)
+	if err != nil {
+		return e.Wrap(err)
+	}
+
+	s.contract = contract
+	s.released = u.Released
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package state
+
+import (
+	"context"
+	"sync"
+
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/pkg/errors"
+)
+
+var SymbolReservationContextKey = util.ContextKey("token-symbol-reservation")
+
+// SymbolReservation keeps the token symbols registered by the operations of
+// one proposal. The symbol registry states of proposal are not seen by the
+// other operations of same proposal, so the operation, which registers the
+// symbol already reserved by the former operations, is rejected in the same
+// way by every node.
+type SymbolReservation struct {
+	reserved map[string]struct{}
+	sync.Mutex
+}
+
+func NewSymbolReservation() *SymbolReservation {
+	return &SymbolReservation{
+		reserved: map[string]struct{}{},
+	}
+}
+
+func (r *SymbolReservation) Reserve(symbol string) error {
+	r.Lock()
+	defer r.Unlock()
+
+	if _, found := r.reserved[symbol]; found {
+		return errors.Errorf("token symbol, %v is already reserved in proposal", symbol)
+	}
+
+	r.reserved[symbol] = struct{}{}
+
+	return nil
+}
+
+// ReserveSymbol reserves the symbol with the SymbolReservation of context;
+// without reservation, like simulation, it does nothing.
+func ReserveSymbol(ctx context.Context, symbol string) error {
+	r, ok := ctx.Value(SymbolReservationContextKey).(*SymbolReservation)
+	if !ok || r == nil {
+		return nil
+	}
+
+	return r.Reserve(symbol)
+}
@@ -0,0 +1,94 @@
+package state
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/imfact-labs/currency-model/common"
+	"github.com/imfact-labs/mitum2/base"
+	"github.com/imfact-labs/mitum2/util"
+	"github.com/pkg/errors"
+)
+
+var TokenBalanceReservationContextKey = util.ContextKey("token-balance-reservation")
+
+// TokenBalanceReservation keeps the token balances spent by the operations of
+// one proposal. The operations are preprocessed in the order of proposal, so
+// the operation, which spends the balance already reserved by the former
+// operations, is rejected in the same way by every node.
+type TokenBalanceReservation struct {
+	reserved map[string]common.Big
+	sync.Mutex
+}
+
+func NewTokenBalanceReservation() *TokenBalanceReservation {
+	return &TokenBalanceReservation{
+		reserved: map[string]common.Big{},
+	}
+}
+
+// Reserve reserves the amounts by token balance key; when any balance is not
+// enough, nothing is reserved.
+func (r *TokenBalanceReservation) Reserve(amounts map[string]common.Big, getStateFunc base.GetStateFunc) error {
+	r.Lock()
+	defer r.Unlock()
+
+	keys := make([]string, 0, len(amounts))
+	for key := range amounts {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for i := range keys {
+		key := keys[i]
+
+		reserved, found := r.reserved[key]
+		if !found {
+			continue
+		}
+
+		balance := common.ZeroBig
+
+		switch st, found, err := getStateFunc(key); {
+		case err != nil:
+			return err
+		case found:
+			b, err := StateTokenBalanceValue(st)
+			if err != nil {
+				return err
+			}
+
+			balance = b
+		}
+
+		if spendable := balance.Sub(reserved); spendable.Compare(amounts[key]) < 0 {
+			return errors.Errorf(
+				"token balance, %v is already reserved in proposal; balance=%v reserved=%v amount=%v",
+				key, balance, reserved, amounts[key])
+		}
+	}
+
+	for i := range keys {
+		reserved, found := r.reserved[keys[i]]
+		if !found {
+			reserved = common.ZeroBig
+		}
+
+		r.reserved[keys[i]] = reserved.Add(amounts[keys[i]])
+	}
+
+	return nil
+}
+
+// ReserveTokenBalances reserves the amounts with the TokenBalanceReservation
+// of context; without reservation, like simulation, it does nothing.
+func ReserveTokenBalances(ctx context.Context, amounts map[string]common.Big, getStateFunc base.GetStateFunc) error {
+	r, ok := ctx.Value(TokenBalanceReservationContextKey).(*TokenBalanceReservation)
+	if !ok || r == nil {
+		return nil
+	}
+
+	return r.Reserve(amounts, getStateFunc)
+}
@@ -1,6 +1,8 @@
 package types
 
 import (
+	"sort"
+
 	"github.com/imfact-labs/currency-model/common"
 	"github.com/imfact-labs/mitum2/base"
 	"github.com/imfact-labs/mitum2/util"
@@ -9,7 +11,14 @@ import (
 	"github.com/pkg/errors"
 )
 
-var ApproveBoxHint = hint.MustNewHint("mitum-token-approve-box-v0.0.1")
+var (
+	// ApproveBoxHint v0.0.2 keeps the approved sorted by account. The
+	// ApproveBox of LegacyApproveBoxHint is decoded and validated as stored
+	// and keeps the order of approved when it is changed; it is migrated by
+	// Canonical with the policy.
+	ApproveBoxHint       = hint.MustNewHint("mitum-token-approve-box-v0.0.2")
+	LegacyApproveBoxHint = hint.MustNewHint("mitum-token-approve-box-v0.0.1")
+)
 
 type ApproveBox struct {
 	hint.BaseHinter
@@ -21,6 +30,16 @@ func NewApproveBox(account base.Address, approved []ApproveInfo) ApproveBox {
 	return ApproveBox{
 		BaseHinter: hint.NewBaseHinter(ApproveBoxHint),
 		account:    account,
+		approved:   sortApproveInfos(approved),
+	}
+}
+
+// NewLegacyApproveBox returns the approve box of LegacyApproveBoxHint, which
+// keeps the approved as it is.
+func NewLegacyApproveBox(account base.Address, approved []ApproveInfo) ApproveBox {
+	return ApproveBox{
+		BaseHinter: hint.NewBaseHinter(LegacyApproveBoxHint),
+		account:    account,
 		approved:   approved,
 	}
 }
@@ -45,9 +64,47 @@ func (a ApproveBox) IsValid([]byte) error {
 		}
 	}
 
+	if a.IsLegacy() {
+		return nil
+	}
+
+	for i := 1; i < len(a.approved); i++ {
+		if addressString(a.approved[i-1].Account()) > addressString(a.approved[i].Account()) {
+			return e.Wrap(common.ErrValueInvalid.Wrap(
+				errors.Errorf("approved not sorted by account, %v", a.approved[i].Account())))
+		}
+	}
+
 	return nil
 }
 
+// IsLegacy returns true when the approve box is decoded from
+// LegacyApproveBoxHint, which does not keep the order of approved.
+func (a ApproveBox) IsLegacy() bool {
+	return a.Hint().Equal(LegacyApproveBoxHint)
+}
+
+// Canonical returns the approve box of ApproveBoxHint with the approved sorted
+// by account.
+func (a ApproveBox) Canonical() ApproveBox {
+	return NewApproveBox(a.account, a.approved)
+}
+
+// Legacy returns the approve box of LegacyApproveBoxHint; the order of
+// approved is kept.
+func (a ApproveBox) Legacy() ApproveBox {
+	return NewLegacyApproveBox(a.account, a.approved)
+}
+
+// withApproved returns the approve box of same hint with the approved.
+func (a ApproveBox) withApproved(approved []ApproveInfo) ApproveBox {
+	if a.IsLegacy() {
+		return NewLegacyApproveBox(a.account, approved)
+	}
+
+	return NewApproveBox(a.account, approved)
+}
+
 func (a ApproveBox) Bytes() []byte {
 	bs := make([][]byte, len(a.approved)+1)
 	for i := range a.approved {
@@ -82,7 +139,7 @@ func (a *ApproveBox) RemoveApproveInfo(ad base.Address) error {
 			nApproved = append(nApproved, a.approved[:i]...)
 			nApproved = append(nApproved, a.approved[i+1:]...)
 
-			a.approved = nApproved
+			*a = a.withApproved(nApproved)
 			return nil
 		}
 		if i == len(a.approved)-1 {
@@ -98,7 +155,7 @@ func (a *ApproveBox) SetApproveInfo(ap ApproveInfo) {
 	var count int
 	if len(a.approved) < 1 {
 		approved = append(approved, ap)
-		a.approved = approved
+		*a = a.withApproved(approved)
 		return
 	}
 	for i := range a.approved {
@@ -113,6 +170,29 @@ func (a *ApproveBox) SetApproveInfo(ap ApproveInfo) {
 		approved = append(approved, ap)
 	}
 
-	a.approved = approved
+	*a = a.withApproved(approved)
 	return
 }
+
+func sortApproveInfos(l []ApproveInfo) []ApproveInfo {
+	if l == nil {
+		return nil
+	}
+
+	s := make([]ApproveInfo, len(l))
+	copy(s, l)
+
+	sort.SliceStable(s, func(i, j int) bool {
+		return addressString(s[i].Account()) < addressString(s[j].Account())
+	})
+
+	return s
+}
+
+func addressString(a base.Address) string {
+	if a == nil {
+		return ""
+	}
+
+	return a.String()
+}
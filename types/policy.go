@@ -12,7 +12,15 @@ import (
 	"github.com/pkg/errors"
 )
 
-var PolicyHint = hint.MustNewHint("mitum-token-policy-v0.0.1")
+var (
+	// PolicyHint v0.0.2 keeps the approve list sorted by account and every
+	// approve box canonical. The Policy of LegacyPolicyHint is decoded and
+	// validated as stored and keeps the order of approve list when it is
+	// changed; it is migrated by Canonical from the height, which the network
+	// agrees on.
+	PolicyHint       = hint.MustNewHint("mitum-token-policy-v0.0.2")
+	LegacyPolicyHint = hint.MustNewHint("mitum-token-policy-v0.0.1")
+)
 
 type Policy struct {
 	hint.BaseHinter
@@ -24,6 +32,17 @@ func NewPolicy(totalSupply common.Big, approveList []ApproveBox) Policy {
 	return Policy{
 		BaseHinter:  hint.NewBaseHinter(PolicyHint),
 		totalSupply: totalSupply,
+		approveList: sortApproveBoxes(approveList),
+	}
+}
+
+// NewLegacyPolicy returns the policy of LegacyPolicyHint, which keeps the
+// approve list as it is, like the policy written before the canonical approve
+// list.
+func NewLegacyPolicy(totalSupply common.Big, approveList []ApproveBox) Policy {
+	return Policy{
+		BaseHinter:  hint.NewBaseHinter(LegacyPolicyHint),
+		totalSupply: totalSupply,
 		approveList: approveList,
 	}
 }
@@ -52,9 +71,60 @@ func (p Policy) IsValid([]byte) error {
 		return e.Wrap(errors.Errorf("nil big"))
 	}
 
+	if p.IsLegacy() {
+		return nil
+	}
+
+	for i := range p.approveList {
+		if p.approveList[i].IsLegacy() {
+			return e.Wrap(common.ErrValueInvalid.Wrap(
+				errors.Errorf("legacy approve box, %v", p.approveList[i].Account())))
+		}
+
+		if i > 0 && addressString(p.approveList[i-1].Account()) > addressString(p.approveList[i].Account()) {
+			return e.Wrap(common.ErrValueInvalid.Wrap(
+				errors.Errorf("approve list not sorted by account, %v", p.approveList[i].Account())))
+		}
+	}
+
 	return nil
 }
 
+// IsLegacy returns true when the policy is decoded from LegacyPolicyHint,
+// which does not keep the order of approve list.
+func (p Policy) IsLegacy() bool {
+	return p.Hint().Equal(LegacyPolicyHint)
+}
+
+// Canonical returns the policy of PolicyHint with the canonical approve boxes
+// sorted by account.
+func (p Policy) Canonical() Policy {
+	return NewPolicy(p.totalSupply, p.approveList)
+}
+
+// Legacy returns the policy of LegacyPolicyHint with the legacy approve boxes;
+// the order of approve list is kept.
+func (p Policy) Legacy() Policy {
+	var l []ApproveBox
+	if p.approveList != nil {
+		l = make([]ApproveBox, len(p.approveList))
+		for i := range p.approveList {
+			l[i] = p.approveList[i].Legacy()
+		}
+	}
+
+	return NewLegacyPolicy(p.totalSupply, l)
+}
+
+// withApproveList returns the policy of same hint with the approve list.
+func (p Policy) withApproveList(approveList []ApproveBox) Policy {
+	if p.IsLegacy() {
+		return NewLegacyPolicy(p.totalSupply, approveList)
+	}
+
+	return NewPolicy(p.totalSupply, approveList)
+}
+
 func (p Policy) Bytes() []byte {
 	b := make([][]byte, len(p.approveList))
 	for i, a := range p.approveList {
@@ -110,22 +180,42 @@ func (p *Policy) MergeApproveBox(napb ApproveBox) {
 	} else {
 		approvedList = append(approvedList, napb)
 	}
-	p.approveList = approvedList
+	*p = p.withApproveList(approvedList)
 }
 
 func (p *Policy) RemoveApproveBox(acc base.Address) {
 	var approvedList []ApproveBox
 
 	idx := -1
-	for i, apb := range approvedList {
+	for i, apb := range p.approveList {
 		if apb.Account().Equal(acc) {
 			idx = i
 			break
 		}
 	}
-	if -1 < idx {
-		approvedList = append(approvedList, p.approveList[:idx]...)
-		approvedList = append(approvedList, p.approveList[idx+1:]...)
+	if idx < 0 {
+		return
+	}
+
+	approvedList = append(approvedList, p.approveList[:idx]...)
+	approvedList = append(approvedList, p.approveList[idx+1:]...)
+
+	*p = p.withApproveList(approvedList)
+}
+
+func sortApproveBoxes(l []ApproveBox) []ApproveBox {
+	if l == nil {
+		return nil
 	}
-	p.approveList = approvedList
+
+	s := make([]ApproveBox, len(l))
+	for i := range l {
+		s[i] = l[i].Canonical()
+	}
+
+	sort.SliceStable(s, func(i, j int) bool {
+		return addressString(s[i].Account()) < addressString(s[j].Account())
+	})
+
+	return s
 }
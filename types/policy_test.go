@@ -0,0 +1,182 @@
+package types
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/imfact-labs/currency-model/common"
+	ctypes "github.com/imfact-labs/currency-model/types"
+	"github.com/imfact-labs/mitum2/util/hint"
+)
+
+func newTestApproveInfos(accounts ...string) []ApproveInfo {
+	infos := make([]ApproveInfo, len(accounts))
+	for i := range accounts {
+		infos[i] = NewApproveInfo(ctypes.NewStringAddress(accounts[i]), common.NewBig(int64(i+1)))
+	}
+
+	return infos
+}
+
+func testAccounts(names ...string) []string {
+	accounts := make([]string, len(names))
+	for i := range names {
+		accounts[i] = ctypes.NewStringAddress(names[i]).String()
+	}
+
+	return accounts
+}
+
+func testApproveListAccounts(l []ApproveBox) []string {
+	var accounts []string
+
+	for i := range l {
+		accounts = append(accounts, l[i].Account().String())
+
+		for _, info := range l[i].Approved() {
+			accounts = append(accounts, info.Account().String())
+		}
+	}
+
+	return accounts
+}
+
+func TestPolicyIsValid(t *testing.T) {
+	unsortedBox := NewLegacyApproveBox(ctypes.NewStringAddress("ownerb"), newTestApproveInfos("spenderb", "spendera"))
+	sortedBox := NewApproveBox(ctypes.NewStringAddress("ownera"), newTestApproveInfos("spenderb", "spendera"))
+
+	cases := []struct {
+		name   string
+		policy Policy
+		err    bool
+	}{
+		{
+			name:   "canonical",
+			policy: NewPolicy(common.NewBig(10), []ApproveBox{unsortedBox, sortedBox}),
+		},
+		{
+			name:   "legacy not sorted",
+			policy: NewLegacyPolicy(common.NewBig(10), []ApproveBox{unsortedBox, sortedBox}),
+		},
+		{
+			name: "approve list not sorted",
+			policy: Policy{
+				BaseHinter:  hint.NewBaseHinter(PolicyHint),
+				totalSupply: common.NewBig(10),
+				approveList: []ApproveBox{unsortedBox.Canonical(), sortedBox},
+			},
+			err: true,
+		},
+		{
+			name: "legacy approve box",
+			policy: Policy{
+				BaseHinter:  hint.NewBaseHinter(PolicyHint),
+				totalSupply: common.NewBig(10),
+				approveList: []ApproveBox{sortedBox, unsortedBox},
+			},
+			err: true,
+		},
+		{
+			name: "approved not sorted",
+			policy: Policy{
+				BaseHinter:  hint.NewBaseHinter(PolicyHint),
+				totalSupply: common.NewBig(10),
+				approveList: []ApproveBox{{
+					BaseHinter: hint.NewBaseHinter(ApproveBoxHint),
+					account:    ctypes.NewStringAddress("ownera"),
+					approved:   newTestApproveInfos("spenderb", "spendera"),
+				}},
+			},
+			err: true,
+		},
+		{
+			name:   "duplicated account",
+			policy: NewLegacyPolicy(common.NewBig(10), []ApproveBox{sortedBox, sortedBox}),
+			err:    true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.policy.IsValid(nil)
+
+			switch {
+			case c.err && err == nil:
+				t.Fatal("expected error, but nil")
+			case !c.err && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestPolicyCanonical(t *testing.T) {
+	legacy := NewLegacyPolicy(common.NewBig(10), []ApproveBox{
+		NewLegacyApproveBox(ctypes.NewStringAddress("ownerb"), newTestApproveInfos("spenderb", "spendera")),
+		NewLegacyApproveBox(ctypes.NewStringAddress("ownera"), newTestApproveInfos("spenderc")),
+	})
+
+	policy := legacy.Canonical()
+
+	if policy.IsLegacy() {
+		t.Fatalf("expected canonical policy, not %v", policy.Hint())
+	}
+
+	for i := range policy.ApproveList() {
+		if policy.ApproveList()[i].IsLegacy() {
+			t.Fatalf("expected canonical approve box, not %v", policy.ApproveList()[i].Hint())
+		}
+	}
+
+	expected := testAccounts("ownera", "spenderc", "ownerb", "spendera", "spenderb")
+	if got := testApproveListAccounts(policy.ApproveList()); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("canonical policy not sorted; expected=%v got=%v", expected, got)
+	}
+
+	if err := policy.IsValid(nil); err != nil {
+		t.Fatalf("invalid canonical policy: %v", err)
+	}
+
+	if again := policy.Canonical(); !reflect.DeepEqual(again.Bytes(), policy.Bytes()) {
+		t.Fatal("canonical of canonical policy changed")
+	}
+
+	if !legacy.TotalSupply().Equal(policy.TotalSupply()) {
+		t.Fatalf("total supply changed; expected=%v got=%v", legacy.TotalSupply(), policy.TotalSupply())
+	}
+
+	// NOTE Legacy keeps the order, so the canonical policy stays sorted.
+	if got := testApproveListAccounts(policy.Legacy().ApproveList()); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("order of legacy policy not kept; expected=%v got=%v", expected, got)
+	}
+}
+
+func TestPolicyLegacyMutation(t *testing.T) {
+	policy := NewLegacyPolicy(common.NewBig(10), []ApproveBox{
+		NewLegacyApproveBox(ctypes.NewStringAddress("ownerb"), newTestApproveInfos("spenderb")),
+	})
+
+	box := NewLegacyApproveBox(ctypes.NewStringAddress("ownera"), newTestApproveInfos("spenderb"))
+	box.SetApproveInfo(NewApproveInfo(ctypes.NewStringAddress("spendera"), common.NewBig(3)))
+
+	policy.MergeApproveBox(box)
+
+	if !policy.IsLegacy() || !policy.ApproveList()[1].IsLegacy() {
+		t.Fatal("legacy policy migrated by mutation")
+	}
+
+	expected := testAccounts("ownerb", "spenderb", "ownera", "spenderb", "spendera")
+	if got := testApproveListAccounts(policy.ApproveList()); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("order of legacy policy not kept; expected=%v got=%v", expected, got)
+	}
+
+	if err := policy.IsValid(nil); err != nil {
+		t.Fatalf("invalid legacy policy: %v", err)
+	}
+
+	policy.RemoveApproveBox(ctypes.NewStringAddress("ownerb"))
+
+	if !policy.IsLegacy() || len(policy.ApproveList()) != 1 {
+		t.Fatalf("legacy approve box not removed; %v", testApproveListAccounts(policy.ApproveList()))
+	}
+}